@@ -3,8 +3,20 @@ set -euo pipefail
 
 echo "[run.sh] Starting service"
 
-echo "[run.sh] Running DB migrations"
-goose -dir ./db/migrations postgres "${DATABASE_URL}" up
+case "${DATABASE_URL}" in
+  memory://*)
+    echo "[run.sh] In-memory storage, skipping DB migrations"
+    ;;
+  sqlite://*)
+    echo "[run.sh] Running SQLite migrations"
+    SQLITE_PATH="${DATABASE_URL#sqlite://}"
+    goose -dir ./db/migrations/sqlite sqlite3 "${SQLITE_PATH%%\?*}" up
+    ;;
+  *)
+    echo "[run.sh] Running DB migrations"
+    goose -dir ./db/migrations postgres "${DATABASE_URL}" up
+    ;;
+esac
 
 echo "[run.sh] Starting Caddy"
 caddy run --config /etc/caddy/Caddyfile &
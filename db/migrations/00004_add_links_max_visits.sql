@@ -0,0 +1,11 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE links ADD COLUMN IF NOT EXISTS max_visits INTEGER NULL;
+ALTER TABLE links ADD COLUMN IF NOT EXISTS visit_count BIGINT NOT NULL DEFAULT 0;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE links DROP COLUMN IF EXISTS visit_count;
+ALTER TABLE links DROP COLUMN IF EXISTS max_visits;
+-- +goose StatementEnd
@@ -0,0 +1,11 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE links ADD COLUMN IF NOT EXISTS redirect_type INTEGER NOT NULL DEFAULT 302;
+ALTER TABLE links ADD CONSTRAINT links_redirect_type_check CHECK (redirect_type IN (301, 302, 307, 308));
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE links DROP CONSTRAINT IF EXISTS links_redirect_type_check;
+ALTER TABLE links DROP COLUMN IF EXISTS redirect_type;
+-- +goose StatementEnd
@@ -0,0 +1,17 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE link_visits ADD COLUMN IF NOT EXISTS browser TEXT NOT NULL DEFAULT '';
+ALTER TABLE link_visits ADD COLUMN IF NOT EXISTS browser_version TEXT NOT NULL DEFAULT '';
+ALTER TABLE link_visits ADD COLUMN IF NOT EXISTS os TEXT NOT NULL DEFAULT '';
+ALTER TABLE link_visits ADD COLUMN IF NOT EXISTS device TEXT NOT NULL DEFAULT '';
+ALTER TABLE link_visits ADD COLUMN IF NOT EXISTS is_bot BOOLEAN NOT NULL DEFAULT FALSE;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE link_visits DROP COLUMN IF EXISTS is_bot;
+ALTER TABLE link_visits DROP COLUMN IF EXISTS device;
+ALTER TABLE link_visits DROP COLUMN IF EXISTS os;
+ALTER TABLE link_visits DROP COLUMN IF EXISTS browser_version;
+ALTER TABLE link_visits DROP COLUMN IF EXISTS browser;
+-- +goose StatementEnd
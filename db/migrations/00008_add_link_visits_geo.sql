@@ -0,0 +1,13 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE link_visits ADD COLUMN IF NOT EXISTS country TEXT NOT NULL DEFAULT '';
+ALTER TABLE link_visits ADD COLUMN IF NOT EXISTS region TEXT NOT NULL DEFAULT '';
+ALTER TABLE link_visits ADD COLUMN IF NOT EXISTS city TEXT NOT NULL DEFAULT '';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE link_visits DROP COLUMN IF EXISTS city;
+ALTER TABLE link_visits DROP COLUMN IF EXISTS region;
+ALTER TABLE link_visits DROP COLUMN IF EXISTS country;
+-- +goose StatementEnd
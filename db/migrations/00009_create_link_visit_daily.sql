@@ -0,0 +1,23 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS link_visit_daily (
+    link_id BIGINT NOT NULL REFERENCES links(id) ON DELETE CASCADE,
+    day DATE NOT NULL,
+    status INT NOT NULL,
+    is_bot BOOLEAN NOT NULL,
+    referer_host TEXT NOT NULL,
+    visits BIGINT NOT NULL,
+    PRIMARY KEY (link_id, day, status, is_bot, referer_host)
+);
+
+CREATE TABLE IF NOT EXISTS link_visit_rollup_state (
+    id INT PRIMARY KEY CHECK (id = 1),
+    rolled_until DATE NOT NULL
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS link_visit_rollup_state;
+DROP TABLE IF EXISTS link_visit_daily;
+-- +goose StatementEnd
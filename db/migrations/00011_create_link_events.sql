@@ -0,0 +1,28 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS link_events (
+    id BIGSERIAL PRIMARY KEY,
+    link_id BIGINT NOT NULL REFERENCES links(id) ON DELETE CASCADE,
+    type TEXT NOT NULL CHECK (type IN ('created', 'updated', 'deleted', 'restored')),
+    old_original_url TEXT NOT NULL DEFAULT '',
+    new_original_url TEXT NOT NULL DEFAULT '',
+    old_short_name TEXT NOT NULL DEFAULT '',
+    new_short_name TEXT NOT NULL DEFAULT '',
+    actor TEXT NOT NULL DEFAULT '',
+    request_id TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS link_events_link_id_idx ON link_events(link_id, id);
+
+INSERT INTO link_events (link_id, type, new_original_url, new_short_name, created_at)
+SELECT id, 'created', original_url, short_name, created_at FROM links;
+
+INSERT INTO link_events (link_id, type, old_original_url, old_short_name, created_at)
+SELECT id, 'deleted', original_url, short_name, deleted_at FROM links WHERE deleted_at IS NOT NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS link_events;
+-- +goose StatementEnd
@@ -0,0 +1,18 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE link_events DROP CONSTRAINT IF EXISTS link_events_link_id_fkey;
+ALTER TABLE link_events DROP CONSTRAINT IF EXISTS link_events_type_check;
+ALTER TABLE link_events ADD CONSTRAINT link_events_type_check
+    CHECK (type IN ('created', 'updated', 'deleted', 'restored', 'purged'));
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DELETE FROM link_events WHERE link_id NOT IN (SELECT id FROM links);
+DELETE FROM link_events WHERE type = 'purged';
+ALTER TABLE link_events DROP CONSTRAINT IF EXISTS link_events_type_check;
+ALTER TABLE link_events ADD CONSTRAINT link_events_type_check
+    CHECK (type IN ('created', 'updated', 'deleted', 'restored'));
+ALTER TABLE link_events ADD CONSTRAINT link_events_link_id_fkey
+    FOREIGN KEY (link_id) REFERENCES links(id) ON DELETE CASCADE;
+-- +goose StatementEnd
@@ -0,0 +1,20 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS link_visits (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    link_id INTEGER NOT NULL REFERENCES links(id) ON DELETE CASCADE,
+    ip TEXT NOT NULL,
+    user_agent TEXT NOT NULL,
+    referer TEXT NOT NULL DEFAULT '',
+    status INTEGER NOT NULL,
+    created_at DATETIME NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f', 'now'))
+);
+
+CREATE INDEX IF NOT EXISTS link_visits_link_id_idx ON link_visits(link_id);
+CREATE INDEX IF NOT EXISTS link_visits_created_at_idx ON link_visits(created_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS link_visits;
+-- +goose StatementEnd
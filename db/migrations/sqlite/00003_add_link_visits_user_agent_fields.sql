@@ -0,0 +1,17 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE link_visits ADD COLUMN browser TEXT NOT NULL DEFAULT '';
+ALTER TABLE link_visits ADD COLUMN browser_version TEXT NOT NULL DEFAULT '';
+ALTER TABLE link_visits ADD COLUMN os TEXT NOT NULL DEFAULT '';
+ALTER TABLE link_visits ADD COLUMN device TEXT NOT NULL DEFAULT '';
+ALTER TABLE link_visits ADD COLUMN is_bot BOOLEAN NOT NULL DEFAULT FALSE;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE link_visits DROP COLUMN is_bot;
+ALTER TABLE link_visits DROP COLUMN device;
+ALTER TABLE link_visits DROP COLUMN os;
+ALTER TABLE link_visits DROP COLUMN browser_version;
+ALTER TABLE link_visits DROP COLUMN browser;
+-- +goose StatementEnd
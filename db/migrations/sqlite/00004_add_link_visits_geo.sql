@@ -0,0 +1,13 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE link_visits ADD COLUMN country TEXT NOT NULL DEFAULT '';
+ALTER TABLE link_visits ADD COLUMN region TEXT NOT NULL DEFAULT '';
+ALTER TABLE link_visits ADD COLUMN city TEXT NOT NULL DEFAULT '';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE link_visits DROP COLUMN city;
+ALTER TABLE link_visits DROP COLUMN region;
+ALTER TABLE link_visits DROP COLUMN country;
+-- +goose StatementEnd
@@ -0,0 +1,11 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE links ADD COLUMN deleted_at DATETIME NULL;
+CREATE INDEX IF NOT EXISTS links_deleted_at_idx ON links(deleted_at) WHERE deleted_at IS NOT NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS links_deleted_at_idx;
+ALTER TABLE links DROP COLUMN deleted_at;
+-- +goose StatementEnd
@@ -0,0 +1,45 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE link_events_new (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    link_id INTEGER NOT NULL,
+    type TEXT NOT NULL CHECK (type IN ('created', 'updated', 'deleted', 'restored', 'purged')),
+    old_original_url TEXT NOT NULL DEFAULT '',
+    new_original_url TEXT NOT NULL DEFAULT '',
+    old_short_name TEXT NOT NULL DEFAULT '',
+    new_short_name TEXT NOT NULL DEFAULT '',
+    actor TEXT NOT NULL DEFAULT '',
+    request_id TEXT NOT NULL DEFAULT '',
+    created_at DATETIME NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f', 'now'))
+);
+
+INSERT INTO link_events_new SELECT * FROM link_events;
+DROP TABLE link_events;
+ALTER TABLE link_events_new RENAME TO link_events;
+
+CREATE INDEX IF NOT EXISTS link_events_link_id_idx ON link_events(link_id, id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+CREATE TABLE link_events_old (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    link_id INTEGER NOT NULL REFERENCES links(id) ON DELETE CASCADE,
+    type TEXT NOT NULL CHECK (type IN ('created', 'updated', 'deleted', 'restored')),
+    old_original_url TEXT NOT NULL DEFAULT '',
+    new_original_url TEXT NOT NULL DEFAULT '',
+    old_short_name TEXT NOT NULL DEFAULT '',
+    new_short_name TEXT NOT NULL DEFAULT '',
+    actor TEXT NOT NULL DEFAULT '',
+    request_id TEXT NOT NULL DEFAULT '',
+    created_at DATETIME NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f', 'now'))
+);
+
+INSERT INTO link_events_old
+SELECT * FROM link_events
+WHERE type <> 'purged' AND link_id IN (SELECT id FROM links);
+DROP TABLE link_events;
+ALTER TABLE link_events_old RENAME TO link_events;
+
+CREATE INDEX IF NOT EXISTS link_events_link_id_idx ON link_events(link_id, id);
+-- +goose StatementEnd
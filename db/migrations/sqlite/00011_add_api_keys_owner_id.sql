@@ -0,0 +1,11 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE api_keys ADD COLUMN owner_id INTEGER NULL REFERENCES users(id) ON DELETE CASCADE;
+CREATE INDEX IF NOT EXISTS api_keys_owner_id_idx ON api_keys(owner_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS api_keys_owner_id_idx;
+ALTER TABLE api_keys DROP COLUMN owner_id;
+-- +goose StatementEnd
@@ -0,0 +1,9 @@
+-- name: CreateLinkEvent :exec
+INSERT INTO link_events (link_id, type, old_original_url, new_original_url, old_short_name, new_short_name, actor, request_id)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8);
+
+-- name: ListLinkEvents :many
+SELECT id, link_id, type, old_original_url, new_original_url, old_short_name, new_short_name, actor, request_id, created_at
+FROM link_events
+WHERE link_id = $1
+ORDER BY id;
@@ -0,0 +1,23 @@
+-- name: UpsertLinkVisitDaily :exec
+INSERT INTO link_visit_daily (link_id, day, status, is_bot, referer_host, visits)
+VALUES ($1, $2, $3, $4, $5, $6)
+ON CONFLICT (link_id, day, status, is_bot, referer_host) DO UPDATE
+SET visits = EXCLUDED.visits;
+
+-- name: ListLinkVisitDaily :many
+SELECT link_id, day, status, is_bot, referer_host, visits
+FROM link_visit_daily
+WHERE link_id = sqlc.arg(link_id)
+  AND day >= sqlc.arg(from_day)
+  AND day < sqlc.arg(to_day)
+ORDER BY day;
+
+-- name: GetLinkVisitRolledUntil :one
+SELECT rolled_until FROM link_visit_rollup_state
+WHERE id = 1;
+
+-- name: SetLinkVisitRolledUntil :exec
+INSERT INTO link_visit_rollup_state (id, rolled_until)
+VALUES (1, $1)
+ON CONFLICT (id) DO UPDATE
+SET rolled_until = GREATEST(link_visit_rollup_state.rolled_until, EXCLUDED.rolled_until);
@@ -1,7 +1,7 @@
 -- name: CreateLinkVisit :one
-INSERT INTO link_visits (link_id, ip, user_agent, referer, status)
-VALUES ($1, $2, $3, $4, $5)
-RETURNING id, link_id, ip, user_agent, referer, status, created_at;
+INSERT INTO link_visits (link_id, ip, user_agent, referer, status, browser, browser_version, os, device, is_bot, country, region, city)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+RETURNING id, link_id, ip, user_agent, referer, status, created_at, browser, browser_version, os, device, is_bot, country, region, city;
 
 -- name: ListLinkVisitsWithRange :many
 SELECT
@@ -11,11 +11,202 @@ SELECT
   user_agent,
   referer,
   status,
-  created_at
+  created_at,
+  browser,
+  browser_version,
+  os,
+  device,
+  is_bot,
+  country,
+  region,
+  city
 FROM link_visits
+WHERE (sqlc.narg(link_id)::bigint IS NULL OR link_id = sqlc.narg(link_id))
+  AND (sqlc.narg(from_time)::timestamptz IS NULL OR created_at >= sqlc.narg(from_time))
+  AND (sqlc.narg(to_time)::timestamptz IS NULL OR created_at < sqlc.narg(to_time))
+  AND (sqlc.narg(status)::int IS NULL OR status = sqlc.narg(status))
+  AND (sqlc.narg(ip)::text IS NULL OR ip ILIKE '%' || sqlc.narg(ip) || '%' ESCAPE '\')
+  AND (sqlc.narg(referer)::text IS NULL OR referer ILIKE '%' || sqlc.narg(referer) || '%' ESCAPE '\')
+  AND (sqlc.narg(browser)::text IS NULL OR browser = sqlc.narg(browser))
+  AND (sqlc.narg(os)::text IS NULL OR os = sqlc.narg(os))
+  AND (sqlc.narg(device)::text IS NULL OR device = sqlc.narg(device))
+  AND (sqlc.narg(is_bot)::boolean IS NULL OR is_bot = sqlc.narg(is_bot))
+  AND (sqlc.narg(owner_id)::bigint IS NULL OR link_id IN (SELECT id FROM links WHERE owner_id = sqlc.narg(owner_id)))
 ORDER BY id
-LIMIT $1 OFFSET $2;
+LIMIT sqlc.arg(row_limit) OFFSET sqlc.arg(row_offset);
+
+-- name: ListLinkVisitsAfter :many
+SELECT
+  id,
+  link_id,
+  ip,
+  user_agent,
+  referer,
+  status,
+  created_at,
+  browser,
+  browser_version,
+  os,
+  device,
+  is_bot,
+  country,
+  region,
+  city
+FROM link_visits
+WHERE (sqlc.narg(link_id)::bigint IS NULL OR link_id = sqlc.narg(link_id))
+  AND (sqlc.narg(from_time)::timestamptz IS NULL OR created_at >= sqlc.narg(from_time))
+  AND (sqlc.narg(to_time)::timestamptz IS NULL OR created_at < sqlc.narg(to_time))
+  AND (sqlc.narg(status)::int IS NULL OR status = sqlc.narg(status))
+  AND (sqlc.narg(ip)::text IS NULL OR ip ILIKE '%' || sqlc.narg(ip) || '%' ESCAPE '\')
+  AND (sqlc.narg(referer)::text IS NULL OR referer ILIKE '%' || sqlc.narg(referer) || '%' ESCAPE '\')
+  AND (sqlc.narg(browser)::text IS NULL OR browser = sqlc.narg(browser))
+  AND (sqlc.narg(os)::text IS NULL OR os = sqlc.narg(os))
+  AND (sqlc.narg(device)::text IS NULL OR device = sqlc.narg(device))
+  AND (sqlc.narg(is_bot)::boolean IS NULL OR is_bot = sqlc.narg(is_bot))
+  AND (sqlc.narg(owner_id)::bigint IS NULL OR link_id IN (SELECT id FROM links WHERE owner_id = sqlc.narg(owner_id)))
+  AND id > sqlc.arg(after_id)
+ORDER BY id
+LIMIT sqlc.arg(row_limit);
 
 -- name: CountLinkVisits :one
-SELECT COUNT(*) FROM link_visits;
+SELECT COUNT(*) FROM link_visits
+WHERE (sqlc.narg(link_id)::bigint IS NULL OR link_id = sqlc.narg(link_id))
+  AND (sqlc.narg(from_time)::timestamptz IS NULL OR created_at >= sqlc.narg(from_time))
+  AND (sqlc.narg(to_time)::timestamptz IS NULL OR created_at < sqlc.narg(to_time))
+  AND (sqlc.narg(status)::int IS NULL OR status = sqlc.narg(status))
+  AND (sqlc.narg(ip)::text IS NULL OR ip ILIKE '%' || sqlc.narg(ip) || '%' ESCAPE '\')
+  AND (sqlc.narg(referer)::text IS NULL OR referer ILIKE '%' || sqlc.narg(referer) || '%' ESCAPE '\')
+  AND (sqlc.narg(browser)::text IS NULL OR browser = sqlc.narg(browser))
+  AND (sqlc.narg(os)::text IS NULL OR os = sqlc.narg(os))
+  AND (sqlc.narg(device)::text IS NULL OR device = sqlc.narg(device))
+  AND (sqlc.narg(is_bot)::boolean IS NULL OR is_bot = sqlc.narg(is_bot))
+  AND (sqlc.narg(owner_id)::bigint IS NULL OR link_id IN (SELECT id FROM links WHERE owner_id = sqlc.narg(owner_id)));
+
+
+-- name: ConsumeLinkVisit :one
+UPDATE links
+SET visit_count = visit_count + 1
+WHERE id = $1
+  AND (max_visits IS NULL OR visit_count < max_visits)
+RETURNING visit_count;
+
+-- name: CountLinkVisitsInRange :one
+SELECT
+  COUNT(*) FILTER (WHERE NOT is_bot) AS humans,
+  COUNT(*) FILTER (WHERE is_bot) AS bots
+FROM link_visits
+WHERE link_id = sqlc.arg(link_id)
+  AND created_at >= sqlc.arg(from_time)
+  AND created_at < sqlc.arg(to_time);
+
+-- name: LinkVisitSeries :many
+SELECT
+  date_trunc(sqlc.arg(interval)::text, created_at AT TIME ZONE 'UTC')::timestamp AS bucket,
+  COUNT(*) AS clicks
+FROM link_visits
+WHERE link_id = sqlc.arg(link_id)
+  AND created_at >= sqlc.arg(from_time)
+  AND created_at < sqlc.arg(to_time)
+  AND (sqlc.arg(include_bots)::boolean OR NOT is_bot)
+GROUP BY bucket
+ORDER BY bucket;
+
+-- name: TopLinkVisitReferers :many
+SELECT referer, COUNT(*) AS count
+FROM link_visits
+WHERE link_id = sqlc.arg(link_id)
+  AND created_at >= sqlc.arg(from_time)
+  AND created_at < sqlc.arg(to_time)
+  AND (sqlc.arg(include_bots)::boolean OR NOT is_bot)
+GROUP BY referer
+ORDER BY count DESC, referer
+LIMIT sqlc.arg(top_limit);
+
+-- name: TopLinkVisitUserAgents :many
+SELECT user_agent, COUNT(*) AS count
+FROM link_visits
+WHERE link_id = sqlc.arg(link_id)
+  AND created_at >= sqlc.arg(from_time)
+  AND created_at < sqlc.arg(to_time)
+  AND (sqlc.arg(include_bots)::boolean OR NOT is_bot)
+GROUP BY user_agent
+ORDER BY count DESC, user_agent
+LIMIT sqlc.arg(top_limit);
+
+-- name: LinkVisitStatuses :many
+SELECT status, COUNT(*) AS count
+FROM link_visits
+WHERE link_id = sqlc.arg(link_id)
+  AND created_at >= sqlc.arg(from_time)
+  AND created_at < sqlc.arg(to_time)
+  AND (sqlc.arg(include_bots)::boolean OR NOT is_bot)
+GROUP BY status
+ORDER BY count DESC, status;
+
+-- name: TopLinkVisitBrowsers :many
+SELECT browser, COUNT(*) AS count
+FROM link_visits
+WHERE link_id = sqlc.arg(link_id)
+  AND created_at >= sqlc.arg(from_time)
+  AND created_at < sqlc.arg(to_time)
+  AND (sqlc.arg(include_bots)::boolean OR NOT is_bot)
+GROUP BY browser
+ORDER BY count DESC, browser
+LIMIT sqlc.arg(top_limit);
+
+-- name: TopLinkVisitOperatingSystems :many
+SELECT os, COUNT(*) AS count
+FROM link_visits
+WHERE link_id = sqlc.arg(link_id)
+  AND created_at >= sqlc.arg(from_time)
+  AND created_at < sqlc.arg(to_time)
+  AND (sqlc.arg(include_bots)::boolean OR NOT is_bot)
+GROUP BY os
+ORDER BY count DESC, os
+LIMIT sqlc.arg(top_limit);
+
+-- name: LinkVisitDevices :many
+SELECT device, COUNT(*) AS count
+FROM link_visits
+WHERE link_id = sqlc.arg(link_id)
+  AND created_at >= sqlc.arg(from_time)
+  AND created_at < sqlc.arg(to_time)
+  AND (sqlc.arg(include_bots)::boolean OR NOT is_bot)
+GROUP BY device
+ORDER BY count DESC, device;
+
+-- name: LinkVisitCountries :many
+SELECT country, COUNT(*) AS count
+FROM link_visits
+WHERE link_id = sqlc.arg(link_id)
+  AND created_at >= sqlc.arg(from_time)
+  AND created_at < sqlc.arg(to_time)
+  AND (sqlc.arg(include_bots)::boolean OR NOT is_bot)
+GROUP BY country
+ORDER BY count DESC, country;
+
+-- name: CountLinkVisitsBefore :one
+SELECT COUNT(*) FROM link_visits
+WHERE created_at < sqlc.arg(before);
+
+-- name: DeleteLinkVisitsBefore :execrows
+DELETE FROM link_visits
+WHERE id IN (
+  SELECT id FROM link_visits
+  WHERE created_at < sqlc.arg(before)
+  ORDER BY created_at
+  LIMIT sqlc.arg(row_limit)
+);
+
+-- name: CountLinkVisitGroups :many
+SELECT link_id, status, is_bot, referer, COUNT(*) AS count
+FROM link_visits
+WHERE (sqlc.narg(link_id)::bigint IS NULL OR link_id = sqlc.narg(link_id))
+  AND created_at >= sqlc.arg(from_time)
+  AND created_at < sqlc.arg(to_time)
+GROUP BY link_id, status, is_bot, referer;
 
+-- name: FirstLinkVisitCreatedAt :one
+SELECT created_at FROM link_visits
+ORDER BY created_at
+LIMIT 1;
@@ -1,58 +1,140 @@
--- name: ListLinks :many
-SELECT 
-  id, 
-  original_url, 
-  short_name, 
-  created_at
-FROM links
-ORDER BY id;
-
-
 -- name: ListLinksWithRange :many
 SELECT 
   id, 
   original_url, 
   short_name, 
-  created_at
+  created_at,
+  expires_at,
+  max_visits,
+  visit_count,
+  password_hash,
+  redirect_type,
+  deleted_at,
+  owner_id
 FROM links
+WHERE deleted_at IS NULL
 ORDER BY id
 LIMIT $1 OFFSET $2;
 
+-- name: ListLinksAfter :many
+SELECT
+  id,
+  original_url,
+  short_name,
+  created_at,
+  expires_at,
+  max_visits,
+  visit_count,
+  password_hash,
+  redirect_type,
+  deleted_at,
+  owner_id
+FROM links
+WHERE id > $1
+  AND deleted_at IS NULL
+ORDER BY id
+LIMIT $2;
+
 -- name: GetLink :one
 SELECT 
   id, 
   original_url, 
   short_name, 
-  created_at
+  created_at,
+  expires_at,
+  max_visits,
+  visit_count,
+  password_hash,
+  redirect_type,
+  deleted_at,
+  owner_id
 FROM links
-WHERE id = $1;
+WHERE id = $1
+  AND deleted_at IS NULL;
 
 -- name: GetLinkByShortName :one
 SELECT
   id,
   original_url,
   short_name,
-  created_at
+  created_at,
+  expires_at,
+  max_visits,
+  visit_count,
+  password_hash,
+  redirect_type,
+  deleted_at,
+  owner_id
 FROM links
-WHERE short_name = $1;
+WHERE short_name = $1
+  AND deleted_at IS NULL;
+
+-- name: GetLinkForUpdate :one
+SELECT
+  id,
+  original_url,
+  short_name,
+  created_at,
+  expires_at,
+  max_visits,
+  visit_count,
+  password_hash,
+  redirect_type,
+  deleted_at,
+  owner_id
+FROM links
+WHERE id = $1
+  AND deleted_at IS NULL
+FOR UPDATE;
 
 -- name: CountLinks :one
-SELECT COUNT(*) FROM links;
+SELECT COUNT(*) FROM links
+WHERE deleted_at IS NULL;
 
 -- name: CreateLink :one
-INSERT INTO links (original_url, short_name)
-VALUES ($1, $2)
-RETURNING id, original_url, short_name, created_at;
+INSERT INTO links (original_url, short_name, expires_at, max_visits, password_hash, redirect_type, owner_id)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+RETURNING id, original_url, short_name, created_at, expires_at, max_visits, visit_count, password_hash, redirect_type, deleted_at, owner_id;
+
+-- name: CreateLinkIfAbsent :one
+INSERT INTO links (original_url, short_name, expires_at, max_visits, password_hash, redirect_type, owner_id)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+ON CONFLICT (short_name) DO NOTHING
+RETURNING id, original_url, short_name, created_at, expires_at, max_visits, visit_count, password_hash, redirect_type, deleted_at, owner_id;
 
 -- name: UpdateLink :one
 UPDATE links
 SET original_url = $2,
-    short_name   = $3
+    short_name   = $3,
+    expires_at   = $4,
+    max_visits   = $5,
+    password_hash = $6,
+    redirect_type = $7,
+    owner_id = COALESCE($8, owner_id)
+WHERE id = $1
+  AND deleted_at IS NULL
+RETURNING id, original_url, short_name, created_at, expires_at, max_visits, visit_count, password_hash, redirect_type, deleted_at, owner_id;
+
+-- name: TrashLink :one
+UPDATE links
+SET deleted_at = now()
 WHERE id = $1
-RETURNING id, original_url, short_name, created_at;
+  AND deleted_at IS NULL
+RETURNING id, original_url, short_name, created_at, expires_at, max_visits, visit_count, password_hash, redirect_type, deleted_at, owner_id;
 
--- name: DeleteLink :one
-DELETE FROM links
+-- name: RestoreLink :one
+UPDATE links
+SET deleted_at = NULL
 WHERE id = $1
-RETURNING id;
+  AND deleted_at IS NOT NULL
+RETURNING id, original_url, short_name, created_at, expires_at, max_visits, visit_count, password_hash, redirect_type, deleted_at, owner_id;
 
+-- name: PurgeTrashedLinks :execrows
+WITH purged AS (
+  DELETE FROM links
+  WHERE deleted_at < sqlc.arg(before)::timestamptz
+  RETURNING id, original_url, short_name
+)
+INSERT INTO link_events (link_id, type, old_original_url, old_short_name, actor, request_id)
+SELECT id, 'purged', original_url, short_name, sqlc.arg(actor), sqlc.arg(request_id)
+FROM purged;
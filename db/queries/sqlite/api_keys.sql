@@ -0,0 +1,27 @@
+-- name: CreateApiKey :one
+INSERT INTO api_keys (name, prefix, key_hash, scopes, owner_id)
+VALUES (?, ?, ?, ?, ?)
+RETURNING id, name, prefix, key_hash, scopes, created_at, last_used_at, revoked_at, owner_id;
+
+-- name: GetApiKeyByHash :one
+SELECT id, name, prefix, key_hash, scopes, created_at, last_used_at, revoked_at, owner_id
+FROM api_keys
+WHERE key_hash = ?
+  AND revoked_at IS NULL;
+
+-- name: ListApiKeys :many
+SELECT id, name, prefix, key_hash, scopes, created_at, last_used_at, revoked_at, owner_id
+FROM api_keys
+ORDER BY id;
+
+-- name: RevokeApiKey :one
+UPDATE api_keys
+SET revoked_at = strftime('%Y-%m-%d %H:%M:%f', 'now')
+WHERE id = ?
+  AND revoked_at IS NULL
+RETURNING id;
+
+-- name: TouchApiKey :exec
+UPDATE api_keys
+SET last_used_at = strftime('%Y-%m-%d %H:%M:%f', sqlc.arg(used_at))
+WHERE id = sqlc.arg(id);
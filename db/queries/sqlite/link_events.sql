@@ -0,0 +1,15 @@
+-- name: CreateLinkEvent :exec
+INSERT INTO link_events (link_id, type, old_original_url, new_original_url, old_short_name, new_short_name, actor, request_id)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?);
+
+-- name: ListLinkEvents :many
+SELECT id, link_id, type, old_original_url, new_original_url, old_short_name, new_short_name, actor, request_id, created_at
+FROM link_events
+WHERE link_id = ?
+ORDER BY id;
+
+-- name: CreatePurgedLinkEvents :exec
+INSERT INTO link_events (link_id, type, old_original_url, old_short_name, actor, request_id)
+SELECT id, 'purged', original_url, short_name, sqlc.arg(actor), sqlc.arg(request_id)
+FROM links
+WHERE deleted_at < strftime('%Y-%m-%d %H:%M:%f', sqlc.arg(before));
@@ -0,0 +1,23 @@
+-- name: UpsertLinkVisitDaily :exec
+INSERT INTO link_visit_daily (link_id, day, status, is_bot, referer_host, visits)
+VALUES (sqlc.arg(link_id), date(sqlc.arg(day)), sqlc.arg(status), sqlc.arg(is_bot), sqlc.arg(referer_host), sqlc.arg(visits))
+ON CONFLICT (link_id, day, status, is_bot, referer_host) DO UPDATE
+SET visits = excluded.visits;
+
+-- name: ListLinkVisitDaily :many
+SELECT link_id, day, status, is_bot, referer_host, visits
+FROM link_visit_daily
+WHERE link_id = sqlc.arg(link_id)
+  AND day >= date(sqlc.arg(from_day))
+  AND day < date(sqlc.arg(to_day))
+ORDER BY day;
+
+-- name: GetLinkVisitRolledUntil :one
+SELECT rolled_until FROM link_visit_rollup_state
+WHERE id = 1;
+
+-- name: SetLinkVisitRolledUntil :exec
+INSERT INTO link_visit_rollup_state (id, rolled_until)
+VALUES (1, date(sqlc.arg(rolled_until)))
+ON CONFLICT (id) DO UPDATE
+SET rolled_until = MAX(rolled_until, excluded.rolled_until);
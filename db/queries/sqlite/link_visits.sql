@@ -0,0 +1,216 @@
+-- name: CreateLinkVisit :one
+INSERT INTO link_visits (link_id, ip, user_agent, referer, status, browser, browser_version, os, device, is_bot, country, region, city)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+RETURNING id, link_id, ip, user_agent, referer, status, created_at, browser, browser_version, os, device, is_bot, country, region, city;
+
+-- name: ListLinkVisitsWithRange :many
+SELECT
+  id,
+  link_id,
+  ip,
+  user_agent,
+  referer,
+  status,
+  created_at,
+  browser,
+  browser_version,
+  os,
+  device,
+  is_bot,
+  country,
+  region,
+  city
+FROM link_visits
+WHERE (sqlc.narg(link_id) IS NULL OR link_id = sqlc.narg(link_id))
+  AND (sqlc.narg(from_time) IS NULL OR created_at >= strftime('%Y-%m-%d %H:%M:%f', sqlc.narg(from_time)))
+  AND (sqlc.narg(to_time) IS NULL OR created_at < strftime('%Y-%m-%d %H:%M:%f', sqlc.narg(to_time)))
+  AND (sqlc.narg(status) IS NULL OR status = sqlc.narg(status))
+  AND (sqlc.narg(ip) IS NULL OR ip LIKE '%' || sqlc.narg(ip) || '%' ESCAPE '\')
+  AND (sqlc.narg(referer) IS NULL OR referer LIKE '%' || sqlc.narg(referer) || '%' ESCAPE '\')
+  AND (sqlc.narg(browser) IS NULL OR browser = sqlc.narg(browser))
+  AND (sqlc.narg(os) IS NULL OR os = sqlc.narg(os))
+  AND (sqlc.narg(device) IS NULL OR device = sqlc.narg(device))
+  AND (sqlc.narg(is_bot) IS NULL OR is_bot = sqlc.narg(is_bot))
+  AND (sqlc.narg(owner_id) IS NULL OR link_id IN (SELECT id FROM links WHERE owner_id = sqlc.narg(owner_id)))
+ORDER BY id
+LIMIT sqlc.arg(row_limit) OFFSET sqlc.arg(row_offset);
+
+-- name: ListLinkVisitsAfter :many
+SELECT
+  id,
+  link_id,
+  ip,
+  user_agent,
+  referer,
+  status,
+  created_at,
+  browser,
+  browser_version,
+  os,
+  device,
+  is_bot,
+  country,
+  region,
+  city
+FROM link_visits
+WHERE (sqlc.narg(link_id) IS NULL OR link_id = sqlc.narg(link_id))
+  AND (sqlc.narg(from_time) IS NULL OR created_at >= strftime('%Y-%m-%d %H:%M:%f', sqlc.narg(from_time)))
+  AND (sqlc.narg(to_time) IS NULL OR created_at < strftime('%Y-%m-%d %H:%M:%f', sqlc.narg(to_time)))
+  AND (sqlc.narg(status) IS NULL OR status = sqlc.narg(status))
+  AND (sqlc.narg(ip) IS NULL OR ip LIKE '%' || sqlc.narg(ip) || '%' ESCAPE '\')
+  AND (sqlc.narg(referer) IS NULL OR referer LIKE '%' || sqlc.narg(referer) || '%' ESCAPE '\')
+  AND (sqlc.narg(browser) IS NULL OR browser = sqlc.narg(browser))
+  AND (sqlc.narg(os) IS NULL OR os = sqlc.narg(os))
+  AND (sqlc.narg(device) IS NULL OR device = sqlc.narg(device))
+  AND (sqlc.narg(is_bot) IS NULL OR is_bot = sqlc.narg(is_bot))
+  AND (sqlc.narg(owner_id) IS NULL OR link_id IN (SELECT id FROM links WHERE owner_id = sqlc.narg(owner_id)))
+  AND id > sqlc.arg(after_id)
+ORDER BY id
+LIMIT sqlc.arg(row_limit);
+
+-- name: CountLinkVisits :one
+SELECT COUNT(*) FROM link_visits
+WHERE (sqlc.narg(link_id) IS NULL OR link_id = sqlc.narg(link_id))
+  AND (sqlc.narg(from_time) IS NULL OR created_at >= strftime('%Y-%m-%d %H:%M:%f', sqlc.narg(from_time)))
+  AND (sqlc.narg(to_time) IS NULL OR created_at < strftime('%Y-%m-%d %H:%M:%f', sqlc.narg(to_time)))
+  AND (sqlc.narg(status) IS NULL OR status = sqlc.narg(status))
+  AND (sqlc.narg(ip) IS NULL OR ip LIKE '%' || sqlc.narg(ip) || '%' ESCAPE '\')
+  AND (sqlc.narg(referer) IS NULL OR referer LIKE '%' || sqlc.narg(referer) || '%' ESCAPE '\')
+  AND (sqlc.narg(browser) IS NULL OR browser = sqlc.narg(browser))
+  AND (sqlc.narg(os) IS NULL OR os = sqlc.narg(os))
+  AND (sqlc.narg(device) IS NULL OR device = sqlc.narg(device))
+  AND (sqlc.narg(is_bot) IS NULL OR is_bot = sqlc.narg(is_bot))
+  AND (sqlc.narg(owner_id) IS NULL OR link_id IN (SELECT id FROM links WHERE owner_id = sqlc.narg(owner_id)));
+
+
+-- name: ConsumeLinkVisit :one
+UPDATE links
+SET visit_count = visit_count + 1
+WHERE id = ?
+  AND (max_visits IS NULL OR visit_count < max_visits)
+RETURNING visit_count;
+
+-- name: CountLinkVisitsInRange :one
+SELECT
+  COUNT(*) FILTER (WHERE NOT is_bot) AS humans,
+  COUNT(*) FILTER (WHERE is_bot) AS bots
+FROM link_visits
+WHERE link_id = sqlc.arg(link_id)
+  AND created_at >= strftime('%Y-%m-%d %H:%M:%f', sqlc.arg(from_time))
+  AND created_at < strftime('%Y-%m-%d %H:%M:%f', sqlc.arg(to_time));
+
+-- name: LinkVisitSeries :many
+SELECT
+  CAST(CASE sqlc.arg(interval)
+    WHEN 'hour' THEN strftime('%Y-%m-%d %H:00:00', created_at)
+    WHEN 'week' THEN date(created_at, 'weekday 0', '-6 days') || ' 00:00:00'
+    ELSE strftime('%Y-%m-%d 00:00:00', created_at)
+  END AS TEXT) AS bucket,
+  COUNT(*) AS clicks
+FROM link_visits
+WHERE link_id = sqlc.arg(link_id)
+  AND created_at >= strftime('%Y-%m-%d %H:%M:%f', sqlc.arg(from_time))
+  AND created_at < strftime('%Y-%m-%d %H:%M:%f', sqlc.arg(to_time))
+  AND (sqlc.arg(include_bots) OR NOT is_bot)
+GROUP BY bucket
+ORDER BY bucket;
+
+-- name: TopLinkVisitReferers :many
+SELECT referer, COUNT(*) AS count
+FROM link_visits
+WHERE link_id = sqlc.arg(link_id)
+  AND created_at >= strftime('%Y-%m-%d %H:%M:%f', sqlc.arg(from_time))
+  AND created_at < strftime('%Y-%m-%d %H:%M:%f', sqlc.arg(to_time))
+  AND (sqlc.arg(include_bots) OR NOT is_bot)
+GROUP BY referer
+ORDER BY count DESC, referer
+LIMIT sqlc.arg(top_limit);
+
+-- name: TopLinkVisitUserAgents :many
+SELECT user_agent, COUNT(*) AS count
+FROM link_visits
+WHERE link_id = sqlc.arg(link_id)
+  AND created_at >= strftime('%Y-%m-%d %H:%M:%f', sqlc.arg(from_time))
+  AND created_at < strftime('%Y-%m-%d %H:%M:%f', sqlc.arg(to_time))
+  AND (sqlc.arg(include_bots) OR NOT is_bot)
+GROUP BY user_agent
+ORDER BY count DESC, user_agent
+LIMIT sqlc.arg(top_limit);
+
+-- name: LinkVisitStatuses :many
+SELECT status, COUNT(*) AS count
+FROM link_visits
+WHERE link_id = sqlc.arg(link_id)
+  AND created_at >= strftime('%Y-%m-%d %H:%M:%f', sqlc.arg(from_time))
+  AND created_at < strftime('%Y-%m-%d %H:%M:%f', sqlc.arg(to_time))
+  AND (sqlc.arg(include_bots) OR NOT is_bot)
+GROUP BY status
+ORDER BY count DESC, status;
+
+-- name: TopLinkVisitBrowsers :many
+SELECT browser, COUNT(*) AS count
+FROM link_visits
+WHERE link_id = sqlc.arg(link_id)
+  AND created_at >= strftime('%Y-%m-%d %H:%M:%f', sqlc.arg(from_time))
+  AND created_at < strftime('%Y-%m-%d %H:%M:%f', sqlc.arg(to_time))
+  AND (sqlc.arg(include_bots) OR NOT is_bot)
+GROUP BY browser
+ORDER BY count DESC, browser
+LIMIT sqlc.arg(top_limit);
+
+-- name: TopLinkVisitOperatingSystems :many
+SELECT os, COUNT(*) AS count
+FROM link_visits
+WHERE link_id = sqlc.arg(link_id)
+  AND created_at >= strftime('%Y-%m-%d %H:%M:%f', sqlc.arg(from_time))
+  AND created_at < strftime('%Y-%m-%d %H:%M:%f', sqlc.arg(to_time))
+  AND (sqlc.arg(include_bots) OR NOT is_bot)
+GROUP BY os
+ORDER BY count DESC, os
+LIMIT sqlc.arg(top_limit);
+
+-- name: LinkVisitDevices :many
+SELECT device, COUNT(*) AS count
+FROM link_visits
+WHERE link_id = sqlc.arg(link_id)
+  AND created_at >= strftime('%Y-%m-%d %H:%M:%f', sqlc.arg(from_time))
+  AND created_at < strftime('%Y-%m-%d %H:%M:%f', sqlc.arg(to_time))
+  AND (sqlc.arg(include_bots) OR NOT is_bot)
+GROUP BY device
+ORDER BY count DESC, device;
+
+-- name: LinkVisitCountries :many
+SELECT country, COUNT(*) AS count
+FROM link_visits
+WHERE link_id = sqlc.arg(link_id)
+  AND created_at >= strftime('%Y-%m-%d %H:%M:%f', sqlc.arg(from_time))
+  AND created_at < strftime('%Y-%m-%d %H:%M:%f', sqlc.arg(to_time))
+  AND (sqlc.arg(include_bots) OR NOT is_bot)
+GROUP BY country
+ORDER BY count DESC, country;
+
+-- name: CountLinkVisitsBefore :one
+SELECT COUNT(*) FROM link_visits
+WHERE created_at < strftime('%Y-%m-%d %H:%M:%f', sqlc.arg(before));
+
+-- name: DeleteLinkVisitsBefore :execrows
+DELETE FROM link_visits
+WHERE id IN (
+  SELECT id FROM link_visits
+  WHERE created_at < strftime('%Y-%m-%d %H:%M:%f', sqlc.arg(before))
+  ORDER BY created_at
+  LIMIT sqlc.arg(row_limit)
+);
+
+-- name: CountLinkVisitGroups :many
+SELECT link_id, status, is_bot, referer, COUNT(*) AS count
+FROM link_visits
+WHERE (sqlc.narg(link_id) IS NULL OR link_id = sqlc.narg(link_id))
+  AND created_at >= strftime('%Y-%m-%d %H:%M:%f', sqlc.arg(from_time))
+  AND created_at < strftime('%Y-%m-%d %H:%M:%f', sqlc.arg(to_time))
+GROUP BY link_id, status, is_bot, referer;
+
+-- name: FirstLinkVisitCreatedAt :one
+SELECT created_at FROM link_visits
+ORDER BY created_at
+LIMIT 1;
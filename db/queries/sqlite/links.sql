@@ -0,0 +1,116 @@
+-- name: ListLinksWithRange :many
+SELECT 
+  id, 
+  original_url, 
+  short_name, 
+  created_at,
+  expires_at,
+  max_visits,
+  visit_count,
+  password_hash,
+  redirect_type,
+  deleted_at,
+  owner_id
+FROM links
+WHERE deleted_at IS NULL
+ORDER BY id
+LIMIT ? OFFSET ?;
+
+-- name: ListLinksAfter :many
+SELECT
+  id,
+  original_url,
+  short_name,
+  created_at,
+  expires_at,
+  max_visits,
+  visit_count,
+  password_hash,
+  redirect_type,
+  deleted_at,
+  owner_id
+FROM links
+WHERE id > ?
+  AND deleted_at IS NULL
+ORDER BY id
+LIMIT ?;
+
+-- name: GetLink :one
+SELECT 
+  id, 
+  original_url, 
+  short_name, 
+  created_at,
+  expires_at,
+  max_visits,
+  visit_count,
+  password_hash,
+  redirect_type,
+  deleted_at,
+  owner_id
+FROM links
+WHERE id = ?
+  AND deleted_at IS NULL;
+
+-- name: GetLinkByShortName :one
+SELECT
+  id,
+  original_url,
+  short_name,
+  created_at,
+  expires_at,
+  max_visits,
+  visit_count,
+  password_hash,
+  redirect_type,
+  deleted_at,
+  owner_id
+FROM links
+WHERE short_name = ?
+  AND deleted_at IS NULL;
+
+-- name: CountLinks :one
+SELECT COUNT(*) FROM links
+WHERE deleted_at IS NULL;
+
+-- name: CreateLink :one
+INSERT INTO links (original_url, short_name, expires_at, max_visits, password_hash, redirect_type, owner_id)
+VALUES (?, ?, ?, ?, ?, ?, ?)
+RETURNING id, original_url, short_name, created_at, expires_at, max_visits, visit_count, password_hash, redirect_type, deleted_at, owner_id;
+
+-- name: CreateLinkIfAbsent :one
+INSERT INTO links (original_url, short_name, expires_at, max_visits, password_hash, redirect_type, owner_id)
+VALUES (?, ?, ?, ?, ?, ?, ?)
+ON CONFLICT (short_name) DO NOTHING
+RETURNING id, original_url, short_name, created_at, expires_at, max_visits, visit_count, password_hash, redirect_type, deleted_at, owner_id;
+
+-- name: UpdateLink :one
+UPDATE links
+SET original_url = ?,
+    short_name   = ?,
+    expires_at   = ?,
+    max_visits   = ?,
+    password_hash = ?,
+    redirect_type = ?,
+    owner_id = COALESCE(?, owner_id)
+WHERE id = ?
+  AND deleted_at IS NULL
+RETURNING id, original_url, short_name, created_at, expires_at, max_visits, visit_count, password_hash, redirect_type, deleted_at, owner_id;
+
+-- name: TrashLink :one
+UPDATE links
+SET deleted_at = strftime('%Y-%m-%d %H:%M:%f', 'now')
+WHERE id = ?
+  AND deleted_at IS NULL
+RETURNING id, original_url, short_name, created_at, expires_at, max_visits, visit_count, password_hash, redirect_type, deleted_at, owner_id;
+
+-- name: RestoreLink :one
+UPDATE links
+SET deleted_at = NULL
+WHERE id = ?
+  AND deleted_at IS NOT NULL
+RETURNING id, original_url, short_name, created_at, expires_at, max_visits, visit_count, password_hash, redirect_type, deleted_at, owner_id;
+
+-- name: PurgeTrashedLinks :execrows
+DELETE FROM links
+WHERE deleted_at < strftime('%Y-%m-%d %H:%M:%f', sqlc.arg(before));
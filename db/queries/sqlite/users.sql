@@ -0,0 +1,38 @@
+-- name: CreateUser :one
+INSERT INTO users (username, password_hash, role)
+VALUES (?, ?, ?)
+RETURNING id, username, password_hash, role, created_at;
+
+-- name: GetUser :one
+SELECT id, username, password_hash, role, created_at
+FROM users
+WHERE id = ?;
+
+-- name: GetUserByUsername :one
+SELECT id, username, password_hash, role, created_at
+FROM users
+WHERE username = ?;
+
+-- name: ListUsers :many
+SELECT id, username, password_hash, role, created_at
+FROM users
+ORDER BY id;
+
+-- name: CreateUserSession :exec
+INSERT INTO user_sessions (user_id, token_hash, expires_at)
+VALUES (sqlc.arg(user_id), sqlc.arg(token_hash), strftime('%Y-%m-%d %H:%M:%f', sqlc.arg(expires_at)));
+
+-- name: GetUserBySession :one
+SELECT users.id, users.username, users.password_hash, users.role, users.created_at
+FROM user_sessions
+JOIN users ON users.id = user_sessions.user_id
+WHERE user_sessions.token_hash = ?
+  AND user_sessions.expires_at > strftime('%Y-%m-%d %H:%M:%f', 'now');
+
+-- name: DeleteUserSession :exec
+DELETE FROM user_sessions
+WHERE token_hash = ?;
+
+-- name: DeleteExpiredUserSessions :execrows
+DELETE FROM user_sessions
+WHERE expires_at <= strftime('%Y-%m-%d %H:%M:%f', 'now');
@@ -0,0 +1,38 @@
+-- name: CreateUser :one
+INSERT INTO users (username, password_hash, role)
+VALUES ($1, $2, $3)
+RETURNING id, username, password_hash, role, created_at;
+
+-- name: GetUser :one
+SELECT id, username, password_hash, role, created_at
+FROM users
+WHERE id = $1;
+
+-- name: GetUserByUsername :one
+SELECT id, username, password_hash, role, created_at
+FROM users
+WHERE username = $1;
+
+-- name: ListUsers :many
+SELECT id, username, password_hash, role, created_at
+FROM users
+ORDER BY id;
+
+-- name: CreateUserSession :exec
+INSERT INTO user_sessions (user_id, token_hash, expires_at)
+VALUES ($1, $2, $3);
+
+-- name: GetUserBySession :one
+SELECT users.id, users.username, users.password_hash, users.role, users.created_at
+FROM user_sessions
+JOIN users ON users.id = user_sessions.user_id
+WHERE user_sessions.token_hash = $1
+  AND user_sessions.expires_at > now();
+
+-- name: DeleteUserSession :exec
+DELETE FROM user_sessions
+WHERE token_hash = $1;
+
+-- name: DeleteExpiredUserSessions :execrows
+DELETE FROM user_sessions
+WHERE expires_at <= now();
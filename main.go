@@ -1,10 +1,14 @@
 package main
 
 import (
-	"database/sql"
+	"context"
+	"errors"
 	"fmt"
 	"log"
+	"net/http"
 	"os"
+	"os/signal"
+	"syscall"
 	"time"
 
 	"github.com/gin-contrib/cors"
@@ -12,11 +16,20 @@ import (
 
 	"link-service/src/config"
 	configDomain "link-service/src/domain/config"
+	"link-service/src/domain/linkvisit"
+	domainratelimit "link-service/src/domain/ratelimit"
 	database "link-service/src/infrastructure/database"
-	postgreslinkrepo "link-service/src/infrastructure/repository/postgres"
+	"link-service/src/infrastructure/geoip"
+	"link-service/src/infrastructure/ratelimit"
+	"link-service/src/infrastructure/repository"
+	cachelinkrepo "link-service/src/infrastructure/repository/cache"
 	httpinterface "link-service/src/interface/http"
+	"link-service/src/interface/http/redirect"
+	"link-service/src/interface/http/stats"
+	apikeyusecase "link-service/src/usecase/apikey"
 	linkusecase "link-service/src/usecase/link"
 	linkvisitusecase "link-service/src/usecase/linkvisit"
+	userusecase "link-service/src/usecase/user"
 )
 
 func main() {
@@ -33,12 +46,23 @@ func main() {
 		log.Fatal(err)
 	}
 
+	// без списка доверенных прокси IP клиента берётся из соединения, а не из X-Forwarded-For
+	if err := httpServer.SetTrustedProxies(cnf.App.TrustedProxies); err != nil {
+		log.Fatal(err)
+	}
+
 	httpServer.Use(cors.New(cors.Config{
 		AllowOrigins: cnf.App.AllowedOrigins,
 		AllowMethods: []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
-		AllowHeaders: []string{"Origin", "Content-Type", "Accept", "Authorization"},
+		AllowHeaders: []string{"Origin", "Content-Type", "Accept", "Authorization", "X-Request-ID"},
 		ExposeHeaders: []string{
 			"Content-Range",
+			"Link",
+			"X-Request-ID",
+			"Retry-After",
+			"RateLimit-Limit",
+			"RateLimit-Remaining",
+			"RateLimit-Reset",
 		},
 		MaxAge: 12 * time.Hour,
 	}))
@@ -54,24 +78,129 @@ func main() {
 
 	defer func() { _ = db.Disconnect() }()
 
-	sqlDB, ok := db.GetInstance().(*sql.DB)
-	if !ok || sqlDB == nil {
-		log.Fatal("invalid database instance")
+	repos, err := repository.New(db)
+	if err != nil {
+		log.Fatal(err)
 	}
 
-	linkRepo := postgreslinkrepo.New(sqlDB)
+	linkRepo := cachelinkrepo.New(repos.Link, cachelinkrepo.Config{
+		Size:        cnf.LinkCache.Size,
+		TTL:         cnf.LinkCache.TTL,
+		NegativeTTL: cnf.LinkCache.NegativeTTL,
+	})
 	linkService := linkusecase.NewService(linkRepo, cnf.App.BaseURL)
+	linkTrashPurger := linkusecase.NewTrashPurger(linkRepo, linkusecase.TrashConfig{
+		MaxAge:   cnf.LinkTrash.MaxAge,
+		Interval: cnf.LinkTrash.Interval,
+	})
+
+	visitRecorder := linkvisitusecase.NewRecorder(repos.LinkVisit, linkvisitusecase.RecorderConfig{
+		QueueSize:     cnf.VisitRecorder.QueueSize,
+		BatchSize:     cnf.VisitRecorder.BatchSize,
+		FlushInterval: cnf.VisitRecorder.FlushInterval,
+	})
+	var geo linkvisit.GeoLocator
+	if cnf.GeoIP.Path != "" {
+		geoReader, err := geoip.Open(geoip.Config{
+			Path:           cnf.GeoIP.Path,
+			ReloadInterval: cnf.GeoIP.ReloadInterval,
+		})
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer func() { _ = geoReader.Close() }()
+
+		geo = geoReader
+	}
 
-	linkVisitRepo := postgreslinkrepo.NewLinkVisitRepository(sqlDB)
-	linkVisitService := linkvisitusecase.NewService(linkVisitRepo)
+	linkVisitService := linkvisitusecase.NewService(repos.LinkVisit, linkRepo, visitRecorder, geo, linkvisitusecase.Privacy{
+		IPMode:       linkvisitusecase.IPMode(cnf.App.Privacy.IPMode),
+		HashKey:      []byte(cnf.App.Privacy.HashKey),
+		SaltRotation: cnf.App.Privacy.SaltRotation,
+	})
+
+	visitAggregator := linkvisitusecase.NewAggregator(repos.LinkVisit, linkvisitusecase.RollupConfig{
+		Interval: cnf.Rollup.Interval,
+	})
+
+	visitPurger := linkvisitusecase.NewPurger(repos.LinkVisit, linkvisitusecase.RetentionConfig{
+		MaxAge:     cnf.Retention.MaxAge,
+		Interval:   cnf.Retention.Interval,
+		BatchSize:  cnf.Retention.BatchSize,
+		BatchPause: cnf.Retention.BatchPause,
+		DryRun:     cnf.Retention.DryRun,
+	})
+
+	if cnf.Auth.AdminKey == "" {
+		log.Printf("API_ADMIN_KEY is not set: API keys cannot be created and only existing keys can access /api")
+	}
+	apiKeyService := apikeyusecase.NewService(repos.APIKey, repos.User, cnf.Auth.AdminKey)
+	userService := userusecase.NewService(repos.User, cnf.Auth.SessionTTL)
 
 	httpServer.Use(gin.Recovery())
 	httpinterface.InitRoutes(httpServer, httpinterface.Deps{
 		Link:      linkService,
 		LinkVisit: linkVisitService,
+		APIKeys:   apiKeyService,
+		Users:     userService,
+		Stats: map[string]stats.Source{
+			"link_cache":      func() any { return linkRepo.Stats() },
+			"link_trash":      func() any { return linkTrashPurger.Stats() },
+			"visit_recorder":  func() any { return visitRecorder.Stats() },
+			"visit_retention": func() any { return visitPurger.Stats() },
+			"visit_rollup":    func() any { return visitAggregator.Stats() },
+		},
+		Retention: visitPurger,
+		Bots: redirect.BotPolicy{
+			UserAgents: cnf.Bots.UserAgents,
+			SkipVisits: cnf.Bots.SkipVisits,
+		},
+		RateLimit: httpinterface.RateLimits{
+			Store:      ratelimit.NewMemoryStore(cnf.RateLimit.MaxKeys),
+			Redirect:   domainratelimit.Policy(cnf.RateLimit.Redirect),
+			CreateLink: domainratelimit.Policy(cnf.RateLimit.CreateLink),
+			Unlock:     domainratelimit.Policy(cnf.RateLimit.Unlock),
+			Login:      domainratelimit.Policy(cnf.RateLimit.Login),
+		},
 	})
 
-	if err := httpServer.Run(fmt.Sprintf("%s:%d", cnf.App.Host, cnf.App.Port)); err != nil {
-		log.Fatal(err)
+	server := &http.Server{
+		Addr:    fmt.Sprintf("%s:%d", cnf.App.Host, cnf.App.Port),
+		Handler: httpServer,
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	go func() {
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Fatal(err)
+		}
+	}()
+
+	<-ctx.Done()
+
+	/*Сначала перестаём принимать запросы, затем дописываем очередь посещений*/
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		log.Printf("http server shutdown: %v", err)
+	}
+
+	if err := visitRecorder.Close(shutdownCtx); err != nil {
+		log.Printf("visit recorder shutdown: %v", err)
+	}
+
+	if err := visitPurger.Close(shutdownCtx); err != nil {
+		log.Printf("visit retention shutdown: %v", err)
+	}
+
+	if err := visitAggregator.Close(shutdownCtx); err != nil {
+		log.Printf("visit rollup shutdown: %v", err)
+	}
+
+	if err := linkTrashPurger.Close(shutdownCtx); err != nil {
+		log.Printf("link trash shutdown: %v", err)
 	}
 }
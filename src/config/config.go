@@ -4,8 +4,12 @@ import (
 	"encoding/json"
 	"fmt"
 	configDomain "link-service/src/domain/config"
+	"math"
+	"net"
 	"os"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
 )
@@ -20,20 +24,38 @@ func Init(envPath string) (*configDomain.Config, error) {
 		_ = godotenv.Load()
 	}
 
-	appConfig := initAppConfig()
+	appConfig, err := initAppConfig()
+	if err != nil {
+		return nil, err
+	}
+
 	dbConfig, err := initDatabaseConfig()
 	if err != nil {
 		return nil, err
 	}
 
+	botConfig, err := initBotConfig()
+	if err != nil {
+		return nil, err
+	}
+
 	return &configDomain.Config{
-		App:      *appConfig,
-		Database: *dbConfig,
+		App:           *appConfig,
+		Database:      *dbConfig,
+		VisitRecorder: *initVisitRecorderConfig(),
+		LinkCache:     *initLinkCacheConfig(),
+		Bots:          *botConfig,
+		GeoIP:         *initGeoIPConfig(),
+		Retention:     *initVisitRetentionConfig(),
+		Rollup:        *initVisitRollupConfig(),
+		LinkTrash:     *initLinkTrashConfig(),
+		Auth:          *initAuthConfig(),
+		RateLimit:     *initRateLimitConfig(),
 	}, nil
 }
 
 /*Метод инициализации конфигурации приложения*/
-func initAppConfig() *configDomain.AppConfig {
+func initAppConfig() (*configDomain.AppConfig, error) {
 	var development bool
 	var host string
 	var port int
@@ -68,6 +90,16 @@ func initAppConfig() *configDomain.AppConfig {
 
 	}
 
+	privacy, err := initPrivacyConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	trustedProxies, err := initTrustedProxies()
+	if err != nil {
+		return nil, err
+	}
+
 	return &configDomain.AppConfig{
 		Development: development,
 		Port:        port,
@@ -82,7 +114,52 @@ func initAppConfig() *configDomain.AppConfig {
 			return fmt.Sprintf("http://%s:%d", host, port)
 		}(),
 		AllowedOrigins: aoList,
+		TrustedProxies: trustedProxies,
+		Privacy:        privacy,
+	}, nil
+}
+
+/*Метод чтения TRUSTED_PROXIES — JSON список IP или CIDR (ошибка, чтобы опечатка не открыла подмену IP через X-Forwarded-For)*/
+func initTrustedProxies() ([]string, error) {
+	v := os.Getenv("TRUSTED_PROXIES")
+	if v == "" {
+		return nil, nil
+	}
+
+	var proxies []string
+	if err := json.Unmarshal([]byte(v), &proxies); err != nil {
+		return nil, fmt.Errorf("invalid TRUSTED_PROXIES: %w", err)
+	}
+	for _, p := range proxies {
+		if _, _, err := net.ParseCIDR(p); err != nil && net.ParseIP(p) == nil {
+			return nil, fmt.Errorf("invalid TRUSTED_PROXIES entry %q", p)
+		}
 	}
+
+	return proxies, nil
+}
+
+/*Метод инициализации режима приватности посещений (неизвестный режим — ошибка, чтобы опечатка не включила хранение IP целиком)*/
+func initPrivacyConfig() (configDomain.PrivacyConfig, error) {
+	ipMode := os.Getenv("PRIVACY_IP_MODE")
+	switch ipMode {
+	case "":
+		ipMode = "full"
+	case "full", "truncate", "hash":
+	default:
+		return configDomain.PrivacyConfig{}, fmt.Errorf("PRIVACY_IP_MODE: unknown mode %q (want full, truncate or hash)", ipMode)
+	}
+
+	saltRotation, err := time.ParseDuration(os.Getenv("PRIVACY_SALT_ROTATION"))
+	if err != nil || saltRotation <= 0 {
+		saltRotation = 24 * time.Hour
+	}
+
+	return configDomain.PrivacyConfig{
+		IPMode:       ipMode,
+		HashKey:      os.Getenv("PRIVACY_HASH_KEY"),
+		SaltRotation: saltRotation,
+	}, nil
 }
 
 /*Метод инициализации конфигурации базы данных*/
@@ -97,6 +174,54 @@ func initDatabaseConfig() (*configDomain.DatabaseConfig, error) {
 	}, nil
 }
 
+/*Метод инициализации конфигурации асинхронной записи посещений*/
+func initVisitRecorderConfig() *configDomain.VisitRecorderConfig {
+	queueSize, _ := strconv.Atoi(os.Getenv("VISIT_QUEUE_SIZE"))
+	if queueSize <= 0 {
+		queueSize = 10000
+	}
+
+	batchSize, _ := strconv.Atoi(os.Getenv("VISIT_BATCH_SIZE"))
+	if batchSize <= 0 {
+		batchSize = 500
+	}
+
+	flushInterval, _ := time.ParseDuration(os.Getenv("VISIT_FLUSH_INTERVAL"))
+	if flushInterval <= 0 {
+		flushInterval = time.Second
+	}
+
+	return &configDomain.VisitRecorderConfig{
+		QueueSize:     queueSize,
+		BatchSize:     batchSize,
+		FlushInterval: flushInterval,
+	}
+}
+
+/*Метод инициализации конфигурации кеша ссылок*/
+func initLinkCacheConfig() *configDomain.LinkCacheConfig {
+	size := 10000
+	if v, err := strconv.Atoi(os.Getenv("LINK_CACHE_SIZE")); err == nil && v >= 0 {
+		size = v
+	}
+
+	ttl, err := time.ParseDuration(os.Getenv("LINK_CACHE_TTL"))
+	if err != nil || ttl <= 0 {
+		ttl = 30 * time.Second
+	}
+
+	negativeTTL, err := time.ParseDuration(os.Getenv("LINK_CACHE_NEGATIVE_TTL"))
+	if err != nil || negativeTTL < 0 {
+		negativeTTL = 5 * time.Second
+	}
+
+	return &configDomain.LinkCacheConfig{
+		Size:        size,
+		TTL:         ttl,
+		NegativeTTL: negativeTTL,
+	}
+}
+
 /*Метод загрузки переменных окружения*/
 func load(envPath string) error {
 	err := godotenv.Load(envPath)
@@ -106,3 +231,168 @@ func load(envPath string) error {
 
 	return nil
 }
+
+/*Метод инициализации конфигурации распознавания ботов (невалидный JSON — ошибка, чтобы опечатка не подменила список встроенным)*/
+func initBotConfig() (*configDomain.BotConfig, error) {
+	var userAgents []string
+	if v := os.Getenv("BOT_USER_AGENTS"); v != "" {
+		if err := json.Unmarshal([]byte(v), &userAgents); err != nil {
+			return nil, fmt.Errorf("invalid BOT_USER_AGENTS: %w", err)
+		}
+	}
+
+	return &configDomain.BotConfig{
+		UserAgents: userAgents,
+		SkipVisits: os.Getenv("BOT_VISITS") == "skip",
+	}, nil
+}
+
+/*Метод инициализации конфигурации GeoIP*/
+func initGeoIPConfig() *configDomain.GeoIPConfig {
+	reloadInterval, err := time.ParseDuration(os.Getenv("GEOIP_RELOAD_INTERVAL"))
+	if err != nil || reloadInterval < 0 {
+		reloadInterval = time.Minute
+	}
+
+	return &configDomain.GeoIPConfig{
+		Path:           os.Getenv("GEOIP_DB_PATH"),
+		ReloadInterval: reloadInterval,
+	}
+}
+
+/*Метод инициализации конфигурации хранения посещений*/
+func initVisitRetentionConfig() *configDomain.VisitRetentionConfig {
+	maxAge, err := parseDays(os.Getenv("VISIT_RETENTION"))
+	if err != nil || maxAge < 0 {
+		maxAge = 0
+	}
+
+	interval, _ := time.ParseDuration(os.Getenv("VISIT_PURGE_INTERVAL"))
+	if interval <= 0 {
+		interval = time.Hour
+	}
+
+	batchSize, _ := strconv.Atoi(os.Getenv("VISIT_PURGE_BATCH_SIZE"))
+	if batchSize <= 0 {
+		batchSize = 1000
+	}
+
+	batchPause, err := time.ParseDuration(os.Getenv("VISIT_PURGE_BATCH_PAUSE"))
+	if err != nil || batchPause < 0 {
+		batchPause = 100 * time.Millisecond
+	}
+
+	return &configDomain.VisitRetentionConfig{
+		MaxAge:     maxAge,
+		Interval:   interval,
+		BatchSize:  batchSize,
+		BatchPause: batchPause,
+		DryRun:     os.Getenv("VISIT_PURGE_DRY_RUN") == "true",
+	}
+}
+
+/*Метод инициализации конфигурации дневной агрегации посещений*/
+func initVisitRollupConfig() *configDomain.VisitRollupConfig {
+	interval, _ := time.ParseDuration(os.Getenv("VISIT_ROLLUP_INTERVAL"))
+	if interval <= 0 {
+		interval = time.Hour
+	}
+
+	return &configDomain.VisitRollupConfig{
+		Interval: interval,
+	}
+}
+
+/*Метод инициализации конфигурации корзины ссылок (по умолчанию ссылки хранятся в корзине 30 дней)*/
+func initLinkTrashConfig() *configDomain.LinkTrashConfig {
+	maxAge := 30 * 24 * time.Hour
+	if v := os.Getenv("LINK_TRASH_RETENTION"); v != "" {
+		d, err := parseDays(v)
+		if err == nil && d >= 0 {
+			maxAge = d
+		}
+	}
+
+	interval, _ := time.ParseDuration(os.Getenv("LINK_TRASH_PURGE_INTERVAL"))
+	if interval <= 0 {
+		interval = time.Hour
+	}
+
+	return &configDomain.LinkTrashConfig{
+		MaxAge:   maxAge,
+		Interval: interval,
+	}
+}
+
+/*Метод разбора длительности с поддержкой суток ("90d"); пустая строка — 0*/
+func parseDays(s string) (time.Duration, error) {
+	if s == "" {
+		return 0, nil
+	}
+
+	if days, ok := strings.CutSuffix(s, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil {
+			return 0, err
+		}
+		return time.Duration(n) * 24 * time.Hour, nil
+	}
+
+	return time.ParseDuration(s)
+}
+
+/*Метод инициализации конфигурации аутентификации API (по умолчанию сессия действует сутки)*/
+func initAuthConfig() *configDomain.AuthConfig {
+	sessionTTL, err := parseDays(os.Getenv("SESSION_TTL"))
+	if err != nil || sessionTTL <= 0 {
+		sessionTTL = 24 * time.Hour
+	}
+
+	return &configDomain.AuthConfig{
+		AdminKey:   strings.TrimSpace(os.Getenv("API_ADMIN_KEY")),
+		SessionTTL: sessionTTL,
+	}
+}
+
+/*Метод инициализации конфигурации ограничения частоты запросов (по умолчанию выключено, кроме попыток ввода пароля и входа)*/
+func initRateLimitConfig() *configDomain.RateLimitConfig {
+	maxKeys, _ := strconv.Atoi(os.Getenv("RATE_LIMIT_MAX_KEYS"))
+	if maxKeys <= 0 {
+		maxKeys = 100000
+	}
+
+	return &configDomain.RateLimitConfig{
+		Redirect:   initRateLimitPolicy("RATE_LIMIT_REDIRECT"),
+		CreateLink: initRateLimitPolicy("RATE_LIMIT_CREATE_LINK"),
+		Unlock:     initRateLimitPolicyOr("RATE_LIMIT_UNLOCK", configDomain.RateLimitPolicy{Rate: 5.0 / 60, Burst: 5}),
+		Login:      initRateLimitPolicyOr("RATE_LIMIT_LOGIN", configDomain.RateLimitPolicy{Rate: 10.0 / 60, Burst: 10}),
+		MaxKeys:    maxKeys,
+	}
+}
+
+/*Метод чтения политики, включённой по умолчанию (пустой <prefix>_RPS — def, 0 — выключено)*/
+func initRateLimitPolicyOr(prefix string, def configDomain.RateLimitPolicy) configDomain.RateLimitPolicy {
+	if os.Getenv(prefix+"_RPS") == "" {
+		return def
+	}
+
+	return initRateLimitPolicy(prefix)
+}
+
+/*Метод чтения политики из <prefix>_RPS и <prefix>_BURST (без BURST — ёмкость на секунду запросов)*/
+func initRateLimitPolicy(prefix string) configDomain.RateLimitPolicy {
+	rate, err := strconv.ParseFloat(os.Getenv(prefix+"_RPS"), 64)
+	if err != nil || rate <= 0 {
+		return configDomain.RateLimitPolicy{}
+	}
+
+	burst, _ := strconv.Atoi(os.Getenv(prefix + "_BURST"))
+	if burst <= 0 {
+		burst = int(math.Ceil(rate))
+	}
+
+	return configDomain.RateLimitPolicy{
+		Rate:  rate,
+		Burst: burst,
+	}
+}
@@ -0,0 +1,8 @@
+package apikey
+
+import "errors"
+
+var (
+	/*Не найден (или отозван)*/
+	ErrNotFound = errors.New("api key not found")
+)
@@ -0,0 +1,31 @@
+package apikey
+
+import (
+	"context"
+	"time"
+
+	"link-service/src/domain/entity"
+)
+
+/*Репозиторий для API ключей*/
+type Repository interface {
+	/*Создание ключа*/
+	Create(ctx context.Context, in CreateInput) (entity.APIKey, error)
+	/*Получение действующего (не отозванного) ключа по SHA-256*/
+	GetByHash(ctx context.Context, keyHash string) (entity.APIKey, error)
+	/*Список всех ключей, включая отозванные*/
+	List(ctx context.Context) ([]entity.APIKey, error)
+	/*Отзыв ключа (ErrNotFound, если ключа нет или он уже отозван)*/
+	Revoke(ctx context.Context, id int64) error
+	/*Обновление даты последнего использования*/
+	Touch(ctx context.Context, id int64, usedAt time.Time) error
+}
+
+/*Входные параметры для создания ключа*/
+type CreateInput struct {
+	Name    string
+	Prefix  string
+	KeyHash string
+	Scopes  []string
+	OwnerID int64
+}
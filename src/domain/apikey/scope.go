@@ -0,0 +1,31 @@
+package apikey
+
+import (
+	"slices"
+	"strings"
+)
+
+/*Разрешения API ключей*/
+const (
+	ScopeLinksRead  = "links:read"  /*Чтение ссылок и их истории*/
+	ScopeLinksWrite = "links:write" /*Создание, изменение, удаление и восстановление ссылок*/
+	ScopeVisitsRead = "visits:read" /*Чтение посещений и статистики*/
+)
+
+/*Все допустимые разрешения*/
+var Scopes = []string{ScopeLinksRead, ScopeLinksWrite, ScopeVisitsRead}
+
+/*Метод проверки допустимого разрешения*/
+func IsValidScope(scope string) bool {
+	return slices.Contains(Scopes, scope)
+}
+
+/*Метод разбора списка разрешений, хранящегося через пробел*/
+func ParseScopes(s string) []string {
+	return strings.Fields(s)
+}
+
+/*Метод преобразования списка разрешений в строку для хранения*/
+func FormatScopes(scopes []string) string {
+	return strings.Join(scopes, " ")
+}
@@ -7,5 +7,7 @@ type AppConfig struct {
 	Host        string /*Хост*/
 	LoggingIO   bool   /*Логирование*/
 	BaseURL     string /*Базовый URL*/
+	Privacy     PrivacyConfig /*Режим приватности посещений*/
 	AllowedOrigins []string /*Разрешенные Origin*/
+	TrustedProxies []string /*Прокси (IP или CIDR), которым доверяются X-Forwarded-For и X-Real-IP; пусто — никому*/
 }
\ No newline at end of file
@@ -0,0 +1,9 @@
+package configDomain
+
+import "time"
+
+/*Конфигурация аутентификации API*/
+type AuthConfig struct {
+	AdminKey   string        /*Административный ключ: все разрешения и управление API ключами и пользователями (пусто — не задан)*/
+	SessionTTL time.Duration /*Срок действия сессии пользователя после входа*/
+}
@@ -0,0 +1,7 @@
+package configDomain
+
+/*Конфигурация распознавания ботов при редиректе*/
+type BotConfig struct {
+	UserAgents []string /*Подстроки User-Agent ботов (nil — встроенный список)*/
+	SkipVisits bool     /*Не записывать посещения ботов (иначе записываются с is_bot)*/
+}
@@ -2,6 +2,15 @@ package configDomain
 
 /*Конфигурация приложения*/
 type Config struct {
-	App      AppConfig      /*Конфигурация приложения*/
-	Database DatabaseConfig /*Конфигурация базы данных*/
+	App           AppConfig            /*Конфигурация приложения*/
+	Database      DatabaseConfig       /*Конфигурация базы данных*/
+	VisitRecorder VisitRecorderConfig  /*Конфигурация асинхронной записи посещений*/
+	LinkCache     LinkCacheConfig      /*Конфигурация кеша ссылок*/
+	Bots          BotConfig            /*Конфигурация распознавания ботов*/
+	GeoIP         GeoIPConfig          /*Конфигурация GeoIP*/
+	Retention     VisitRetentionConfig /*Конфигурация хранения посещений*/
+	Rollup        VisitRollupConfig    /*Конфигурация дневной агрегации посещений*/
+	LinkTrash     LinkTrashConfig      /*Конфигурация корзины ссылок*/
+	Auth          AuthConfig           /*Конфигурация аутентификации API*/
+	RateLimit     RateLimitConfig      /*Конфигурация ограничения частоты запросов*/
 }
@@ -0,0 +1,9 @@
+package configDomain
+
+import "time"
+
+/*Конфигурация определения местоположения посещений по IP*/
+type GeoIPConfig struct {
+	Path           string        /*Путь к файлу .mmdb (пусто — местоположение не определяется)*/
+	ReloadInterval time.Duration /*Период проверки изменения файла*/
+}
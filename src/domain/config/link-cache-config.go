@@ -0,0 +1,10 @@
+package configDomain
+
+import "time"
+
+/*Конфигурация кеша поиска ссылок по short_name*/
+type LinkCacheConfig struct {
+	Size        int           /*Максимальное количество записей (0 — кеш выключен)*/
+	TTL         time.Duration /*Время жизни найденной ссылки*/
+	NegativeTTL time.Duration /*Время жизни записи о ненайденной ссылке*/
+}
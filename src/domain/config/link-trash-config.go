@@ -0,0 +1,9 @@
+package configDomain
+
+import "time"
+
+/*Конфигурация корзины ссылок*/
+type LinkTrashConfig struct {
+	MaxAge   time.Duration /*Срок хранения ссылок в корзине (0 — не удаляются)*/
+	Interval time.Duration /*Период фоновой очистки корзины*/
+}
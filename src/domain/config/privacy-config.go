@@ -0,0 +1,10 @@
+package configDomain
+
+import "time"
+
+/*Конфигурация хранения персональных данных посещений*/
+type PrivacyConfig struct {
+	IPMode       string        /*Режим хранения IP: full, truncate или hash*/
+	HashKey      string        /*Ключ HMAC для режима hash (пусто — случайный ключ на время работы процесса)*/
+	SaltRotation time.Duration /*Период смены соли HMAC*/
+}
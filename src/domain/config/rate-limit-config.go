@@ -0,0 +1,16 @@
+package configDomain
+
+/*Конфигурация ограничения частоты запросов (Rate 0 — ограничение выключено)*/
+type RateLimitConfig struct {
+	Redirect   RateLimitPolicy /*Политика для /r/:code (по IP клиента)*/
+	CreateLink RateLimitPolicy /*Политика для POST /api/links и /api/links/import (по API ключу или пользователю)*/
+	Unlock     RateLimitPolicy /*Политика попыток ввода пароля POST /r/:code (по IP и ссылке, включена по умолчанию)*/
+	Login      RateLimitPolicy /*Политика попыток входа POST /api/auth/login (по IP клиента, включена по умолчанию)*/
+	MaxKeys    int             /*Максимум корзин в памяти процесса*/
+}
+
+/*Политика ограничения: корзина токенов*/
+type RateLimitPolicy struct {
+	Rate  float64 /*Запросов в секунду*/
+	Burst int     /*Сколько запросов можно сделать подряд*/
+}
@@ -0,0 +1,10 @@
+package configDomain
+
+import "time"
+
+/*Конфигурация асинхронной записи посещений*/
+type VisitRecorderConfig struct {
+	QueueSize     int           /*Размер очереди в памяти*/
+	BatchSize     int           /*Размер пачки для записи*/
+	FlushInterval time.Duration /*Максимальное время ожидания пачки*/
+}
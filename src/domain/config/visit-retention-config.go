@@ -0,0 +1,12 @@
+package configDomain
+
+import "time"
+
+/*Конфигурация хранения посещений*/
+type VisitRetentionConfig struct {
+	MaxAge     time.Duration /*Срок хранения посещений (0 — хранятся бессрочно)*/
+	Interval   time.Duration /*Период фоновой очистки*/
+	BatchSize  int           /*Максимум строк в одном DELETE*/
+	BatchPause time.Duration /*Пауза между пачками*/
+	DryRun     bool          /*Фоновая очистка только считает устаревшие посещения*/
+}
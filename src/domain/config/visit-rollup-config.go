@@ -0,0 +1,8 @@
+package configDomain
+
+import "time"
+
+/*Конфигурация дневной агрегации посещений*/
+type VisitRollupConfig struct {
+	Interval time.Duration /*Период запуска агрегации*/
+}
@@ -0,0 +1,16 @@
+package entity
+
+import "time"
+
+/*Entity для API ключей*/
+type APIKey struct {
+	ID         int64      /*Идентифиактор записи*/
+	Name       string     /*Название ключа (кому выдан)*/
+	Prefix     string     /*Начало ключа для отображения в списке*/
+	KeyHash    string     /*SHA-256 ключа (сам ключ не хранится)*/
+	Scopes     []string   /*Разрешения ключа*/
+	CreatedAt  time.Time  /*Дата создания*/
+	LastUsedAt *time.Time /*Дата последнего использования (nil — не использовался)*/
+	RevokedAt  *time.Time /*Дата отзыва (nil — ключ действует)*/
+	OwnerID    *int64     /*Пользователь, от имени которого действует ключ (nil — ключ выдан до привязки к пользователям)*/
+}
@@ -0,0 +1,17 @@
+package entity
+
+import "time"
+
+/*Entity для истории изменений ссылки*/
+type LinkEvent struct {
+	ID             int64     /*Идентифиактор записи*/
+	LinkID         int64     /*Идентификатор ссылки*/
+	Type           string    /*Тип изменения: created, updated, deleted, restored*/
+	OldOriginalURL string    /*Исходный URL до изменения (пусто при создании и восстановлении)*/
+	NewOriginalURL string    /*Исходный URL после изменения (пусто при удалении)*/
+	OldShortName   string    /*Короткий URL до изменения*/
+	NewShortName   string    /*Короткий URL после изменения*/
+	Actor          string    /*Кто выполнил изменение (пусто — неизвестно)*/
+	RequestID      string    /*Идентификатор HTTP запроса*/
+	CreatedAt      time.Time /*Дата изменения*/
+}
@@ -4,12 +4,20 @@ import "time"
 
 /*Entity для посещений ссылок*/
 type LinkVisit struct {
-	ID        int64     /*Идентифиактор записи*/
-	LinkID    int64     /*Идентификатор ссылки*/
-	IP        string    /*IP клиента*/
-	UserAgent string    /*User-Agent*/
-	Referer   string    /*Referer*/
-	Status    int       /*HTTP статус редиректа*/
-	CreatedAt time.Time /*Дата создания*/
+	ID             int64     /*Идентифиактор записи*/
+	LinkID         int64     /*Идентификатор ссылки*/
+	IP             string    /*IP клиента*/
+	UserAgent      string    /*User-Agent*/
+	Referer        string    /*Referer*/
+	Status         int       /*HTTP статус редиректа*/
+	Browser        string    /*Семейство браузера из User-Agent*/
+	BrowserVersion string    /*Мажорная версия браузера*/
+	OS             string    /*Операционная система*/
+	Device         string    /*Класс устройства: desktop, mobile, tablet (пусто для ботов)*/
+	IsBot          bool      /*Посещение ботом*/
+	Country        string    /*ISO-код страны по IP*/
+	Region         string    /*Регион по IP*/
+	City           string    /*Город по IP*/
+	CreatedAt      time.Time /*Дата создания*/
 }
 
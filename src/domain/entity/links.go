@@ -4,8 +4,15 @@ import "time"
 
 /*Entity для ссылок*/
 type Link struct {
-	ID          int64     /*Идентифиактор записи*/
-	OriginalURL string    /*Исходный URL*/
-	ShortName   string    /*Короткий URL*/
-	CreatedAt   time.Time /*Дата создания*/
+	ID           int64      /*Идентифиактор записи*/
+	OriginalURL  string     /*Исходный URL*/
+	ShortName    string     /*Короткий URL*/
+	CreatedAt    time.Time  /*Дата создания*/
+	ExpiresAt    *time.Time /*Дата истечения срока действия (nil — бессрочная)*/
+	MaxVisits    *int       /*Лимит посещений (nil — без лимита)*/
+	VisitCount   int64      /*Количество посещений, учтённых в лимите*/
+	PasswordHash string     /*Хеш пароля (пустая строка — без пароля)*/
+	RedirectType int        /*HTTP статус редиректа (301/302/307/308)*/
+	DeletedAt    *time.Time /*Дата перемещения в корзину (nil — ссылка активна)*/
+	OwnerID      *int64     /*Владелец ссылки (nil — без владельца, видна только администраторам)*/
 }
@@ -0,0 +1,12 @@
+package entity
+
+import "time"
+
+/*Entity для пользователей*/
+type User struct {
+	ID           int64     /*Идентифиактор записи*/
+	Username     string    /*Имя для входа*/
+	PasswordHash string    /*Хеш пароля (bcrypt)*/
+	Role         string    /*Роль (admin или member)*/
+	CreatedAt    time.Time /*Дата создания*/
+}
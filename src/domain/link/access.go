@@ -0,0 +1,39 @@
+package link
+
+import "context"
+
+/*Доступ к ссылкам для текущего запроса*/
+type Access struct {
+	UserID int64 /*Пользователь, ссылками которого ограничен доступ (0 без All — ни одной ссылки)*/
+	All    bool  /*Доступ ко всем ссылкам (администратор или SystemAccess)*/
+}
+
+/*Доступ ко всем ссылкам для фоновых задач и вызовов вне API: задаётся явно, без доступа в контексте ссылки не видны*/
+var SystemAccess = Access{All: true}
+
+/*Владелец, которому не принадлежит ни одна ссылка: выборка с ним всегда пуста*/
+const NoOwner int64 = -1
+
+/*Метод добавления в контекст доступа к ссылкам*/
+func WithAccess(ctx context.Context, a Access) context.Context {
+	return context.WithValue(ctx, accessKey, a)
+}
+
+/*Метод получения владельца, которым ограничена выборка (0 — без ограничения, NoOwner — доступа в контексте нет или он пустой)*/
+func OwnerScope(ctx context.Context) int64 {
+	a, ok := ctx.Value(accessKey).(Access)
+	switch {
+	case ok && a.All:
+		return 0
+	case ok && a.UserID != 0:
+		return a.UserID
+	default:
+		return NoOwner
+	}
+}
+
+/*Метод получения пользователя из контекста (0 — не пользователь)*/
+func UserFrom(ctx context.Context) int64 {
+	a, _ := ctx.Value(accessKey).(Access)
+	return a.UserID
+}
@@ -0,0 +1,63 @@
+package link
+
+import (
+	"encoding/base64"
+	"errors"
+	"strconv"
+	"strings"
+)
+
+const (
+	/*Размер страницы по умолчанию*/
+	DefaultCursorLimit = 50
+	/*Максимальный размер страницы*/
+	MaxCursorLimit = 1000
+)
+
+/*Параметры страницы для keyset-пагинации (WHERE id > AfterID ORDER BY id LIMIT Limit)*/
+type Cursor struct {
+	AfterID int64 /*Идентификатор последней записи предыдущей страницы (0 — с начала)*/
+	Limit   int   /*Размер страницы*/
+}
+
+/*Префикс внутри курсора — чтобы формат можно было расширить, не ломая старые курсоры*/
+const cursorPrefix = "id:"
+
+// EncodeCursor кодирует идентификатор последней записи в непрозрачную строку курсора.
+func EncodeCursor(afterID int64) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(cursorPrefix + strconv.FormatInt(afterID, 10)))
+}
+
+// ParseCursor разбирает параметры cursor и limit из запроса.
+//
+// Пустой cursor означает первую страницу, пустой limit — DefaultCursorLimit.
+func ParseCursor(cursor, limit string) (*Cursor, error) {
+	res := &Cursor{Limit: DefaultCursorLimit}
+
+	if cursor = strings.TrimSpace(cursor); cursor != "" {
+		raw, err := base64.RawURLEncoding.DecodeString(cursor)
+		if err != nil {
+			return nil, errors.New("invalid cursor")
+		}
+
+		id, ok := strings.CutPrefix(string(raw), cursorPrefix)
+		if !ok {
+			return nil, errors.New("invalid cursor")
+		}
+
+		res.AfterID, err = strconv.ParseInt(id, 10, 64)
+		if err != nil || res.AfterID < 0 {
+			return nil, errors.New("invalid cursor")
+		}
+	}
+
+	if limit = strings.TrimSpace(limit); limit != "" {
+		n, err := strconv.Atoi(limit)
+		if err != nil || n < 1 || n > MaxCursorLimit {
+			return nil, errors.New("invalid limit (expected 1-" + strconv.Itoa(MaxCursorLimit) + ")")
+		}
+		res.Limit = n
+	}
+
+	return res, nil
+}
@@ -0,0 +1,67 @@
+package link
+
+import (
+	"context"
+
+	"link-service/src/domain/entity"
+)
+
+/*Типы изменений ссылки в истории*/
+const (
+	EventCreated  = "created"
+	EventUpdated  = "updated"
+	EventDeleted  = "deleted"
+	EventRestored = "restored"
+	EventPurged   = "purged"
+)
+
+type ctxKey int
+
+const (
+	actorKey ctxKey = iota
+	requestIDKey
+	accessKey
+)
+
+/*Метод добавления в контекст того, кто выполняет изменение*/
+func WithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, actorKey, actor)
+}
+
+/*Метод получения из контекста того, кто выполняет изменение (пусто — неизвестно)*/
+func ActorFrom(ctx context.Context) string {
+	v, _ := ctx.Value(actorKey).(string)
+	return v
+}
+
+/*Метод добавления в контекст идентификатора запроса*/
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey, id)
+}
+
+/*Метод получения из контекста идентификатора запроса*/
+func RequestIDFrom(ctx context.Context) string {
+	v, _ := ctx.Value(requestIDKey).(string)
+	return v
+}
+
+/*Метод формирования записи истории (before, after — состояние ссылки до и после изменения, nil — нет); actor и request ID берутся из контекста*/
+func NewEvent(ctx context.Context, eventType string, before, after *entity.Link) entity.LinkEvent {
+	e := entity.LinkEvent{
+		Type:      eventType,
+		Actor:     ActorFrom(ctx),
+		RequestID: RequestIDFrom(ctx),
+	}
+	if before != nil {
+		e.LinkID = before.ID
+		e.OldOriginalURL = before.OriginalURL
+		e.OldShortName = before.ShortName
+	}
+	if after != nil {
+		e.LinkID = after.ID
+		e.NewOriginalURL = after.OriginalURL
+		e.NewShortName = after.ShortName
+	}
+
+	return e
+}
@@ -0,0 +1,133 @@
+package link
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+)
+
+/*Поля, по которым разрешена сортировка списка ссылок, и их колонки в БД (только они попадают в ORDER BY)*/
+var SortColumns = map[string]string{
+	"id":            "id",
+	"original_url":  "original_url",
+	"short_name":    "short_name",
+	"created_at":    "created_at",
+	"expires_at":    "expires_at",
+	"max_visits":    "max_visits",
+	"visit_count":   "visit_count",
+	"redirect_type": "redirect_type",
+	"deleted_at":    "deleted_at",
+}
+
+/*Фильтр списка ссылок (пустые поля не применяются)*/
+type Filter struct {
+	Q         string  /*Подстрока в original_url или short_name (без учёта регистра)*/
+	ShortName string  /*Точное совпадение short_name*/
+	IDs       []int64 /*Список идентификаторов (react-admin getMany)*/
+	Trashed   bool    /*Только ссылки в корзине (по умолчанию — только активные)*/
+	OwnerID   int64   /*Только ссылки владельца (задаётся сервисом по доступу из контекста, не из запроса)*/
+}
+
+/*Сортировка списка ссылок (пустое поле — по id)*/
+type Sort struct {
+	Field string /*Поле из SortColumns*/
+	Desc  bool   /*По убыванию*/
+}
+
+/*Параметры выборки списка ссылок*/
+type Query struct {
+	Filter Filter
+	Sort   Sort
+}
+
+/*Метод проверки, что фильтр ничего не ограничивает*/
+func (f Filter) IsEmpty() bool {
+	return f.Q == "" && f.ShortName == "" && f.IDs == nil && !f.Trashed && f.OwnerID == 0
+}
+
+/*Метод проверки, что выборка совпадает с выборкой по умолчанию (все ссылки по id)*/
+func (q Query) IsEmpty() bool {
+	return q.Filter.IsEmpty() && (q.Sort.Field == "" || q.Sort.Field == "id") && !q.Sort.Desc
+}
+
+// ParseFilter парсит фильтр в формате react-admin: {"q":"promo","short_name":"abc","id":[1,2]}.
+//
+// Неизвестные поля считаются ошибкой, чтобы опечатка не превращалась в пустой фильтр.
+func ParseFilter(strFilter string) (Filter, error) {
+	var res Filter
+
+	strFilter = strings.TrimSpace(strFilter)
+	if strFilter == "" {
+		return res, nil
+	}
+
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(strFilter), &raw); err != nil {
+		return res, errors.New("invalid filter")
+	}
+
+	for key, value := range raw {
+		var err error
+
+		switch key {
+		case "q":
+			err = json.Unmarshal(value, &res.Q)
+			res.Q = strings.TrimSpace(res.Q)
+		case "short_name":
+			err = json.Unmarshal(value, &res.ShortName)
+		case "id":
+			res.IDs, err = parseIDs(value)
+		default:
+			return Filter{}, errors.New("invalid filter (unsupported field " + key + ")")
+		}
+
+		if err != nil {
+			return Filter{}, errors.New("invalid filter (" + key + ")")
+		}
+	}
+
+	return res, nil
+}
+
+// ParseSort парсит сортировку в формате react-admin: ["created_at","DESC"].
+func ParseSort(strSort string) (Sort, error) {
+	strSort = strings.TrimSpace(strSort)
+	if strSort == "" {
+		return Sort{}, nil
+	}
+
+	var parts []string
+	if err := json.Unmarshal([]byte(strSort), &parts); err != nil || len(parts) != 2 {
+		return Sort{}, errors.New("invalid sort")
+	}
+
+	if _, ok := SortColumns[parts[0]]; !ok {
+		return Sort{}, errors.New("invalid sort (unsupported field " + parts[0] + ")")
+	}
+
+	switch strings.ToUpper(parts[1]) {
+	case "ASC":
+		return Sort{Field: parts[0]}, nil
+	case "DESC":
+		return Sort{Field: parts[0], Desc: true}, nil
+	default:
+		return Sort{}, errors.New("invalid sort (order must be ASC or DESC)")
+	}
+}
+
+/*Разбор id: одно число или массив чисел*/
+func parseIDs(value json.RawMessage) ([]int64, error) {
+	if bytes.HasPrefix(bytes.TrimSpace(value), []byte("[")) {
+		ids := []int64{}
+		err := json.Unmarshal(value, &ids)
+		return ids, err
+	}
+
+	var id int64
+	if err := json.Unmarshal(value, &id); err != nil {
+		return nil, err
+	}
+
+	return []int64{id}, nil
+}
@@ -0,0 +1,21 @@
+package link
+
+import "net/http"
+
+/*HTTP статус редиректа по умолчанию*/
+const DefaultRedirectType = http.StatusFound
+
+/*Метод проверки допустимого HTTP статуса редиректа*/
+func IsValidRedirectType(status int) bool {
+	switch status {
+	case http.StatusMovedPermanently, http.StatusFound, http.StatusTemporaryRedirect, http.StatusPermanentRedirect:
+		return true
+	default:
+		return false
+	}
+}
+
+/*Метод проверки постоянного редиректа (301/308), который можно кешировать*/
+func IsPermanentRedirect(status int) bool {
+	return status == http.StatusMovedPermanently || status == http.StatusPermanentRedirect
+}
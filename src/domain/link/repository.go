@@ -2,26 +2,67 @@ package link
 
 import (
 	"context"
+	"time"
 
 	"link-service/src/domain/entity"
 )
 
-/*Репозиторий для ссылок*/
+/*Репозиторий для ссылок; создание, обновление, удаление и восстановление записываются в историю в той же транзакции*/
 type Repository interface {
-	/*Список ссылок*/
-	List(ctx context.Context) ([]entity.Link, error)
-	/*Список ссылок с range*/
-	ListWithRange(ctx context.Context, rng *Range) ([]entity.Link, error)
-	/*Общее количество ссылок*/
-	Count(ctx context.Context) (int64, error)
+	/*Список ссылок с фильтром и сортировкой*/
+	List(ctx context.Context, q Query) ([]entity.Link, error)
+	/*Список ссылок с range, фильтром и сортировкой*/
+	ListWithRange(ctx context.Context, rng *Range, q Query) ([]entity.Link, error)
+	/*Список ссылок после курсора (keyset-пагинация) с фильтром*/
+	ListAfter(ctx context.Context, cur *Cursor, f Filter) ([]entity.Link, error)
+	/*Обход ссылок с фильтром и сортировкой построчно, без загрузки всего списка в память (ошибка fn прерывает обход)*/
+	Each(ctx context.Context, q Query, fn func(entity.Link) error) error
+	/*Количество ссылок, подходящих под фильтр*/
+	Count(ctx context.Context, f Filter) (int64, error)
 	/*Получение ссылки по идентификатору*/
 	Get(ctx context.Context, id int64) (entity.Link, error)
 	/*Получение ссылки по short_name*/
 	GetByShortName(ctx context.Context, shortName string) (entity.Link, error)
 	/*Создание ссылки*/
-	Create(ctx context.Context, originalURL, shortName string) (entity.Link, error)
+	Create(ctx context.Context, in CreateInput) (entity.Link, error)
+	/*Пакетное создание ссылок в одной транзакции: строки с занятым short_name пропускаются (atomic — при любом конфликте транзакция откатывается и возвращается ErrShortNameConflict)*/
+	CreateBatch(ctx context.Context, in []CreateInput, atomic bool) ([]BatchResult, error)
 	/*Обновление ссылки*/
-	Update(ctx context.Context, id int64, originalURL, shortName string) (entity.Link, error)
-	/*Удаление ссылки*/
+	Update(ctx context.Context, id int64, in UpdateInput) (entity.Link, error)
+	/*Перемещение ссылки в корзину (short_name остаётся занятым, посещения сохраняются)*/
 	Delete(ctx context.Context, id int64) error
+	/*Восстановление ссылки из корзины (ErrNotFound, если ссылки в корзине нет)*/
+	Restore(ctx context.Context, id int64) (entity.Link, error)
+	/*Окончательное удаление ссылок, попавших в корзину раньше before, вместе с посещениями; в историю пишется запись purged*/
+	PurgeTrashed(ctx context.Context, before time.Time) (int64, error)
+	/*История изменений ссылки, включая ссылку в корзине и окончательно удалённую (от старых к новым; ErrNotFound, если записей нет)*/
+	History(ctx context.Context, id int64) ([]entity.LinkEvent, error)
+}
+
+/*Входные параметры для создания ссылки*/
+type CreateInput struct {
+	OriginalURL  string
+	ShortName    string
+	ExpiresAt    *time.Time
+	MaxVisits    *int
+	PasswordHash string
+	RedirectType int
+	OwnerID      *int64 /*Владелец (nil — без владельца)*/
+}
+
+/*Результат создания ссылки в пакете*/
+type BatchResult struct {
+	Link     entity.Link /*Созданная ссылка*/
+	Conflict bool        /*short_name уже занят — ссылка не создана*/
+}
+
+/*Входные параметры для обновления ссылки*/
+type UpdateInput struct {
+	OriginalURL  string
+	ShortName    string
+	ExpiresAt    *time.Time
+	MaxVisits    *int
+	PasswordHash string
+	RedirectType int
+	OwnerID      *int64 /*Новый владелец (nil — оставить как есть)*/
 }
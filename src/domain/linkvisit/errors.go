@@ -0,0 +1,10 @@
+package linkvisit
+
+import "errors"
+
+var (
+	/*Лимит посещений ссылки исчерпан*/
+	ErrLimitReached = errors.New("link visit limit reached")
+	/*Пачка отклонена из-за данных отдельных строк (например, ссылка уже удалена)*/
+	ErrInvalidRow = errors.New("link visit row rejected")
+)
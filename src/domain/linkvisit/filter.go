@@ -0,0 +1,119 @@
+package linkvisit
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+)
+
+/*Фильтр списка посещений (нулевые значения не применяются)*/
+type Filter struct {
+	LinkID  int64      /*Идентификатор ссылки*/
+	From    *time.Time /*created_at >= From*/
+	To      *time.Time /*created_at < To*/
+	Status  int        /*HTTP статус*/
+	IP      string     /*Подстрока IP*/
+	Referer string     /*Подстрока Referer (без учёта регистра)*/
+	Browser string     /*Семейство браузера*/
+	OS      string     /*Операционная система*/
+	Device  Device     /*Класс устройства*/
+	IsBot   *bool      /*Только боты (true) или только люди (false)*/
+	OwnerID int64      /*Только посещения ссылок владельца (задаётся сервисом по доступу из контекста, не из запроса)*/
+}
+
+/*Метод проверки, что фильтр ничего не ограничивает*/
+func (f Filter) IsEmpty() bool {
+	return f == Filter{}
+}
+
+/*Поля фильтра в JSON (react-admin)*/
+type filterJSON struct {
+	LinkID  *int64  `json:"link_id"`
+	From    *string `json:"from"`
+	To      *string `json:"to"`
+	Status  *int    `json:"status"`
+	IP      *string `json:"ip"`
+	Referer *string `json:"referer"`
+	Browser *string `json:"browser"`
+	OS      *string `json:"os"`
+	Device  *string `json:"device"`
+	IsBot   *bool   `json:"is_bot"`
+}
+
+// ParseFilter парсит фильтр в формате react-admin: {"link_id":1,"from":"2025-01-01","status":302,"ip":"10.0.","referer":"google","device":"mobile","is_bot":false}.
+//
+// from и to принимаются в формате RFC 3339 или как дата 2006-01-02 (UTC).
+func ParseFilter(strFilter string) (Filter, error) {
+	var res Filter
+
+	strFilter = strings.TrimSpace(strFilter)
+	if strFilter == "" {
+		return res, nil
+	}
+
+	var raw filterJSON
+	dec := json.NewDecoder(bytes.NewReader([]byte(strFilter)))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&raw); err != nil {
+		return res, errors.New("invalid filter")
+	}
+
+	if raw.LinkID != nil {
+		res.LinkID = *raw.LinkID
+	}
+	if raw.Status != nil {
+		res.Status = *raw.Status
+	}
+	if raw.IP != nil {
+		res.IP = *raw.IP
+	}
+	if raw.Referer != nil {
+		res.Referer = *raw.Referer
+	}
+	if raw.Browser != nil {
+		res.Browser = *raw.Browser
+	}
+	if raw.OS != nil {
+		res.OS = *raw.OS
+	}
+	if raw.Device != nil && *raw.Device != "" {
+		res.Device = Device(*raw.Device)
+		if !res.Device.IsValid() {
+			return Filter{}, errors.New("invalid filter (device)")
+		}
+	}
+	res.IsBot = raw.IsBot
+
+	var err error
+	if raw.From != nil {
+		if res.From, err = ParseTime(*raw.From); err != nil {
+			return Filter{}, errors.New("invalid filter (from)")
+		}
+	}
+	if raw.To != nil {
+		if res.To, err = ParseTime(*raw.To); err != nil {
+			return Filter{}, errors.New("invalid filter (to)")
+		}
+	}
+
+	return res, nil
+}
+
+// ParseTime парсит время в формате RFC 3339 или дату 2006-01-02 (UTC); пустая строка — nil.
+func ParseTime(s string) (*time.Time, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return nil, nil
+	}
+
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		if t, err = time.Parse(time.DateOnly, s); err != nil {
+			return nil, err
+		}
+	}
+
+	return &t, nil
+}
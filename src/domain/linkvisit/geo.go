@@ -0,0 +1,14 @@
+package linkvisit
+
+/*Местоположение клиента по IP*/
+type Location struct {
+	Country string /*ISO-код страны (DE, US, ...)*/
+	Region  string /*Регион (первое подразделение страны)*/
+	City    string /*Город*/
+}
+
+/*Определение местоположения по IP без сетевых запросов*/
+type GeoLocator interface {
+	/*Местоположение IP (пустое, если IP не найден или некорректен)*/
+	Locate(ip string) Location
+}
@@ -2,6 +2,7 @@ package linkvisit
 
 import (
 	"context"
+	"time"
 
 	"link-service/src/domain/entity"
 	"link-service/src/domain/link"
@@ -11,10 +12,32 @@ import (
 type Repository interface {
 	/*Создание записи посещения*/
 	Create(ctx context.Context, in CreateInput) (entity.LinkVisit, error)
-	/*Список посещений с range*/
-	ListWithRange(ctx context.Context, rng *link.Range) ([]entity.LinkVisit, error)
-	/*Общее количество посещений*/
-	Count(ctx context.Context) (int64, error)
+	/*Атомарное создание записи посещения с учётом лимита посещений ссылки (ErrLimitReached, если лимит исчерпан)*/
+	CreateWithinLimit(ctx context.Context, in CreateInput) (entity.LinkVisit, error)
+	/*Пакетное создание записей посещений (всё или ничего: при ошибке не записано ни одной; ErrInvalidRow — виноваты данные строк)*/
+	CreateBatch(ctx context.Context, in []CreateInput) error
+	/*Список посещений с range и фильтром*/
+	ListWithRange(ctx context.Context, rng *link.Range, f Filter) ([]entity.LinkVisit, error)
+	/*Список посещений после курсора (keyset-пагинация) с фильтром*/
+	ListAfter(ctx context.Context, cur *link.Cursor, f Filter) ([]entity.LinkVisit, error)
+	/*Обход посещений по фильтру в порядке id построчно, без загрузки всего списка в память (ошибка fn прерывает обход)*/
+	Each(ctx context.Context, f Filter, fn func(entity.LinkVisit) error) error
+	/*Количество посещений, подходящих под фильтр*/
+	Count(ctx context.Context, f Filter) (int64, error)
+	/*Статистика посещений ссылки за период*/
+	Stats(ctx context.Context, q StatsQuery) (Stats, error)
+	/*Количество посещений, созданных раньше before*/
+	CountBefore(ctx context.Context, before time.Time) (int64, error)
+	/*Удаление не более limit самых старых посещений, созданных раньше before; возвращает число удалённых*/
+	DeleteBefore(ctx context.Context, before time.Time, limit int) (int64, error)
+	/*Количество посещений за период, сгруппированное по ссылке, статусу, признаку бота и рефереру*/
+	CountGroups(ctx context.Context, q GroupQuery) ([]VisitGroup, error)
+	/*Сохранение дневных счётчиков за day (счётчики с теми же ключами заменяются) и сдвиг границы агрегации*/
+	SaveDaily(ctx context.Context, day time.Time, counts []DailyCount) error
+	/*Дневные счётчики ссылки за дни [from, to)*/
+	ListDaily(ctx context.Context, linkID int64, from, to time.Time) ([]DailyCount, error)
+	/*Состояние дневной агрегации*/
+	RollupState(ctx context.Context) (RollupState, error)
 }
 
 /*Входные параметры для создания посещения*/
@@ -24,5 +47,7 @@ type CreateInput struct {
 	UserAgent string
 	Referer   string
 	Status    int
+	Agent     UserAgent /*Разобранный User-Agent*/
+	Geo       Location  /*Местоположение по IP*/
 }
 
@@ -0,0 +1,83 @@
+package linkvisit
+
+import (
+	"cmp"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+)
+
+/*Параметры группировки посещений за период [From, To)*/
+type GroupQuery struct {
+	LinkID int64 /*0 — все ссылки*/
+	From   time.Time
+	To     time.Time
+}
+
+/*Количество посещений с одинаковыми ссылкой, статусом, признаком бота и реферером*/
+type VisitGroup struct {
+	LinkID  int64
+	Status  int
+	IsBot   bool
+	Referer string
+	Count   int64
+}
+
+/*Дневной счётчик посещений ссылки*/
+type DailyCount struct {
+	LinkID      int64
+	Day         time.Time /*Начало суток (UTC)*/
+	Status      int
+	IsBot       bool
+	RefererHost string /*Хост реферера (пусто — прямой переход)*/
+	Visits      int64
+}
+
+/*Состояние дневной агрегации посещений*/
+type RollupState struct {
+	RolledUntil  time.Time /*Дни раньше этой границы агрегированы (нулевое — агрегации не было)*/
+	FirstVisitAt time.Time /*Время самого старого посещения (нулевое — посещений нет)*/
+}
+
+// RefererHost возвращает хост реферера в нижнем регистре без "www.".
+//
+// Пустая строка — прямой переход или реферер, который не разбирается как URL.
+func RefererHost(referer string) string {
+	u, err := url.Parse(strings.TrimSpace(referer))
+	if err != nil {
+		return ""
+	}
+
+	host := strings.ToLower(u.Hostname())
+	return strings.TrimPrefix(host, "www.")
+}
+
+/*Количество посещений по хостам рефереров по убыванию (при равенстве — по хосту)*/
+func RefererHosts(groups []VisitGroup, includeBots bool) []ValueCount {
+	counts := map[string]int64{}
+	for _, g := range groups {
+		if g.IsBot && !includeBots {
+			continue
+		}
+		counts[RefererHost(g.Referer)] += g.Count
+	}
+
+	return SortValueCounts(counts)
+}
+
+/*Счётчики по убыванию количества (при равенстве — по значению, как ORDER BY count DESC, value)*/
+func SortValueCounts(counts map[string]int64) []ValueCount {
+	res := make([]ValueCount, 0, len(counts))
+	for value, count := range counts {
+		res = append(res, ValueCount{Value: value, Count: count})
+	}
+	slices.SortFunc(res, func(a, b ValueCount) int {
+		if a.Count != b.Count {
+			return cmp.Compare(b.Count, a.Count)
+		}
+		return cmp.Compare(a.Value, b.Value)
+	})
+
+	return res
+}
@@ -0,0 +1,22 @@
+package linkvisit
+
+import (
+	"testing"
+
+	"github.com/go-playground/assert/v2"
+)
+
+func TestRefererHost(t *testing.T) {
+	cases := map[string]string{
+		"":                                     "",
+		"https://www.Google.com/search?q=link": "google.com",
+		"http://t.co/abc":                      "t.co",
+		"https://news.ycombinator.com:443/":    "news.ycombinator.com",
+		"android-app://com.slack/":             "com.slack",
+		"not a url":                            "",
+	}
+
+	for referer, want := range cases {
+		assert.Equal(t, want, RefererHost(referer))
+	}
+}
@@ -0,0 +1,92 @@
+package linkvisit
+
+import "time"
+
+/*Интервал группировки посещений по времени*/
+type Interval string
+
+const (
+	IntervalHour Interval = "hour" /*По часам*/
+	IntervalDay  Interval = "day"  /*По дням*/
+	IntervalWeek Interval = "week" /*По неделям (с понедельника)*/
+)
+
+/*Метод проверки интервала*/
+func (i Interval) IsValid() bool {
+	switch i {
+	case IntervalHour, IntervalDay, IntervalWeek:
+		return true
+	default:
+		return false
+	}
+}
+
+/*Метод получения начала интервала, в который попадает t (в UTC)*/
+func (i Interval) Truncate(t time.Time) time.Time {
+	t = t.UTC()
+
+	switch i {
+	case IntervalHour:
+		return t.Truncate(time.Hour)
+	case IntervalWeek:
+		day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+		return day.AddDate(0, 0, -(int(day.Weekday())+6)%7)
+	default:
+		return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	}
+}
+
+/*Метод получения начала следующего интервала*/
+func (i Interval) Next(start time.Time) time.Time {
+	switch i {
+	case IntervalHour:
+		return start.Add(time.Hour)
+	case IntervalWeek:
+		return start.AddDate(0, 0, 7)
+	default:
+		return start.AddDate(0, 0, 1)
+	}
+}
+
+/*Параметры статистики посещений ссылки за период [From, To)*/
+type StatsQuery struct {
+	LinkID      int64
+	From        time.Time
+	To          time.Time
+	Interval    Interval
+	Top         int  /*Количество записей в топах*/
+	IncludeBots bool /*Учитывать посещения ботов (по умолчанию исключаются)*/
+}
+
+/*Количество переходов за интервал*/
+type Bucket struct {
+	Start  time.Time /*Начало интервала (UTC)*/
+	Clicks int64
+}
+
+/*Количество посещений с одинаковым значением поля*/
+type ValueCount struct {
+	Value string
+	Count int64
+}
+
+/*Количество посещений с одинаковым HTTP статусом*/
+type StatusCount struct {
+	Status int
+	Count  int64
+}
+
+/*Статистика посещений ссылки (Series — только непустые интервалы по возрастанию)*/
+type Stats struct {
+	Total            int64 /*Переходы (с ботами — только при IncludeBots)*/
+	Bots             int64 /*Посещения ботами за период (всегда, для оценки отсеянного)*/
+	Series           []Bucket
+	TopReferers      []ValueCount
+	TopUserAgents    []ValueCount
+	Statuses         []StatusCount
+	Browsers         []ValueCount /*Топ семейств браузеров*/
+	OperatingSystems []ValueCount /*Топ операционных систем*/
+	Devices          []ValueCount /*Классы устройств (пусто — боты)*/
+	Countries        []ValueCount /*Страны (пусто — не определена)*/
+	RefererHosts     []ValueCount /*Хосты рефереров, все по убыванию (пусто — прямой переход)*/
+}
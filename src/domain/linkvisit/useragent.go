@@ -0,0 +1,133 @@
+package linkvisit
+
+import (
+	"regexp"
+	"strings"
+)
+
+/*Класс устройства клиента*/
+type Device string
+
+const (
+	DeviceDesktop Device = "desktop" /*Компьютер*/
+	DeviceMobile  Device = "mobile"  /*Телефон*/
+	DeviceTablet  Device = "tablet"  /*Планшет*/
+)
+
+/*Метод проверки, что класс устройства поддерживается*/
+func (d Device) IsValid() bool {
+	switch d {
+	case DeviceDesktop, DeviceMobile, DeviceTablet:
+		return true
+	}
+	return false
+}
+
+/*Результат разбора User-Agent*/
+type UserAgent struct {
+	Browser        string /*Семейство браузера (Chrome, Firefox, ...)*/
+	BrowserVersion string /*Мажорная версия браузера*/
+	OS             string /*Операционная система*/
+	Device         Device /*Класс устройства (пусто для ботов и пустого User-Agent)*/
+	IsBot          bool   /*Краулер, скрипт или превью-бот*/
+}
+
+/*Правило распознавания браузера: первое совпавшее правило побеждает, поэтому более частные идут раньше*/
+type browserRule struct {
+	name string
+	re   *regexp.Regexp
+}
+
+var browserRules = []browserRule{
+	{"Edge", regexp.MustCompile(`(?:Edg|EdgA|EdgiOS|Edge)/(\d+)`)},
+	{"Opera", regexp.MustCompile(`(?:OPR|OPiOS|Opera)/(\d+)`)},
+	{"Yandex Browser", regexp.MustCompile(`YaBrowser/(\d+)`)},
+	{"Samsung Internet", regexp.MustCompile(`SamsungBrowser/(\d+)`)},
+	{"Vivaldi", regexp.MustCompile(`Vivaldi/(\d+)`)},
+	{"UC Browser", regexp.MustCompile(`UCBrowser/(\d+)`)},
+	{"Firefox", regexp.MustCompile(`(?:Firefox|FxiOS)/(\d+)`)},
+	{"Chrome", regexp.MustCompile(`(?:Chrome|CriOS)/(\d+)`)},
+	{"Safari", regexp.MustCompile(`Version/(\d+).*Safari/`)},
+	{"Internet Explorer", regexp.MustCompile(`(?:MSIE |Trident/.*rv:)(\d+)`)},
+	{"curl", regexp.MustCompile(`^curl/(\d+)`)},
+	{"Wget", regexp.MustCompile(`^Wget/(\d+)`)},
+}
+
+/*Правило распознавания ОС*/
+type osRule struct {
+	name   string
+	substr string
+}
+
+/*iOS проверяется раньше macOS: в User-Agent iPhone есть "like Mac OS X"*/
+var osRules = []osRule{
+	{"Windows", "Windows"},
+	{"Android", "Android"},
+	{"iOS", "iPhone"},
+	{"iOS", "iPad"},
+	{"iOS", "iPod"},
+	{"macOS", "Macintosh"},
+	{"Chrome OS", "CrOS"},
+	{"Linux", "Linux"},
+}
+
+/*Признаки ботов (в нижнем регистре)*/
+var botMarkers = []string{
+	"bot", "crawler", "spider", "slurp", "crawl", "preview",
+	"facebookexternalhit", "whatsapp", "telegram", "embedly", "headless",
+	"curl/", "wget/", "python-requests", "python-urllib", "go-http-client",
+	"okhttp", "java/", "libwww-perl", "httpclient", "axios/", "node-fetch",
+	"lighthouse", "pingdom", "uptime", "monitor",
+}
+
+// ParseUserAgent разбирает User-Agent по встроенному набору правил без сетевых запросов.
+//
+// Неизвестные значения остаются пустыми, в том числе для пустого User-Agent.
+func ParseUserAgent(ua string) UserAgent {
+	ua = strings.TrimSpace(ua)
+	if ua == "" {
+		return UserAgent{}
+	}
+
+	var res UserAgent
+
+	for _, rule := range browserRules {
+		if m := rule.re.FindStringSubmatch(ua); m != nil {
+			res.Browser, res.BrowserVersion = rule.name, m[1]
+			break
+		}
+	}
+
+	for _, rule := range osRules {
+		if strings.Contains(ua, rule.substr) {
+			res.OS = rule.name
+			break
+		}
+	}
+
+	lower := strings.ToLower(ua)
+	for _, marker := range botMarkers {
+		if strings.Contains(lower, marker) {
+			res.IsBot = true
+			return res
+		}
+	}
+
+	res.Device = device(ua, res.OS)
+
+	return res
+}
+
+/*Метод определения класса устройства*/
+func device(ua, os string) Device {
+	switch {
+	case strings.Contains(ua, "iPad"), strings.Contains(ua, "Tablet"):
+		return DeviceTablet
+	case os == "Android" && !strings.Contains(ua, "Mobile"):
+		// Android-планшеты не пишут Mobile в User-Agent
+		return DeviceTablet
+	case strings.Contains(ua, "Mobi"), strings.Contains(ua, "iPhone"), strings.Contains(ua, "iPod"):
+		return DeviceMobile
+	}
+	return DeviceDesktop
+}
@@ -0,0 +1,42 @@
+package linkvisit
+
+import (
+	"testing"
+
+	"github.com/go-playground/assert/v2"
+)
+
+func TestParseUserAgent(t *testing.T) {
+	for ua, want := range map[string]UserAgent{
+		"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/124.0.0.0 Safari/537.36": {
+			Browser: "Chrome", BrowserVersion: "124", OS: "Windows", Device: DeviceDesktop,
+		},
+		"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/124.0.0.0 Safari/537.36 Edg/124.0.2478.51": {
+			Browser: "Edge", BrowserVersion: "124", OS: "Windows", Device: DeviceDesktop,
+		},
+		"Mozilla/5.0 (iPhone; CPU iPhone OS 17_4 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.4 Mobile/15E148 Safari/604.1": {
+			Browser: "Safari", BrowserVersion: "17", OS: "iOS", Device: DeviceMobile,
+		},
+		"Mozilla/5.0 (iPad; CPU OS 16_6 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) CriOS/120.0.6099.119 Mobile/15E148 Safari/604.1": {
+			Browser: "Chrome", BrowserVersion: "120", OS: "iOS", Device: DeviceTablet,
+		},
+		"Mozilla/5.0 (Linux; Android 14; Pixel 8) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/124.0.0.0 Mobile Safari/537.36": {
+			Browser: "Chrome", BrowserVersion: "124", OS: "Android", Device: DeviceMobile,
+		},
+		"Mozilla/5.0 (Linux; Android 13; SM-X700) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/124.0.0.0 Safari/537.36": {
+			Browser: "Chrome", BrowserVersion: "124", OS: "Android", Device: DeviceTablet,
+		},
+		"Mozilla/5.0 (Macintosh; Intel Mac OS X 14.4; rv:125.0) Gecko/20100101 Firefox/125.0": {
+			Browser: "Firefox", BrowserVersion: "125", OS: "macOS", Device: DeviceDesktop,
+		},
+		"Mozilla/5.0 (compatible; Googlebot/2.1; +http://www.google.com/bot.html)": {
+			IsBot: true,
+		},
+		"curl/8.5.0": {
+			Browser: "curl", BrowserVersion: "8", IsBot: true,
+		},
+		"": {},
+	} {
+		assert.Equal(t, want, ParseUserAgent(ua))
+	}
+}
@@ -0,0 +1,32 @@
+package ratelimit
+
+import (
+	"context"
+	"time"
+)
+
+/*Политика ограничения: корзина токенов на Burst запросов, пополняется на Rate токенов в секунду*/
+type Policy struct {
+	Rate  float64 /*Скорость пополнения, запросов в секунду (0 — ограничение выключено)*/
+	Burst int     /*Ёмкость корзины: сколько запросов можно сделать подряд*/
+}
+
+/*Метод проверки, что ограничение включено*/
+func (p Policy) Enabled() bool {
+	return p.Rate > 0 && p.Burst > 0
+}
+
+/*Результат списания токена*/
+type Result struct {
+	Allowed    bool          /*Запрос пропущен*/
+	Limit      int           /*Ёмкость корзины*/
+	Remaining  int           /*Сколько запросов осталось прямо сейчас*/
+	RetryAfter time.Duration /*Через сколько появится следующий токен (0 — запрос пропущен)*/
+	Reset      time.Duration /*Через сколько корзина заполнится полностью*/
+}
+
+/*Хранилище корзин токенов по ключу (IP, API ключ)*/
+type Store interface {
+	/*Списание одного токена из корзины key по политике p на момент now*/
+	Take(ctx context.Context, key string, p Policy, now time.Time) (Result, error)
+}
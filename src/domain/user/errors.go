@@ -0,0 +1,10 @@
+package user
+
+import "errors"
+
+var (
+	/*Не найден (для сессии — неизвестна или истекла)*/
+	ErrNotFound = errors.New("user not found")
+	/*Конфликт*/
+	ErrUsernameConflict = errors.New("username already exists")
+)
@@ -0,0 +1,35 @@
+package user
+
+import (
+	"context"
+	"time"
+
+	"link-service/src/domain/entity"
+)
+
+/*Репозиторий для пользователей и их сессий*/
+type Repository interface {
+	/*Создание пользователя (ErrUsernameConflict, если имя занято)*/
+	Create(ctx context.Context, in CreateInput) (entity.User, error)
+	/*Получение пользователя по идентификатору*/
+	Get(ctx context.Context, id int64) (entity.User, error)
+	/*Получение пользователя по имени для входа*/
+	GetByUsername(ctx context.Context, username string) (entity.User, error)
+	/*Список всех пользователей*/
+	List(ctx context.Context) ([]entity.User, error)
+	/*Создание сессии пользователя (хранится только SHA-256 токена)*/
+	CreateSession(ctx context.Context, userID int64, tokenHash string, expiresAt time.Time) error
+	/*Получение пользователя по действующей сессии (ErrNotFound, если сессии нет или она истекла)*/
+	GetBySession(ctx context.Context, tokenHash string) (entity.User, error)
+	/*Удаление сессии (выход)*/
+	DeleteSession(ctx context.Context, tokenHash string) error
+	/*Удаление истёкших сессий; возвращает число удалённых*/
+	DeleteExpiredSessions(ctx context.Context) (int64, error)
+}
+
+/*Входные параметры для создания пользователя*/
+type CreateInput struct {
+	Username     string
+	PasswordHash string
+	Role         string
+}
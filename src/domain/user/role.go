@@ -0,0 +1,12 @@
+package user
+
+/*Роли пользователей*/
+const (
+	RoleAdmin  = "admin"  /*Видит и меняет все ссылки, управляет пользователями и ключами*/
+	RoleMember = "member" /*Видит и меняет только свои ссылки*/
+)
+
+/*Метод проверки допустимой роли*/
+func IsValidRole(role string) bool {
+	return role == RoleAdmin || role == RoleMember
+}
@@ -0,0 +1,15 @@
+package user
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+)
+
+/*Префикс токенов сессий (чтобы их можно было отличить от API ключей)*/
+const SessionTokenPrefix = "lss_"
+
+/*Метод получения SHA-256 токена сессии (сам токен не хранится)*/
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
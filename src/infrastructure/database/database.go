@@ -3,15 +3,25 @@ package database
 import (
 	"database/sql"
 	"fmt"
+	"net/url"
 
 	"link-service/src/infrastructure/database/postgres"
+	"link-service/src/infrastructure/database/sqlite"
 
 	configDomain "link-service/src/domain/config"
 )
 
+/*Поддерживаемые драйверы хранилища*/
+const (
+	DriverPostgres = "postgres" /*PostgreSQL*/
+	DriverSqlite   = "sqlite"   /*SQLite (файл рядом с сервисом)*/
+	DriverMemory   = "memory"   /*Хранилище в памяти (без базы данных)*/
+)
+
 /*Экземпляр базы данных*/
 type DatabaseImpl struct {
 	instance  *sql.DB
+	driver    string
 	config    configDomain.DatabaseConfig
 	loggingIO bool
 }
@@ -26,12 +36,35 @@ func NewDatabaseImpl(config configDomain.DatabaseConfig, loggingIO bool) *Databa
 
 /*Метод подключения к базе данных*/
 func (d *DatabaseImpl) Connect(config configDomain.DatabaseConfig) error {
-	db, err := postgres.Open(config.URL)
+	driver, err := DriverFromURL(config.URL)
 	if err != nil {
 		return err
 	}
 
-	d.instance = db
+	d.driver = driver
+
+	switch driver {
+	case DriverMemory:
+		if d.loggingIO {
+			fmt.Println("Using in-memory storage")
+		}
+
+		return nil
+	case DriverSqlite:
+		db, err := sqlite.Open(config.URL)
+		if err != nil {
+			return err
+		}
+
+		d.instance = db
+	default:
+		db, err := postgres.Open(config.URL)
+		if err != nil {
+			return err
+		}
+
+		d.instance = db
+	}
 
 	if d.loggingIO {
 		fmt.Println("Database connected successfully")
@@ -40,6 +73,11 @@ func (d *DatabaseImpl) Connect(config configDomain.DatabaseConfig) error {
 	return nil
 }
 
+/*Метод получения драйвера хранилища*/
+func (d *DatabaseImpl) Driver() string {
+	return d.driver
+}
+
 /*Метод получения экземпляра базы данных*/
 func (d *DatabaseImpl) GetInstance() any {
 	return d.instance
@@ -61,9 +99,32 @@ func (d *DatabaseImpl) Disconnect() error {
 
 /*Метод проверки соединения с базой данных*/
 func (d *DatabaseImpl) Ping() error {
+	if d.driver == DriverMemory {
+		return nil
+	}
+
 	if d.instance == nil {
 		return fmt.Errorf("db not connected")
 	}
 
 	return d.instance.Ping()
 }
+
+/*Метод определения драйвера по схеме DATABASE_URL*/
+func DriverFromURL(databaseURL string) (string, error) {
+	u, err := url.Parse(databaseURL)
+	if err != nil {
+		return "", fmt.Errorf("invalid DATABASE_URL: %w", err)
+	}
+
+	switch u.Scheme {
+	case "postgres", "postgresql":
+		return DriverPostgres, nil
+	case "sqlite":
+		return DriverSqlite, nil
+	case "memory":
+		return DriverMemory, nil
+	default:
+		return "", fmt.Errorf("unsupported DATABASE_URL scheme %q", u.Scheme)
+	}
+}
@@ -25,4 +25,3 @@ func Open(databaseURL string) (*sql.DB, error) {
 
 	return db, nil
 }
-
@@ -0,0 +1,108 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: link_visit_daily.sql
+
+package sqlcdb
+
+import (
+	"context"
+	"time"
+)
+
+const getLinkVisitRolledUntil = `-- name: GetLinkVisitRolledUntil :one
+SELECT rolled_until FROM link_visit_rollup_state
+WHERE id = 1
+`
+
+func (q *Queries) GetLinkVisitRolledUntil(ctx context.Context) (time.Time, error) {
+	row := q.db.QueryRowContext(ctx, getLinkVisitRolledUntil)
+	var rolled_until time.Time
+	err := row.Scan(&rolled_until)
+	return rolled_until, err
+}
+
+const listLinkVisitDaily = `-- name: ListLinkVisitDaily :many
+SELECT link_id, day, status, is_bot, referer_host, visits
+FROM link_visit_daily
+WHERE link_id = $1
+  AND day >= $2
+  AND day < $3
+ORDER BY day
+`
+
+type ListLinkVisitDailyParams struct {
+	LinkID  int64     `json:"link_id"`
+	FromDay time.Time `json:"from_day"`
+	ToDay   time.Time `json:"to_day"`
+}
+
+func (q *Queries) ListLinkVisitDaily(ctx context.Context, arg ListLinkVisitDailyParams) ([]LinkVisitDaily, error) {
+	rows, err := q.db.QueryContext(ctx, listLinkVisitDaily, arg.LinkID, arg.FromDay, arg.ToDay)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []LinkVisitDaily
+	for rows.Next() {
+		var i LinkVisitDaily
+		if err := rows.Scan(
+			&i.LinkID,
+			&i.Day,
+			&i.Status,
+			&i.IsBot,
+			&i.RefererHost,
+			&i.Visits,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const setLinkVisitRolledUntil = `-- name: SetLinkVisitRolledUntil :exec
+INSERT INTO link_visit_rollup_state (id, rolled_until)
+VALUES (1, $1)
+ON CONFLICT (id) DO UPDATE
+SET rolled_until = GREATEST(link_visit_rollup_state.rolled_until, EXCLUDED.rolled_until)
+`
+
+func (q *Queries) SetLinkVisitRolledUntil(ctx context.Context, rolledUntil time.Time) error {
+	_, err := q.db.ExecContext(ctx, setLinkVisitRolledUntil, rolledUntil)
+	return err
+}
+
+const upsertLinkVisitDaily = `-- name: UpsertLinkVisitDaily :exec
+INSERT INTO link_visit_daily (link_id, day, status, is_bot, referer_host, visits)
+VALUES ($1, $2, $3, $4, $5, $6)
+ON CONFLICT (link_id, day, status, is_bot, referer_host) DO UPDATE
+SET visits = EXCLUDED.visits
+`
+
+type UpsertLinkVisitDailyParams struct {
+	LinkID      int64     `json:"link_id"`
+	Day         time.Time `json:"day"`
+	Status      int32     `json:"status"`
+	IsBot       bool      `json:"is_bot"`
+	RefererHost string    `json:"referer_host"`
+	Visits      int64     `json:"visits"`
+}
+
+func (q *Queries) UpsertLinkVisitDaily(ctx context.Context, arg UpsertLinkVisitDailyParams) error {
+	_, err := q.db.ExecContext(ctx, upsertLinkVisitDaily,
+		arg.LinkID,
+		arg.Day,
+		arg.Status,
+		arg.IsBot,
+		arg.RefererHost,
+		arg.Visits,
+	)
+	return err
+}
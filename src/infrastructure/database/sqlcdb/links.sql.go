@@ -7,10 +7,13 @@ package sqlcdb
 
 import (
 	"context"
+	"database/sql"
+	"time"
 )
 
 const countLinks = `-- name: CountLinks :one
 SELECT COUNT(*) FROM links
+WHERE deleted_at IS NULL
 `
 
 func (q *Queries) CountLinks(ctx context.Context) (int64, error) {
@@ -21,44 +24,81 @@ func (q *Queries) CountLinks(ctx context.Context) (int64, error) {
 }
 
 const createLink = `-- name: CreateLink :one
-INSERT INTO links (original_url, short_name)
-VALUES ($1, $2)
-RETURNING id, original_url, short_name, created_at
+INSERT INTO links (original_url, short_name, expires_at, max_visits, password_hash, redirect_type, owner_id)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+RETURNING id, original_url, short_name, created_at, expires_at, max_visits, visit_count, password_hash, redirect_type, deleted_at, owner_id
 `
 
 type CreateLinkParams struct {
-	OriginalUrl string `json:"original_url"`
-	ShortName   string `json:"short_name"`
+	OriginalUrl  string        `json:"original_url"`
+	ShortName    string        `json:"short_name"`
+	ExpiresAt    sql.NullTime  `json:"expires_at"`
+	MaxVisits    sql.NullInt32 `json:"max_visits"`
+	PasswordHash string        `json:"password_hash"`
+	RedirectType int32         `json:"redirect_type"`
+	OwnerID      sql.NullInt64 `json:"owner_id"`
 }
 
 func (q *Queries) CreateLink(ctx context.Context, arg CreateLinkParams) (Link, error) {
-	row := q.db.QueryRowContext(ctx, createLink, arg.OriginalUrl, arg.ShortName)
+	row := q.db.QueryRowContext(ctx, createLink, arg.OriginalUrl, arg.ShortName, arg.ExpiresAt, arg.MaxVisits, arg.PasswordHash, arg.RedirectType, arg.OwnerID)
 	var i Link
 	err := row.Scan(
 		&i.ID,
 		&i.OriginalUrl,
 		&i.ShortName,
 		&i.CreatedAt,
+		&i.ExpiresAt,
+		&i.MaxVisits,
+		&i.VisitCount,
+		&i.PasswordHash,
+		&i.RedirectType,
+		&i.DeletedAt,
+		&i.OwnerID,
 	)
 	return i, err
 }
 
-const deleteLink = `-- name: DeleteLink :one
-DELETE FROM links
-WHERE id = $1
-RETURNING id
+const createLinkIfAbsent = `-- name: CreateLinkIfAbsent :one
+INSERT INTO links (original_url, short_name, expires_at, max_visits, password_hash, redirect_type, owner_id)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+ON CONFLICT (short_name) DO NOTHING
+RETURNING id, original_url, short_name, created_at, expires_at, max_visits, visit_count, password_hash, redirect_type, deleted_at, owner_id
 `
 
-func (q *Queries) DeleteLink(ctx context.Context, id int64) (int64, error) {
-	row := q.db.QueryRowContext(ctx, deleteLink, id)
-	err := row.Scan(&id)
-	return id, err
+type CreateLinkIfAbsentParams struct {
+	OriginalUrl  string        `json:"original_url"`
+	ShortName    string        `json:"short_name"`
+	ExpiresAt    sql.NullTime  `json:"expires_at"`
+	MaxVisits    sql.NullInt32 `json:"max_visits"`
+	PasswordHash string        `json:"password_hash"`
+	RedirectType int32         `json:"redirect_type"`
+	OwnerID      sql.NullInt64 `json:"owner_id"`
+}
+
+func (q *Queries) CreateLinkIfAbsent(ctx context.Context, arg CreateLinkIfAbsentParams) (Link, error) {
+	row := q.db.QueryRowContext(ctx, createLinkIfAbsent, arg.OriginalUrl, arg.ShortName, arg.ExpiresAt, arg.MaxVisits, arg.PasswordHash, arg.RedirectType, arg.OwnerID)
+	var i Link
+	err := row.Scan(
+		&i.ID,
+		&i.OriginalUrl,
+		&i.ShortName,
+		&i.CreatedAt,
+		&i.ExpiresAt,
+		&i.MaxVisits,
+		&i.VisitCount,
+		&i.PasswordHash,
+		&i.RedirectType,
+		&i.DeletedAt,
+		&i.OwnerID,
+	)
+	return i, err
 }
 
 const getLink = `-- name: GetLink :one
-SELECT id, original_url, short_name, created_at
+SELECT id, original_url, short_name, created_at, expires_at, max_visits, visit_count, password_hash, redirect_type, deleted_at, owner_id
 FROM links
 WHERE id = $1
+  AND deleted_at IS NULL
 `
 
 func (q *Queries) GetLink(ctx context.Context, id int64) (Link, error) {
@@ -69,14 +109,22 @@ func (q *Queries) GetLink(ctx context.Context, id int64) (Link, error) {
 		&i.OriginalUrl,
 		&i.ShortName,
 		&i.CreatedAt,
+		&i.ExpiresAt,
+		&i.MaxVisits,
+		&i.VisitCount,
+		&i.PasswordHash,
+		&i.RedirectType,
+		&i.DeletedAt,
+		&i.OwnerID,
 	)
 	return i, err
 }
 
 const getLinkByShortName = `-- name: GetLinkByShortName :one
-SELECT id, original_url, short_name, created_at
+SELECT id, original_url, short_name, created_at, expires_at, max_visits, visit_count, password_hash, redirect_type, deleted_at, owner_id
 FROM links
 WHERE short_name = $1
+  AND deleted_at IS NULL
 `
 
 func (q *Queries) GetLinkByShortName(ctx context.Context, shortName string) (Link, error) {
@@ -87,18 +135,60 @@ func (q *Queries) GetLinkByShortName(ctx context.Context, shortName string) (Lin
 		&i.OriginalUrl,
 		&i.ShortName,
 		&i.CreatedAt,
+		&i.ExpiresAt,
+		&i.MaxVisits,
+		&i.VisitCount,
+		&i.PasswordHash,
+		&i.RedirectType,
+		&i.DeletedAt,
+		&i.OwnerID,
+	)
+	return i, err
+}
+
+const getLinkForUpdate = `-- name: GetLinkForUpdate :one
+SELECT id, original_url, short_name, created_at, expires_at, max_visits, visit_count, password_hash, redirect_type, deleted_at, owner_id
+FROM links
+WHERE id = $1
+  AND deleted_at IS NULL
+FOR UPDATE
+`
+
+func (q *Queries) GetLinkForUpdate(ctx context.Context, id int64) (Link, error) {
+	row := q.db.QueryRowContext(ctx, getLinkForUpdate, id)
+	var i Link
+	err := row.Scan(
+		&i.ID,
+		&i.OriginalUrl,
+		&i.ShortName,
+		&i.CreatedAt,
+		&i.ExpiresAt,
+		&i.MaxVisits,
+		&i.VisitCount,
+		&i.PasswordHash,
+		&i.RedirectType,
+		&i.DeletedAt,
+		&i.OwnerID,
 	)
 	return i, err
 }
 
-const listLinks = `-- name: ListLinks :many
-SELECT id, original_url, short_name, created_at
+const listLinksAfter = `-- name: ListLinksAfter :many
+SELECT id, original_url, short_name, created_at, expires_at, max_visits, visit_count, password_hash, redirect_type, deleted_at, owner_id
 FROM links
+WHERE id > $1
+  AND deleted_at IS NULL
 ORDER BY id
+LIMIT $2
 `
 
-func (q *Queries) ListLinks(ctx context.Context) ([]Link, error) {
-	rows, err := q.db.QueryContext(ctx, listLinks)
+type ListLinksAfterParams struct {
+	ID    int64 `json:"id"`
+	Limit int32 `json:"limit"`
+}
+
+func (q *Queries) ListLinksAfter(ctx context.Context, arg ListLinksAfterParams) ([]Link, error) {
+	rows, err := q.db.QueryContext(ctx, listLinksAfter, arg.ID, arg.Limit)
 	if err != nil {
 		return nil, err
 	}
@@ -111,6 +201,13 @@ func (q *Queries) ListLinks(ctx context.Context) ([]Link, error) {
 			&i.OriginalUrl,
 			&i.ShortName,
 			&i.CreatedAt,
+			&i.ExpiresAt,
+			&i.MaxVisits,
+			&i.VisitCount,
+			&i.PasswordHash,
+			&i.RedirectType,
+			&i.DeletedAt,
+			&i.OwnerID,
 		); err != nil {
 			return nil, err
 		}
@@ -126,8 +223,9 @@ func (q *Queries) ListLinks(ctx context.Context) ([]Link, error) {
 }
 
 const listLinksWithRange = `-- name: ListLinksWithRange :many
-SELECT id, original_url, short_name, created_at
+SELECT id, original_url, short_name, created_at, expires_at, max_visits, visit_count, password_hash, redirect_type, deleted_at, owner_id
 FROM links
+WHERE deleted_at IS NULL
 ORDER BY id
 LIMIT $1 OFFSET $2
 `
@@ -151,6 +249,13 @@ func (q *Queries) ListLinksWithRange(ctx context.Context, arg ListLinksWithRange
 			&i.OriginalUrl,
 			&i.ShortName,
 			&i.CreatedAt,
+			&i.ExpiresAt,
+			&i.MaxVisits,
+			&i.VisitCount,
+			&i.PasswordHash,
+			&i.RedirectType,
+			&i.DeletedAt,
+			&i.OwnerID,
 		); err != nil {
 			return nil, err
 		}
@@ -165,28 +270,125 @@ func (q *Queries) ListLinksWithRange(ctx context.Context, arg ListLinksWithRange
 	return items, nil
 }
 
+const purgeTrashedLinks = `-- name: PurgeTrashedLinks :execrows
+WITH purged AS (
+  DELETE FROM links
+  WHERE deleted_at < $1::timestamptz
+  RETURNING id, original_url, short_name
+)
+INSERT INTO link_events (link_id, type, old_original_url, old_short_name, actor, request_id)
+SELECT id, 'purged', original_url, short_name, $2, $3
+FROM purged
+`
+
+type PurgeTrashedLinksParams struct {
+	Before    time.Time `json:"before"`
+	Actor     string    `json:"actor"`
+	RequestID string    `json:"request_id"`
+}
+
+func (q *Queries) PurgeTrashedLinks(ctx context.Context, arg PurgeTrashedLinksParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, purgeTrashedLinks, arg.Before, arg.Actor, arg.RequestID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const restoreLink = `-- name: RestoreLink :one
+UPDATE links
+SET deleted_at = NULL
+WHERE id = $1
+  AND deleted_at IS NOT NULL
+RETURNING id, original_url, short_name, created_at, expires_at, max_visits, visit_count, password_hash, redirect_type, deleted_at, owner_id
+`
+
+func (q *Queries) RestoreLink(ctx context.Context, id int64) (Link, error) {
+	row := q.db.QueryRowContext(ctx, restoreLink, id)
+	var i Link
+	err := row.Scan(
+		&i.ID,
+		&i.OriginalUrl,
+		&i.ShortName,
+		&i.CreatedAt,
+		&i.ExpiresAt,
+		&i.MaxVisits,
+		&i.VisitCount,
+		&i.PasswordHash,
+		&i.RedirectType,
+		&i.DeletedAt,
+		&i.OwnerID,
+	)
+	return i, err
+}
+
+const trashLink = `-- name: TrashLink :one
+UPDATE links
+SET deleted_at = now()
+WHERE id = $1
+  AND deleted_at IS NULL
+RETURNING id, original_url, short_name, created_at, expires_at, max_visits, visit_count, password_hash, redirect_type, deleted_at, owner_id
+`
+
+func (q *Queries) TrashLink(ctx context.Context, id int64) (Link, error) {
+	row := q.db.QueryRowContext(ctx, trashLink, id)
+	var i Link
+	err := row.Scan(
+		&i.ID,
+		&i.OriginalUrl,
+		&i.ShortName,
+		&i.CreatedAt,
+		&i.ExpiresAt,
+		&i.MaxVisits,
+		&i.VisitCount,
+		&i.PasswordHash,
+		&i.RedirectType,
+		&i.DeletedAt,
+		&i.OwnerID,
+	)
+	return i, err
+}
+
 const updateLink = `-- name: UpdateLink :one
 UPDATE links
 SET original_url = $2,
-    short_name   = $3
+    short_name   = $3,
+    expires_at   = $4,
+    max_visits   = $5,
+    password_hash = $6,
+    redirect_type = $7,
+    owner_id = COALESCE($8, owner_id)
 WHERE id = $1
-RETURNING id, original_url, short_name, created_at
+  AND deleted_at IS NULL
+RETURNING id, original_url, short_name, created_at, expires_at, max_visits, visit_count, password_hash, redirect_type, deleted_at, owner_id
 `
 
 type UpdateLinkParams struct {
-	ID          int64  `json:"id"`
-	OriginalUrl string `json:"original_url"`
-	ShortName   string `json:"short_name"`
+	ID           int64         `json:"id"`
+	OriginalUrl  string        `json:"original_url"`
+	ShortName    string        `json:"short_name"`
+	ExpiresAt    sql.NullTime  `json:"expires_at"`
+	MaxVisits    sql.NullInt32 `json:"max_visits"`
+	PasswordHash string        `json:"password_hash"`
+	RedirectType int32         `json:"redirect_type"`
+	OwnerID      sql.NullInt64 `json:"owner_id"`
 }
 
 func (q *Queries) UpdateLink(ctx context.Context, arg UpdateLinkParams) (Link, error) {
-	row := q.db.QueryRowContext(ctx, updateLink, arg.ID, arg.OriginalUrl, arg.ShortName)
+	row := q.db.QueryRowContext(ctx, updateLink, arg.ID, arg.OriginalUrl, arg.ShortName, arg.ExpiresAt, arg.MaxVisits, arg.PasswordHash, arg.RedirectType, arg.OwnerID)
 	var i Link
 	err := row.Scan(
 		&i.ID,
 		&i.OriginalUrl,
 		&i.ShortName,
 		&i.CreatedAt,
+		&i.ExpiresAt,
+		&i.MaxVisits,
+		&i.VisitCount,
+		&i.PasswordHash,
+		&i.RedirectType,
+		&i.DeletedAt,
+		&i.OwnerID,
 	)
 	return i, err
 }
@@ -5,22 +5,93 @@
 package sqlcdb
 
 import (
+	"database/sql"
 	"time"
 )
 
+type ApiKey struct {
+	ID         int64         `json:"id"`
+	Name       string        `json:"name"`
+	Prefix     string        `json:"prefix"`
+	KeyHash    string        `json:"key_hash"`
+	Scopes     string        `json:"scopes"`
+	CreatedAt  time.Time     `json:"created_at"`
+	LastUsedAt sql.NullTime  `json:"last_used_at"`
+	RevokedAt  sql.NullTime  `json:"revoked_at"`
+	OwnerID    sql.NullInt64 `json:"owner_id"`
+}
+
 type Link struct {
-	ID          int64     `json:"id"`
-	OriginalUrl string    `json:"original_url"`
-	ShortName   string    `json:"short_name"`
-	CreatedAt   time.Time `json:"created_at"`
+	ID           int64         `json:"id"`
+	OriginalUrl  string        `json:"original_url"`
+	ShortName    string        `json:"short_name"`
+	CreatedAt    time.Time     `json:"created_at"`
+	ExpiresAt    sql.NullTime  `json:"expires_at"`
+	MaxVisits    sql.NullInt32 `json:"max_visits"`
+	VisitCount   int64         `json:"visit_count"`
+	PasswordHash string        `json:"password_hash"`
+	RedirectType int32         `json:"redirect_type"`
+	DeletedAt    sql.NullTime  `json:"deleted_at"`
+	OwnerID      sql.NullInt64 `json:"owner_id"`
+}
+
+type LinkEvent struct {
+	ID             int64     `json:"id"`
+	LinkID         int64     `json:"link_id"`
+	Type           string    `json:"type"`
+	OldOriginalUrl string    `json:"old_original_url"`
+	NewOriginalUrl string    `json:"new_original_url"`
+	OldShortName   string    `json:"old_short_name"`
+	NewShortName   string    `json:"new_short_name"`
+	Actor          string    `json:"actor"`
+	RequestID      string    `json:"request_id"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+type LinkVisitDaily struct {
+	LinkID      int64     `json:"link_id"`
+	Day         time.Time `json:"day"`
+	Status      int32     `json:"status"`
+	IsBot       bool      `json:"is_bot"`
+	RefererHost string    `json:"referer_host"`
+	Visits      int64     `json:"visits"`
+}
+
+type LinkVisitRollupState struct {
+	ID          int32     `json:"id"`
+	RolledUntil time.Time `json:"rolled_until"`
 }
 
 type LinkVisit struct {
+	ID             int64     `json:"id"`
+	LinkID         int64     `json:"link_id"`
+	Ip             string    `json:"ip"`
+	UserAgent      string    `json:"user_agent"`
+	Referer        string    `json:"referer"`
+	Status         int32     `json:"status"`
+	CreatedAt      time.Time `json:"created_at"`
+	Browser        string    `json:"browser"`
+	BrowserVersion string    `json:"browser_version"`
+	Os             string    `json:"os"`
+	Device         string    `json:"device"`
+	IsBot          bool      `json:"is_bot"`
+	Country        string    `json:"country"`
+	Region         string    `json:"region"`
+	City           string    `json:"city"`
+}
+
+type User struct {
+	ID           int64     `json:"id"`
+	Username     string    `json:"username"`
+	PasswordHash string    `json:"password_hash"`
+	Role         string    `json:"role"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+type UserSession struct {
 	ID        int64     `json:"id"`
-	LinkID    int64     `json:"link_id"`
-	Ip        string    `json:"ip"`
-	UserAgent string    `json:"user_agent"`
-	Referer   string    `json:"referer"`
-	Status    int32     `json:"status"`
+	UserID    int64     `json:"user_id"`
+	TokenHash string    `json:"token_hash"`
 	CreatedAt time.Time `json:"created_at"`
+	ExpiresAt time.Time `json:"expires_at"`
 }
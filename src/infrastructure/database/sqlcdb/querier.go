@@ -6,21 +6,63 @@ package sqlcdb
 
 import (
 	"context"
+	"time"
 )
 
 type Querier interface {
+	CreateApiKey(ctx context.Context, arg CreateApiKeyParams) (ApiKey, error)
+	GetApiKeyByHash(ctx context.Context, keyHash string) (ApiKey, error)
+	ListApiKeys(ctx context.Context) ([]ApiKey, error)
+	RevokeApiKey(ctx context.Context, id int64) (int64, error)
+	TouchApiKey(ctx context.Context, arg TouchApiKeyParams) error
+
 	CountLinks(ctx context.Context) (int64, error)
 	CreateLink(ctx context.Context, arg CreateLinkParams) (Link, error)
-	DeleteLink(ctx context.Context, id int64) (int64, error)
+	CreateLinkIfAbsent(ctx context.Context, arg CreateLinkIfAbsentParams) (Link, error)
 	GetLink(ctx context.Context, id int64) (Link, error)
 	GetLinkByShortName(ctx context.Context, shortName string) (Link, error)
-	ListLinks(ctx context.Context) ([]Link, error)
+	GetLinkForUpdate(ctx context.Context, id int64) (Link, error)
+	ListLinksAfter(ctx context.Context, arg ListLinksAfterParams) ([]Link, error)
 	ListLinksWithRange(ctx context.Context, arg ListLinksWithRangeParams) ([]Link, error)
+	PurgeTrashedLinks(ctx context.Context, arg PurgeTrashedLinksParams) (int64, error)
+	RestoreLink(ctx context.Context, id int64) (Link, error)
+	TrashLink(ctx context.Context, id int64) (Link, error)
 	UpdateLink(ctx context.Context, arg UpdateLinkParams) (Link, error)
 
-	CountLinkVisits(ctx context.Context) (int64, error)
+	CreateLinkEvent(ctx context.Context, arg CreateLinkEventParams) error
+	ListLinkEvents(ctx context.Context, linkID int64) ([]LinkEvent, error)
+
+	ConsumeLinkVisit(ctx context.Context, id int64) (int64, error)
+	CountLinkVisits(ctx context.Context, arg CountLinkVisitsParams) (int64, error)
+	CountLinkVisitsBefore(ctx context.Context, before time.Time) (int64, error)
+	CountLinkVisitGroups(ctx context.Context, arg CountLinkVisitGroupsParams) ([]CountLinkVisitGroupsRow, error)
+	CountLinkVisitsInRange(ctx context.Context, arg CountLinkVisitsInRangeParams) (CountLinkVisitsInRangeRow, error)
 	CreateLinkVisit(ctx context.Context, arg CreateLinkVisitParams) (LinkVisit, error)
+	DeleteLinkVisitsBefore(ctx context.Context, arg DeleteLinkVisitsBeforeParams) (int64, error)
+	FirstLinkVisitCreatedAt(ctx context.Context) (time.Time, error)
+	GetLinkVisitRolledUntil(ctx context.Context) (time.Time, error)
+	LinkVisitCountries(ctx context.Context, arg LinkVisitCountriesParams) ([]LinkVisitCountriesRow, error)
+	LinkVisitDevices(ctx context.Context, arg LinkVisitDevicesParams) ([]LinkVisitDevicesRow, error)
+	LinkVisitSeries(ctx context.Context, arg LinkVisitSeriesParams) ([]LinkVisitSeriesRow, error)
+	LinkVisitStatuses(ctx context.Context, arg LinkVisitStatusesParams) ([]LinkVisitStatusesRow, error)
+	ListLinkVisitDaily(ctx context.Context, arg ListLinkVisitDailyParams) ([]LinkVisitDaily, error)
+	ListLinkVisitsAfter(ctx context.Context, arg ListLinkVisitsAfterParams) ([]LinkVisit, error)
 	ListLinkVisitsWithRange(ctx context.Context, arg ListLinkVisitsWithRangeParams) ([]LinkVisit, error)
+	TopLinkVisitBrowsers(ctx context.Context, arg TopLinkVisitBrowsersParams) ([]TopLinkVisitBrowsersRow, error)
+	TopLinkVisitOperatingSystems(ctx context.Context, arg TopLinkVisitOperatingSystemsParams) ([]TopLinkVisitOperatingSystemsRow, error)
+	TopLinkVisitReferers(ctx context.Context, arg TopLinkVisitReferersParams) ([]TopLinkVisitReferersRow, error)
+	SetLinkVisitRolledUntil(ctx context.Context, rolledUntil time.Time) error
+	TopLinkVisitUserAgents(ctx context.Context, arg TopLinkVisitUserAgentsParams) ([]TopLinkVisitUserAgentsRow, error)
+	UpsertLinkVisitDaily(ctx context.Context, arg UpsertLinkVisitDailyParams) error
+
+	CreateUser(ctx context.Context, arg CreateUserParams) (User, error)
+	CreateUserSession(ctx context.Context, arg CreateUserSessionParams) error
+	DeleteExpiredUserSessions(ctx context.Context) (int64, error)
+	DeleteUserSession(ctx context.Context, tokenHash string) error
+	GetUser(ctx context.Context, id int64) (User, error)
+	GetUserBySession(ctx context.Context, tokenHash string) (User, error)
+	GetUserByUsername(ctx context.Context, username string) (User, error)
+	ListUsers(ctx context.Context) ([]User, error)
 }
 
 var _ Querier = (*Queries)(nil)
@@ -0,0 +1,84 @@
+package sqlite
+
+import (
+	"database/sql"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	_ "modernc.org/sqlite"
+)
+
+/*Префикс схемы DATABASE_URL для SQLite*/
+const scheme = "sqlite://"
+
+/*Параметры соединения по умолчанию: внешние ключи (для ON DELETE CASCADE), ожидание блокировки и WAL*/
+var defaultPragmas = []string{
+	"foreign_keys(1)",
+	"busy_timeout(5000)",
+	"journal_mode(WAL)",
+}
+
+/*Открытие соединения с базой данных*/
+func Open(databaseURL string) (*sql.DB, error) {
+	dsn, err := DSN(databaseURL)
+	if err != nil {
+		return nil, err
+	}
+
+	db, err := sql.Open("sqlite", dsn)
+	if err != nil {
+		return nil, err
+	}
+
+	if strings.HasPrefix(dsn, "file::memory:") {
+		// у каждого соединения своя база в памяти, поэтому держим ровно одно и не пересоздаём его
+		db.SetMaxOpenConns(1)
+	} else {
+		db.SetConnMaxLifetime(30 * time.Minute)
+		db.SetMaxOpenConns(10)
+		db.SetMaxIdleConns(10)
+	}
+
+	if err := db.Ping(); err != nil {
+		_ = db.Close()
+		return nil, err
+	}
+
+	return db, nil
+}
+
+/*Преобразование DATABASE_URL вида sqlite://path/to/file.db?... в DSN драйвера*/
+func DSN(databaseURL string) (string, error) {
+	rest, ok := strings.CutPrefix(databaseURL, scheme)
+	if !ok {
+		return "", fmt.Errorf("invalid sqlite DATABASE_URL %q", databaseURL)
+	}
+
+	path, rawQuery, _ := strings.Cut(rest, "?")
+	if path == "" {
+		return "", fmt.Errorf("sqlite DATABASE_URL has no file path")
+	}
+
+	query, err := url.ParseQuery(rawQuery)
+	if err != nil {
+		return "", fmt.Errorf("invalid sqlite DATABASE_URL: %w", err)
+	}
+
+	if len(query["_pragma"]) == 0 {
+		query["_pragma"] = defaultPragmas
+	}
+
+	// запись начинается сразу с BEGIN, чтобы конкурирующие транзакции ждали busy_timeout, а не падали
+	if query.Get("_txlock") == "" {
+		query.Set("_txlock", "immediate")
+	}
+
+	// время пишется в формате, который понимают функции даты SQLite (strftime в запросах статистики)
+	if query.Get("_time_format") == "" {
+		query.Set("_time_format", "sqlite")
+	}
+
+	return "file:" + path + "?" + query.Encode(), nil
+}
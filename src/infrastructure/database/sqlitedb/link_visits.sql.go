@@ -0,0 +1,882 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: link_visits.sql
+
+package sqlitedb
+
+import (
+	"context"
+	"database/sql"
+	"time"
+)
+
+const consumeLinkVisit = `-- name: ConsumeLinkVisit :one
+UPDATE links
+SET visit_count = visit_count + 1
+WHERE id = ?
+  AND (max_visits IS NULL OR visit_count < max_visits)
+RETURNING visit_count
+`
+
+func (q *Queries) ConsumeLinkVisit(ctx context.Context, id int64) (int64, error) {
+	row := q.db.QueryRowContext(ctx, consumeLinkVisit, id)
+	var visit_count int64
+	err := row.Scan(&visit_count)
+	return visit_count, err
+}
+
+const countLinkVisits = `-- name: CountLinkVisits :one
+SELECT COUNT(*) FROM link_visits
+WHERE (? IS NULL OR link_id = ?)
+  AND (? IS NULL OR created_at >= strftime('%Y-%m-%d %H:%M:%f', ?))
+  AND (? IS NULL OR created_at < strftime('%Y-%m-%d %H:%M:%f', ?))
+  AND (? IS NULL OR status = ?)
+  AND (? IS NULL OR ip LIKE '%' || ? || '%' ESCAPE '\')
+  AND (? IS NULL OR referer LIKE '%' || ? || '%' ESCAPE '\')
+  AND (? IS NULL OR browser = ?)
+  AND (? IS NULL OR os = ?)
+  AND (? IS NULL OR device = ?)
+  AND (? IS NULL OR is_bot = ?)
+  AND (? IS NULL OR link_id IN (SELECT id FROM links WHERE owner_id = ?))
+`
+
+type CountLinkVisitsParams struct {
+	LinkID   sql.NullInt64  `json:"link_id"`
+	FromTime interface{}    `json:"from_time"`
+	ToTime   interface{}    `json:"to_time"`
+	Status   sql.NullInt64  `json:"status"`
+	Ip       sql.NullString `json:"ip"`
+	Referer  sql.NullString `json:"referer"`
+	Browser  sql.NullString `json:"browser"`
+	Os       sql.NullString `json:"os"`
+	Device   sql.NullString `json:"device"`
+	IsBot    sql.NullBool   `json:"is_bot"`
+	OwnerID  sql.NullInt64  `json:"owner_id"`
+}
+
+func (q *Queries) CountLinkVisits(ctx context.Context, arg CountLinkVisitsParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countLinkVisits,
+		arg.LinkID,
+		arg.LinkID,
+		arg.FromTime,
+		arg.FromTime,
+		arg.ToTime,
+		arg.ToTime,
+		arg.Status,
+		arg.Status,
+		arg.Ip,
+		arg.Ip,
+		arg.Referer,
+		arg.Referer,
+		arg.Browser,
+		arg.Browser,
+		arg.Os,
+		arg.Os,
+		arg.Device,
+		arg.Device,
+		arg.IsBot,
+		arg.IsBot,
+		arg.OwnerID,
+		arg.OwnerID,
+	)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const createLinkVisit = `-- name: CreateLinkVisit :one
+INSERT INTO link_visits (link_id, ip, user_agent, referer, status, browser, browser_version, os, device, is_bot, country, region, city)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+RETURNING id, link_id, ip, user_agent, referer, status, created_at, browser, browser_version, os, device, is_bot, country, region, city
+`
+
+type CreateLinkVisitParams struct {
+	LinkID         int64  `json:"link_id"`
+	Ip             string `json:"ip"`
+	UserAgent      string `json:"user_agent"`
+	Referer        string `json:"referer"`
+	Status         int64  `json:"status"`
+	Browser        string `json:"browser"`
+	BrowserVersion string `json:"browser_version"`
+	Os             string `json:"os"`
+	Device         string `json:"device"`
+	IsBot          bool   `json:"is_bot"`
+	Country        string `json:"country"`
+	Region         string `json:"region"`
+	City           string `json:"city"`
+}
+
+func (q *Queries) CreateLinkVisit(ctx context.Context, arg CreateLinkVisitParams) (LinkVisit, error) {
+	row := q.db.QueryRowContext(ctx, createLinkVisit,
+		arg.LinkID,
+		arg.Ip,
+		arg.UserAgent,
+		arg.Referer,
+		arg.Status,
+		arg.Browser,
+		arg.BrowserVersion,
+		arg.Os,
+		arg.Device,
+		arg.IsBot,
+		arg.Country,
+		arg.Region,
+		arg.City,
+	)
+	var i LinkVisit
+	err := row.Scan(
+		&i.ID,
+		&i.LinkID,
+		&i.Ip,
+		&i.UserAgent,
+		&i.Referer,
+		&i.Status,
+		&i.CreatedAt,
+		&i.Browser,
+		&i.BrowserVersion,
+		&i.Os,
+		&i.Device,
+		&i.IsBot,
+		&i.Country,
+		&i.Region,
+		&i.City,
+	)
+	return i, err
+}
+
+const listLinkVisitsAfter = `-- name: ListLinkVisitsAfter :many
+SELECT id, link_id, ip, user_agent, referer, status, created_at, browser, browser_version, os, device, is_bot, country, region, city
+FROM link_visits
+WHERE (? IS NULL OR link_id = ?)
+  AND (? IS NULL OR created_at >= strftime('%Y-%m-%d %H:%M:%f', ?))
+  AND (? IS NULL OR created_at < strftime('%Y-%m-%d %H:%M:%f', ?))
+  AND (? IS NULL OR status = ?)
+  AND (? IS NULL OR ip LIKE '%' || ? || '%' ESCAPE '\')
+  AND (? IS NULL OR referer LIKE '%' || ? || '%' ESCAPE '\')
+  AND (? IS NULL OR browser = ?)
+  AND (? IS NULL OR os = ?)
+  AND (? IS NULL OR device = ?)
+  AND (? IS NULL OR is_bot = ?)
+  AND (? IS NULL OR link_id IN (SELECT id FROM links WHERE owner_id = ?))
+  AND id > ?
+ORDER BY id
+LIMIT ?
+`
+
+type ListLinkVisitsAfterParams struct {
+	LinkID   sql.NullInt64  `json:"link_id"`
+	FromTime interface{}    `json:"from_time"`
+	ToTime   interface{}    `json:"to_time"`
+	Status   sql.NullInt64  `json:"status"`
+	Ip       sql.NullString `json:"ip"`
+	Referer  sql.NullString `json:"referer"`
+	Browser  sql.NullString `json:"browser"`
+	Os       sql.NullString `json:"os"`
+	Device   sql.NullString `json:"device"`
+	IsBot    sql.NullBool   `json:"is_bot"`
+	OwnerID  sql.NullInt64  `json:"owner_id"`
+	AfterID  int64          `json:"after_id"`
+	RowLimit int64          `json:"row_limit"`
+}
+
+func (q *Queries) ListLinkVisitsAfter(ctx context.Context, arg ListLinkVisitsAfterParams) ([]LinkVisit, error) {
+	rows, err := q.db.QueryContext(ctx, listLinkVisitsAfter,
+		arg.LinkID,
+		arg.LinkID,
+		arg.FromTime,
+		arg.FromTime,
+		arg.ToTime,
+		arg.ToTime,
+		arg.Status,
+		arg.Status,
+		arg.Ip,
+		arg.Ip,
+		arg.Referer,
+		arg.Referer,
+		arg.Browser,
+		arg.Browser,
+		arg.Os,
+		arg.Os,
+		arg.Device,
+		arg.Device,
+		arg.IsBot,
+		arg.IsBot,
+		arg.OwnerID,
+		arg.OwnerID,
+		arg.AfterID,
+		arg.RowLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []LinkVisit
+	for rows.Next() {
+		var i LinkVisit
+		if err := rows.Scan(
+			&i.ID,
+			&i.LinkID,
+			&i.Ip,
+			&i.UserAgent,
+			&i.Referer,
+			&i.Status,
+			&i.CreatedAt,
+			&i.Browser,
+			&i.BrowserVersion,
+			&i.Os,
+			&i.Device,
+			&i.IsBot,
+			&i.Country,
+			&i.Region,
+			&i.City,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listLinkVisitsWithRange = `-- name: ListLinkVisitsWithRange :many
+SELECT id, link_id, ip, user_agent, referer, status, created_at, browser, browser_version, os, device, is_bot, country, region, city
+FROM link_visits
+WHERE (? IS NULL OR link_id = ?)
+  AND (? IS NULL OR created_at >= strftime('%Y-%m-%d %H:%M:%f', ?))
+  AND (? IS NULL OR created_at < strftime('%Y-%m-%d %H:%M:%f', ?))
+  AND (? IS NULL OR status = ?)
+  AND (? IS NULL OR ip LIKE '%' || ? || '%' ESCAPE '\')
+  AND (? IS NULL OR referer LIKE '%' || ? || '%' ESCAPE '\')
+  AND (? IS NULL OR browser = ?)
+  AND (? IS NULL OR os = ?)
+  AND (? IS NULL OR device = ?)
+  AND (? IS NULL OR is_bot = ?)
+  AND (? IS NULL OR link_id IN (SELECT id FROM links WHERE owner_id = ?))
+ORDER BY id
+LIMIT ? OFFSET ?
+`
+
+type ListLinkVisitsWithRangeParams struct {
+	LinkID    sql.NullInt64  `json:"link_id"`
+	FromTime  interface{}    `json:"from_time"`
+	ToTime    interface{}    `json:"to_time"`
+	Status    sql.NullInt64  `json:"status"`
+	Ip        sql.NullString `json:"ip"`
+	Referer   sql.NullString `json:"referer"`
+	Browser   sql.NullString `json:"browser"`
+	Os        sql.NullString `json:"os"`
+	Device    sql.NullString `json:"device"`
+	IsBot     sql.NullBool   `json:"is_bot"`
+	OwnerID   sql.NullInt64  `json:"owner_id"`
+	RowLimit  int64          `json:"row_limit"`
+	RowOffset int64          `json:"row_offset"`
+}
+
+func (q *Queries) ListLinkVisitsWithRange(ctx context.Context, arg ListLinkVisitsWithRangeParams) ([]LinkVisit, error) {
+	rows, err := q.db.QueryContext(ctx, listLinkVisitsWithRange,
+		arg.LinkID,
+		arg.LinkID,
+		arg.FromTime,
+		arg.FromTime,
+		arg.ToTime,
+		arg.ToTime,
+		arg.Status,
+		arg.Status,
+		arg.Ip,
+		arg.Ip,
+		arg.Referer,
+		arg.Referer,
+		arg.Browser,
+		arg.Browser,
+		arg.Os,
+		arg.Os,
+		arg.Device,
+		arg.Device,
+		arg.IsBot,
+		arg.IsBot,
+		arg.OwnerID,
+		arg.OwnerID,
+		arg.RowLimit,
+		arg.RowOffset,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []LinkVisit
+	for rows.Next() {
+		var i LinkVisit
+		if err := rows.Scan(
+			&i.ID,
+			&i.LinkID,
+			&i.Ip,
+			&i.UserAgent,
+			&i.Referer,
+			&i.Status,
+			&i.CreatedAt,
+			&i.Browser,
+			&i.BrowserVersion,
+			&i.Os,
+			&i.Device,
+			&i.IsBot,
+			&i.Country,
+			&i.Region,
+			&i.City,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const countLinkVisitsInRange = `-- name: CountLinkVisitsInRange :one
+SELECT
+  COUNT(*) FILTER (WHERE NOT is_bot) AS humans,
+  COUNT(*) FILTER (WHERE is_bot) AS bots
+FROM link_visits
+WHERE link_id = ?
+  AND created_at >= strftime('%Y-%m-%d %H:%M:%f', ?)
+  AND created_at < strftime('%Y-%m-%d %H:%M:%f', ?)
+`
+
+type CountLinkVisitsInRangeParams struct {
+	LinkID   int64       `json:"link_id"`
+	FromTime interface{} `json:"from_time"`
+	ToTime   interface{} `json:"to_time"`
+}
+
+type CountLinkVisitsInRangeRow struct {
+	Humans int64 `json:"humans"`
+	Bots   int64 `json:"bots"`
+}
+
+func (q *Queries) CountLinkVisitsInRange(ctx context.Context, arg CountLinkVisitsInRangeParams) (CountLinkVisitsInRangeRow, error) {
+	row := q.db.QueryRowContext(ctx, countLinkVisitsInRange, arg.LinkID, arg.FromTime, arg.ToTime)
+	var i CountLinkVisitsInRangeRow
+	err := row.Scan(&i.Humans, &i.Bots)
+	return i, err
+}
+
+const linkVisitSeries = `-- name: LinkVisitSeries :many
+SELECT
+  CAST(CASE ?
+    WHEN 'hour' THEN strftime('%Y-%m-%d %H:00:00', created_at)
+    WHEN 'week' THEN date(created_at, 'weekday 0', '-6 days') || ' 00:00:00'
+    ELSE strftime('%Y-%m-%d 00:00:00', created_at)
+  END AS TEXT) AS bucket,
+  COUNT(*) AS clicks
+FROM link_visits
+WHERE link_id = ?
+  AND created_at >= strftime('%Y-%m-%d %H:%M:%f', ?)
+  AND created_at < strftime('%Y-%m-%d %H:%M:%f', ?)
+  AND (? OR NOT is_bot)
+GROUP BY bucket
+ORDER BY bucket
+`
+
+type LinkVisitSeriesParams struct {
+	Interval    interface{} `json:"interval"`
+	LinkID      int64       `json:"link_id"`
+	FromTime    interface{} `json:"from_time"`
+	ToTime      interface{} `json:"to_time"`
+	IncludeBots bool        `json:"include_bots"`
+}
+
+type LinkVisitSeriesRow struct {
+	Bucket string `json:"bucket"`
+	Clicks int64  `json:"clicks"`
+}
+
+func (q *Queries) LinkVisitSeries(ctx context.Context, arg LinkVisitSeriesParams) ([]LinkVisitSeriesRow, error) {
+	rows, err := q.db.QueryContext(ctx, linkVisitSeries, arg.Interval, arg.LinkID, arg.FromTime, arg.ToTime, arg.IncludeBots)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []LinkVisitSeriesRow
+	for rows.Next() {
+		var i LinkVisitSeriesRow
+		if err := rows.Scan(
+			&i.Bucket,
+			&i.Clicks,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const topLinkVisitReferers = `-- name: TopLinkVisitReferers :many
+SELECT referer, COUNT(*) AS count
+FROM link_visits
+WHERE link_id = ?
+  AND created_at >= strftime('%Y-%m-%d %H:%M:%f', ?)
+  AND created_at < strftime('%Y-%m-%d %H:%M:%f', ?)
+  AND (? OR NOT is_bot)
+GROUP BY referer
+ORDER BY count DESC, referer
+LIMIT ?
+`
+
+type TopLinkVisitReferersParams struct {
+	LinkID      int64       `json:"link_id"`
+	FromTime    interface{} `json:"from_time"`
+	ToTime      interface{} `json:"to_time"`
+	IncludeBots bool        `json:"include_bots"`
+	TopLimit    int64       `json:"top_limit"`
+}
+
+type TopLinkVisitReferersRow struct {
+	Referer string `json:"referer"`
+	Count   int64  `json:"count"`
+}
+
+func (q *Queries) TopLinkVisitReferers(ctx context.Context, arg TopLinkVisitReferersParams) ([]TopLinkVisitReferersRow, error) {
+	rows, err := q.db.QueryContext(ctx, topLinkVisitReferers, arg.LinkID, arg.FromTime, arg.ToTime, arg.IncludeBots, arg.TopLimit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []TopLinkVisitReferersRow
+	for rows.Next() {
+		var i TopLinkVisitReferersRow
+		if err := rows.Scan(
+			&i.Referer,
+			&i.Count,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const topLinkVisitUserAgents = `-- name: TopLinkVisitUserAgents :many
+SELECT user_agent, COUNT(*) AS count
+FROM link_visits
+WHERE link_id = ?
+  AND created_at >= strftime('%Y-%m-%d %H:%M:%f', ?)
+  AND created_at < strftime('%Y-%m-%d %H:%M:%f', ?)
+  AND (? OR NOT is_bot)
+GROUP BY user_agent
+ORDER BY count DESC, user_agent
+LIMIT ?
+`
+
+type TopLinkVisitUserAgentsParams struct {
+	LinkID      int64       `json:"link_id"`
+	FromTime    interface{} `json:"from_time"`
+	ToTime      interface{} `json:"to_time"`
+	IncludeBots bool        `json:"include_bots"`
+	TopLimit    int64       `json:"top_limit"`
+}
+
+type TopLinkVisitUserAgentsRow struct {
+	UserAgent string `json:"user_agent"`
+	Count     int64  `json:"count"`
+}
+
+func (q *Queries) TopLinkVisitUserAgents(ctx context.Context, arg TopLinkVisitUserAgentsParams) ([]TopLinkVisitUserAgentsRow, error) {
+	rows, err := q.db.QueryContext(ctx, topLinkVisitUserAgents, arg.LinkID, arg.FromTime, arg.ToTime, arg.IncludeBots, arg.TopLimit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []TopLinkVisitUserAgentsRow
+	for rows.Next() {
+		var i TopLinkVisitUserAgentsRow
+		if err := rows.Scan(
+			&i.UserAgent,
+			&i.Count,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const linkVisitStatuses = `-- name: LinkVisitStatuses :many
+SELECT status, COUNT(*) AS count
+FROM link_visits
+WHERE link_id = ?
+  AND created_at >= strftime('%Y-%m-%d %H:%M:%f', ?)
+  AND created_at < strftime('%Y-%m-%d %H:%M:%f', ?)
+  AND (? OR NOT is_bot)
+GROUP BY status
+ORDER BY count DESC, status
+`
+
+type LinkVisitStatusesParams struct {
+	LinkID      int64       `json:"link_id"`
+	FromTime    interface{} `json:"from_time"`
+	ToTime      interface{} `json:"to_time"`
+	IncludeBots bool        `json:"include_bots"`
+}
+
+type LinkVisitStatusesRow struct {
+	Status int64 `json:"status"`
+	Count  int64 `json:"count"`
+}
+
+func (q *Queries) LinkVisitStatuses(ctx context.Context, arg LinkVisitStatusesParams) ([]LinkVisitStatusesRow, error) {
+	rows, err := q.db.QueryContext(ctx, linkVisitStatuses, arg.LinkID, arg.FromTime, arg.ToTime, arg.IncludeBots)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []LinkVisitStatusesRow
+	for rows.Next() {
+		var i LinkVisitStatusesRow
+		if err := rows.Scan(
+			&i.Status,
+			&i.Count,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const topLinkVisitBrowsers = `-- name: TopLinkVisitBrowsers :many
+SELECT browser, COUNT(*) AS count
+FROM link_visits
+WHERE link_id = ?
+  AND created_at >= strftime('%Y-%m-%d %H:%M:%f', ?)
+  AND created_at < strftime('%Y-%m-%d %H:%M:%f', ?)
+  AND (? OR NOT is_bot)
+GROUP BY browser
+ORDER BY count DESC, browser
+LIMIT ?
+`
+
+type TopLinkVisitBrowsersParams struct {
+	LinkID      int64       `json:"link_id"`
+	FromTime    interface{} `json:"from_time"`
+	ToTime      interface{} `json:"to_time"`
+	IncludeBots bool        `json:"include_bots"`
+	TopLimit    int64       `json:"top_limit"`
+}
+
+type TopLinkVisitBrowsersRow struct {
+	Browser string `json:"browser"`
+	Count   int64  `json:"count"`
+}
+
+func (q *Queries) TopLinkVisitBrowsers(ctx context.Context, arg TopLinkVisitBrowsersParams) ([]TopLinkVisitBrowsersRow, error) {
+	rows, err := q.db.QueryContext(ctx, topLinkVisitBrowsers, arg.LinkID, arg.FromTime, arg.ToTime, arg.IncludeBots, arg.TopLimit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []TopLinkVisitBrowsersRow
+	for rows.Next() {
+		var i TopLinkVisitBrowsersRow
+		if err := rows.Scan(
+			&i.Browser,
+			&i.Count,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const topLinkVisitOperatingSystems = `-- name: TopLinkVisitOperatingSystems :many
+SELECT os, COUNT(*) AS count
+FROM link_visits
+WHERE link_id = ?
+  AND created_at >= strftime('%Y-%m-%d %H:%M:%f', ?)
+  AND created_at < strftime('%Y-%m-%d %H:%M:%f', ?)
+  AND (? OR NOT is_bot)
+GROUP BY os
+ORDER BY count DESC, os
+LIMIT ?
+`
+
+type TopLinkVisitOperatingSystemsParams struct {
+	LinkID      int64       `json:"link_id"`
+	FromTime    interface{} `json:"from_time"`
+	ToTime      interface{} `json:"to_time"`
+	IncludeBots bool        `json:"include_bots"`
+	TopLimit    int64       `json:"top_limit"`
+}
+
+type TopLinkVisitOperatingSystemsRow struct {
+	Os    string `json:"os"`
+	Count int64  `json:"count"`
+}
+
+func (q *Queries) TopLinkVisitOperatingSystems(ctx context.Context, arg TopLinkVisitOperatingSystemsParams) ([]TopLinkVisitOperatingSystemsRow, error) {
+	rows, err := q.db.QueryContext(ctx, topLinkVisitOperatingSystems, arg.LinkID, arg.FromTime, arg.ToTime, arg.IncludeBots, arg.TopLimit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []TopLinkVisitOperatingSystemsRow
+	for rows.Next() {
+		var i TopLinkVisitOperatingSystemsRow
+		if err := rows.Scan(
+			&i.Os,
+			&i.Count,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const linkVisitDevices = `-- name: LinkVisitDevices :many
+SELECT device, COUNT(*) AS count
+FROM link_visits
+WHERE link_id = ?
+  AND created_at >= strftime('%Y-%m-%d %H:%M:%f', ?)
+  AND created_at < strftime('%Y-%m-%d %H:%M:%f', ?)
+  AND (? OR NOT is_bot)
+GROUP BY device
+ORDER BY count DESC, device
+`
+
+type LinkVisitDevicesParams struct {
+	LinkID      int64       `json:"link_id"`
+	FromTime    interface{} `json:"from_time"`
+	ToTime      interface{} `json:"to_time"`
+	IncludeBots bool        `json:"include_bots"`
+}
+
+type LinkVisitDevicesRow struct {
+	Device string `json:"device"`
+	Count  int64  `json:"count"`
+}
+
+func (q *Queries) LinkVisitDevices(ctx context.Context, arg LinkVisitDevicesParams) ([]LinkVisitDevicesRow, error) {
+	rows, err := q.db.QueryContext(ctx, linkVisitDevices, arg.LinkID, arg.FromTime, arg.ToTime, arg.IncludeBots)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []LinkVisitDevicesRow
+	for rows.Next() {
+		var i LinkVisitDevicesRow
+		if err := rows.Scan(
+			&i.Device,
+			&i.Count,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const linkVisitCountries = `-- name: LinkVisitCountries :many
+SELECT country, COUNT(*) AS count
+FROM link_visits
+WHERE link_id = ?
+  AND created_at >= strftime('%Y-%m-%d %H:%M:%f', ?)
+  AND created_at < strftime('%Y-%m-%d %H:%M:%f', ?)
+  AND (? OR NOT is_bot)
+GROUP BY country
+ORDER BY count DESC, country
+`
+
+type LinkVisitCountriesParams struct {
+	LinkID      int64       `json:"link_id"`
+	FromTime    interface{} `json:"from_time"`
+	ToTime      interface{} `json:"to_time"`
+	IncludeBots bool        `json:"include_bots"`
+}
+
+type LinkVisitCountriesRow struct {
+	Country string `json:"country"`
+	Count   int64  `json:"count"`
+}
+
+func (q *Queries) LinkVisitCountries(ctx context.Context, arg LinkVisitCountriesParams) ([]LinkVisitCountriesRow, error) {
+	rows, err := q.db.QueryContext(ctx, linkVisitCountries, arg.LinkID, arg.FromTime, arg.ToTime, arg.IncludeBots)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []LinkVisitCountriesRow
+	for rows.Next() {
+		var i LinkVisitCountriesRow
+		if err := rows.Scan(
+			&i.Country,
+			&i.Count,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const countLinkVisitsBefore = `-- name: CountLinkVisitsBefore :one
+SELECT COUNT(*) FROM link_visits
+WHERE created_at < strftime('%Y-%m-%d %H:%M:%f', ?)
+`
+
+func (q *Queries) CountLinkVisitsBefore(ctx context.Context, before interface{}) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countLinkVisitsBefore, before)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const deleteLinkVisitsBefore = `-- name: DeleteLinkVisitsBefore :execrows
+DELETE FROM link_visits
+WHERE id IN (
+  SELECT id FROM link_visits
+  WHERE created_at < strftime('%Y-%m-%d %H:%M:%f', ?)
+  ORDER BY created_at
+  LIMIT ?
+)
+`
+
+type DeleteLinkVisitsBeforeParams struct {
+	Before   interface{} `json:"before"`
+	RowLimit int64       `json:"row_limit"`
+}
+
+func (q *Queries) DeleteLinkVisitsBefore(ctx context.Context, arg DeleteLinkVisitsBeforeParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteLinkVisitsBefore, arg.Before, arg.RowLimit)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const countLinkVisitGroups = `-- name: CountLinkVisitGroups :many
+SELECT link_id, status, is_bot, referer, COUNT(*) AS count
+FROM link_visits
+WHERE (? IS NULL OR link_id = ?)
+  AND created_at >= strftime('%Y-%m-%d %H:%M:%f', ?)
+  AND created_at < strftime('%Y-%m-%d %H:%M:%f', ?)
+GROUP BY link_id, status, is_bot, referer
+`
+
+type CountLinkVisitGroupsParams struct {
+	LinkID   sql.NullInt64 `json:"link_id"`
+	FromTime interface{}   `json:"from_time"`
+	ToTime   interface{}   `json:"to_time"`
+}
+
+type CountLinkVisitGroupsRow struct {
+	LinkID  int64  `json:"link_id"`
+	Status  int64  `json:"status"`
+	IsBot   bool   `json:"is_bot"`
+	Referer string `json:"referer"`
+	Count   int64  `json:"count"`
+}
+
+func (q *Queries) CountLinkVisitGroups(ctx context.Context, arg CountLinkVisitGroupsParams) ([]CountLinkVisitGroupsRow, error) {
+	rows, err := q.db.QueryContext(ctx, countLinkVisitGroups,
+		arg.LinkID,
+		arg.LinkID,
+		arg.FromTime,
+		arg.ToTime,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []CountLinkVisitGroupsRow
+	for rows.Next() {
+		var i CountLinkVisitGroupsRow
+		if err := rows.Scan(
+			&i.LinkID,
+			&i.Status,
+			&i.IsBot,
+			&i.Referer,
+			&i.Count,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const firstLinkVisitCreatedAt = `-- name: FirstLinkVisitCreatedAt :one
+SELECT created_at FROM link_visits
+ORDER BY created_at
+LIMIT 1
+`
+
+func (q *Queries) FirstLinkVisitCreatedAt(ctx context.Context) (time.Time, error) {
+	row := q.db.QueryRowContext(ctx, firstLinkVisitCreatedAt)
+	var created_at time.Time
+	err := row.Scan(&created_at)
+	return created_at, err
+}
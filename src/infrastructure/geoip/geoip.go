@@ -0,0 +1,155 @@
+package geoip
+
+import (
+	"log"
+	"net"
+	"os"
+	"sync/atomic"
+	"time"
+
+	"github.com/oschwald/maxminddb-golang"
+
+	domain "link-service/src/domain/linkvisit"
+)
+
+/*Параметры базы GeoIP*/
+type Config struct {
+	Path           string        /*Путь к файлу .mmdb (GeoLite2/GeoIP2 City или Country)*/
+	ReloadInterval time.Duration /*Период проверки изменения файла (0 — без перезагрузки)*/
+}
+
+/*Поля записи MaxMind City/Country, нужные для посещений*/
+type cityRecord struct {
+	Country struct {
+		ISOCode string `maxminddb:"iso_code"`
+	} `maxminddb:"country"`
+	Subdivisions []struct {
+		Names map[string]string `maxminddb:"names"`
+	} `maxminddb:"subdivisions"`
+	City struct {
+		Names map[string]string `maxminddb:"names"`
+	} `maxminddb:"city"`
+}
+
+/*Определение местоположения по локальному файлу MaxMind с перезагрузкой при изменении*/
+type Reader struct {
+	path string
+	db   atomic.Pointer[maxminddb.Reader]
+
+	// состояние файла меняет только горутина перезагрузки
+	modTime time.Time
+	size    int64
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+/*Метод открытия базы и запуска проверки изменений файла*/
+func Open(cfg Config) (*Reader, error) {
+	r := &Reader{
+		path: cfg.Path,
+		stop: make(chan struct{}),
+		done: make(chan struct{}),
+	}
+
+	if _, err := r.reload(); err != nil {
+		return nil, err
+	}
+
+	if cfg.ReloadInterval <= 0 {
+		close(r.done)
+		return r, nil
+	}
+
+	go r.watch(cfg.ReloadInterval)
+
+	return r, nil
+}
+
+/*Местоположение IP (пустое, если IP не найден или некорректен)*/
+func (r *Reader) Locate(ip string) domain.Location {
+	addr := net.ParseIP(ip)
+	if addr == nil {
+		return domain.Location{}
+	}
+
+	var rec cityRecord
+	if err := r.db.Load().Lookup(addr, &rec); err != nil {
+		return domain.Location{}
+	}
+
+	res := domain.Location{
+		Country: rec.Country.ISOCode,
+		City:    rec.City.Names["en"],
+	}
+	if len(rec.Subdivisions) > 0 {
+		res.Region = rec.Subdivisions[0].Names["en"]
+	}
+
+	return res
+}
+
+/*Метод остановки проверки изменений файла*/
+func (r *Reader) Close() error {
+	select {
+	case <-r.stop:
+	default:
+		close(r.stop)
+	}
+	<-r.done
+
+	return nil
+}
+
+/*Периодическая проверка файла: ошибка перезагрузки оставляет предыдущую версию базы*/
+func (r *Reader) watch(interval time.Duration) {
+	defer close(r.done)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-r.stop:
+			return
+		case <-ticker.C:
+			reloaded, err := r.reload()
+			if err != nil {
+				log.Printf("geoip: reload %s: %v", r.path, err)
+				continue
+			}
+			if reloaded {
+				log.Printf("geoip: reloaded %s", r.path)
+			}
+		}
+	}
+}
+
+/*Загрузка файла, если он изменился с прошлой загрузки*/
+func (r *Reader) reload() (bool, error) {
+	info, err := os.Stat(r.path)
+	if err != nil {
+		return false, err
+	}
+	if r.db.Load() != nil && info.ModTime().Equal(r.modTime) && info.Size() == r.size {
+		return false, nil
+	}
+
+	// файл читается в память целиком: старую версию можно отпустить, не дожидаясь текущих Locate
+	data, err := os.ReadFile(r.path)
+	if err != nil {
+		return false, err
+	}
+
+	db, err := maxminddb.FromBytes(data)
+	if err != nil {
+		return false, err
+	}
+
+	r.db.Store(db)
+	r.modTime, r.size = info.ModTime(), info.Size()
+
+	return true, nil
+}
+
+var _ domain.GeoLocator = (*Reader)(nil)
@@ -0,0 +1,138 @@
+package geoip
+
+import (
+	"bytes"
+	"encoding/binary"
+	"net"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/go-playground/assert/v2"
+
+	domain "link-service/src/domain/linkvisit"
+)
+
+/*Минимальный кодировщик формата MaxMind DB (только типы, нужные тесту)*/
+func encode(buf *bytes.Buffer, v any) {
+	switch v := v.(type) {
+	case string:
+		buf.WriteByte(2<<5 | byte(len(v)))
+		buf.WriteString(v)
+	case uint16:
+		buf.WriteByte(5<<5 | 2)
+		_ = binary.Write(buf, binary.BigEndian, v)
+	case uint32:
+		buf.WriteByte(6<<5 | 4)
+		_ = binary.Write(buf, binary.BigEndian, v)
+	case map[string]any:
+		buf.WriteByte(7<<5 | byte(len(v)))
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			encode(buf, k)
+			encode(buf, v[k])
+		}
+	case []any:
+		// array — расширенный тип 11: размер в управляющем байте, (11 - 7) в следующем
+		buf.WriteByte(byte(len(v)))
+		buf.WriteByte(11 - 7)
+		for _, item := range v {
+			encode(buf, item)
+		}
+	}
+}
+
+/*Запись IPv4-базы с одной сетью /24 (record_size 24)*/
+func writeTestDB(t *testing.T, path string, network string, record map[string]any) {
+	t.Helper()
+
+	_, ipNet, err := net.ParseCIDR(network)
+	assert.Equal(t, nil, err)
+	ip := ipNet.IP.To4()
+	ones, _ := ipNet.Mask.Size()
+
+	var data bytes.Buffer
+	encode(&data, record)
+
+	nodeCount := uint32(ones)
+	dataPointer := nodeCount + 16 // смещение 0 в секции данных
+
+	var tree bytes.Buffer
+	for i := 0; i < ones; i++ {
+		bit := (ip[i/8] >> (7 - uint(i%8))) & 1
+		next := uint32(i + 1)
+		if i == ones-1 {
+			next = dataPointer
+		}
+
+		records := [2]uint32{nodeCount, nodeCount}
+		records[bit] = next
+		for _, rec := range records {
+			tree.Write([]byte{byte(rec >> 16), byte(rec >> 8), byte(rec)})
+		}
+	}
+
+	var out bytes.Buffer
+	out.Write(tree.Bytes())
+	out.Write(make([]byte, 16))
+	out.Write(data.Bytes())
+	out.WriteString("\xAB\xCD\xEFMaxMind.com")
+	encode(&out, map[string]any{
+		"binary_format_major_version": uint16(2),
+		"binary_format_minor_version": uint16(0),
+		"database_type":               "GeoIP2-City",
+		"ip_version":                  uint16(4),
+		"node_count":                  nodeCount,
+		"record_size":                 uint16(24),
+	})
+
+	assert.Equal(t, nil, os.WriteFile(path, out.Bytes(), 0o644))
+}
+
+func testCity(country, region, city string) map[string]any {
+	return map[string]any{
+		"country":      map[string]any{"iso_code": country},
+		"subdivisions": []any{map[string]any{"names": map[string]any{"en": region}}},
+		"city":         map[string]any{"names": map[string]any{"en": city}},
+	}
+}
+
+func TestReaderLocateAndReload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "city.mmdb")
+	writeTestDB(t, path, "81.2.69.0/24", testCity("GB", "England", "London"))
+
+	r, err := Open(Config{Path: path, ReloadInterval: 10 * time.Millisecond})
+	assert.Equal(t, nil, err)
+	defer func() { _ = r.Close() }()
+
+	assert.Equal(t, domain.Location{Country: "GB", Region: "England", City: "London"}, r.Locate("81.2.69.142"))
+	assert.Equal(t, domain.Location{}, r.Locate("10.0.0.1"))
+	assert.Equal(t, domain.Location{}, r.Locate("not an ip"))
+
+	// новая версия файла подхватывается без перезапуска
+	writeTestDB(t, path, "81.2.69.0/24", testCity("DE", "Bavaria", "Munich"))
+	future := time.Now().Add(time.Hour)
+	assert.Equal(t, nil, os.Chtimes(path, future, future))
+
+	deadline := time.Now().Add(2 * time.Second)
+	for r.Locate("81.2.69.142").Country != "DE" && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	assert.Equal(t, domain.Location{Country: "DE", Region: "Bavaria", City: "Munich"}, r.Locate("81.2.69.142"))
+
+	// битый файл не заменяет загруженную базу
+	assert.Equal(t, nil, os.WriteFile(path, []byte("garbage"), 0o644))
+	time.Sleep(50 * time.Millisecond)
+	assert.Equal(t, "DE", r.Locate("81.2.69.142").Country)
+}
+
+func TestOpenMissingFile(t *testing.T) {
+	_, err := Open(Config{Path: filepath.Join(t.TempDir(), "missing.mmdb")})
+	assert.NotEqual(t, nil, err)
+}
@@ -0,0 +1,110 @@
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+
+	domain "link-service/src/domain/ratelimit"
+)
+
+/*Как часто удалять заполненные корзины, чтобы карта не росла от разовых клиентов*/
+const sweepInterval = time.Minute
+
+/*Корзина токенов одного ключа*/
+type bucket struct {
+	tokens  float64
+	updated time.Time
+	full    time.Time /*Момент, когда корзина заполнится (после него её можно удалить)*/
+}
+
+/*Хранилище корзин токенов в памяти процесса (у каждого экземпляра сервиса — свои лимиты)*/
+type MemoryStore struct {
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	maxKeys   int
+	lastSweep time.Time
+}
+
+/*Метод создания хранилища в памяти не больше чем на maxKeys корзин (0 и меньше — без ограничения)*/
+func NewMemoryStore(maxKeys int) *MemoryStore {
+	return &MemoryStore{buckets: make(map[string]*bucket), maxKeys: maxKeys}
+}
+
+/*Метод списания токена из корзины key*/
+func (s *MemoryStore) Take(ctx context.Context, key string, p domain.Policy, now time.Time) (domain.Result, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.sweep(now)
+
+	burst := float64(p.Burst)
+	b, ok := s.buckets[key]
+	if !ok {
+		s.makeRoom(now)
+		b = &bucket{tokens: burst, updated: now}
+		s.buckets[key] = b
+	}
+
+	if elapsed := now.Sub(b.updated).Seconds(); elapsed > 0 {
+		b.tokens = math.Min(burst, b.tokens+elapsed*p.Rate)
+		b.updated = now
+	}
+
+	res := domain.Result{Limit: p.Burst}
+	if b.tokens >= 1 {
+		b.tokens--
+		res.Allowed = true
+	} else {
+		res.RetryAfter = seconds((1 - b.tokens) / p.Rate)
+	}
+
+	res.Remaining = int(b.tokens)
+	res.Reset = seconds((burst - b.tokens) / p.Rate)
+	b.full = now.Add(res.Reset)
+
+	return res, nil
+}
+
+/*Метод удаления заполненных корзин: новая корзина для того же ключа будет такой же*/
+func (s *MemoryStore) sweep(now time.Time) {
+	if now.Sub(s.lastSweep) < sweepInterval {
+		return
+	}
+	s.lastSweep = now
+
+	for key, b := range s.buckets {
+		if !now.Before(b.full) {
+			delete(s.buckets, key)
+		}
+	}
+}
+
+// makeRoom освобождает место под новую корзину, когда их уже maxKeys.
+//
+// Сначала удаляются заполненные корзины; если их нет, удаляется произвольная. Потерявший
+// корзину клиент получает её заново полной — это лучше, чем неограниченный рост карты
+// при запросах с множества адресов.
+func (s *MemoryStore) makeRoom(now time.Time) {
+	if s.maxKeys <= 0 || len(s.buckets) < s.maxKeys {
+		return
+	}
+
+	s.lastSweep = time.Time{}
+	s.sweep(now)
+
+	for key := range s.buckets {
+		if len(s.buckets) < s.maxKeys {
+			return
+		}
+		delete(s.buckets, key)
+	}
+}
+
+/*Метод перевода секунд в длительность*/
+func seconds(s float64) time.Duration {
+	return time.Duration(s * float64(time.Second))
+}
+
+var _ domain.Store = (*MemoryStore)(nil)
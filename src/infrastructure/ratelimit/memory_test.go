@@ -0,0 +1,74 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	domain "link-service/src/domain/ratelimit"
+
+	"github.com/go-playground/assert/v2"
+)
+
+func TestMemoryStoreTokenBucket(t *testing.T) {
+	ctx := context.Background()
+	s := NewMemoryStore(0)
+	p := domain.Policy{Rate: 2, Burst: 3}
+	now := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	for i := 2; i >= 0; i-- {
+		res, err := s.Take(ctx, "ip:1.2.3.4", p, now)
+		assert.Equal(t, nil, err)
+		assert.Equal(t, true, res.Allowed)
+		assert.Equal(t, 3, res.Limit)
+		assert.Equal(t, i, res.Remaining)
+	}
+
+	res, _ := s.Take(ctx, "ip:1.2.3.4", p, now)
+	assert.Equal(t, false, res.Allowed)
+	assert.Equal(t, 500*time.Millisecond, res.RetryAfter)
+	assert.Equal(t, 1500*time.Millisecond, res.Reset)
+
+	// у другого ключа своя корзина
+	res, _ = s.Take(ctx, "ip:5.6.7.8", p, now)
+	assert.Equal(t, true, res.Allowed)
+
+	// за полсекунды набирается один токен
+	res, _ = s.Take(ctx, "ip:1.2.3.4", p, now.Add(500*time.Millisecond))
+	assert.Equal(t, true, res.Allowed)
+	assert.Equal(t, 0, res.Remaining)
+}
+
+func TestMemoryStoreSweepsFullBuckets(t *testing.T) {
+	ctx := context.Background()
+	s := NewMemoryStore(0)
+	p := domain.Policy{Rate: 1, Burst: 1}
+	now := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	_, _ = s.Take(ctx, "a", p, now)
+	_, _ = s.Take(ctx, "b", p, now.Add(sweepInterval))
+	assert.Equal(t, 1, len(s.buckets))
+
+	// снова пустая корзина «a» после удаления — полная
+	res, _ := s.Take(ctx, "a", p, now.Add(sweepInterval))
+	assert.Equal(t, true, res.Allowed)
+}
+
+func TestMemoryStoreCapsKeys(t *testing.T) {
+	ctx := context.Background()
+	s := NewMemoryStore(2)
+	p := domain.Policy{Rate: 1, Burst: 1}
+	now := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	for _, key := range []string{"a", "b", "c", "d"} {
+		res, _ := s.Take(ctx, key, p, now)
+		assert.Equal(t, true, res.Allowed)
+		assert.Equal(t, true, len(s.buckets) <= 2)
+	}
+
+	// при нехватке места сначала удаляются заполненные корзины
+	_, _ = s.Take(ctx, "e", p, now.Add(time.Second))
+	assert.Equal(t, 1, len(s.buckets))
+	_, ok := s.buckets["e"]
+	assert.Equal(t, true, ok)
+}
@@ -0,0 +1,245 @@
+package cache
+
+import (
+	"container/list"
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"link-service/src/domain/entity"
+	domain "link-service/src/domain/link"
+)
+
+/*Параметры кеша ссылок*/
+type Config struct {
+	Size        int           /*Максимальное количество записей*/
+	TTL         time.Duration /*Время жизни найденной ссылки*/
+	NegativeTTL time.Duration /*Время жизни записи о ненайденной ссылке*/
+}
+
+/*Счётчики кеша ссылок*/
+type Stats struct {
+	Hits      int64 `json:"hits"`      /*Попадания*/
+	Misses    int64 `json:"misses"`    /*Промахи*/
+	Evictions int64 `json:"evictions"` /*Вытеснения по размеру*/
+	Size      int   `json:"size"`      /*Текущее количество записей*/
+}
+
+/*Запись кеша*/
+type entry struct {
+	shortName string
+	link      entity.Link
+	notFound  bool
+	expiresAt time.Time
+}
+
+/*Декоратор репозитория ссылок с LRU-кешем поиска по short_name*/
+type Repository struct {
+	domain.Repository
+
+	cfg Config
+	now func() time.Time
+
+	mu       sync.Mutex
+	lru      *list.List
+	byName   map[string]*list.Element
+	nameByID map[int64]string
+	gen      uint64 /*Поколение: растёт при каждом сбросе, чтобы не сохранить устаревшее значение*/
+
+	hits      atomic.Int64
+	misses    atomic.Int64
+	evictions atomic.Int64
+}
+
+/*Метод создания кеширующего репозитория*/
+func New(repo domain.Repository, cfg Config) *Repository {
+	return &Repository{
+		Repository: repo,
+		cfg:        cfg,
+		now:        time.Now,
+		lru:        list.New(),
+		byName:     make(map[string]*list.Element),
+		nameByID:   make(map[int64]string),
+	}
+}
+
+/*Получение ссылки по short_name с кешированием, в том числе отрицательным*/
+func (r *Repository) GetByShortName(ctx context.Context, shortName string) (entity.Link, error) {
+	if l, notFound, ok := r.lookup(shortName); ok {
+		r.hits.Add(1)
+		if notFound {
+			return entity.Link{}, domain.ErrNotFound
+		}
+		return l, nil
+	}
+
+	r.misses.Add(1)
+
+	gen := r.generation()
+	l, err := r.Repository.GetByShortName(ctx, shortName)
+	switch {
+	case err == nil:
+		r.store(gen, entry{shortName: shortName, link: l, expiresAt: r.now().Add(r.cfg.TTL)})
+	case errors.Is(err, domain.ErrNotFound) && r.cfg.NegativeTTL > 0:
+		r.store(gen, entry{shortName: shortName, notFound: true, expiresAt: r.now().Add(r.cfg.NegativeTTL)})
+	}
+
+	return l, err
+}
+
+/*Создание ссылки со сбросом отрицательной записи для short_name*/
+func (r *Repository) Create(ctx context.Context, in domain.CreateInput) (entity.Link, error) {
+	l, err := r.Repository.Create(ctx, in)
+	r.invalidateName(in.ShortName)
+
+	return l, err
+}
+
+/*Пакетное создание ссылок со сбросом отрицательных записей для их short_name*/
+func (r *Repository) CreateBatch(ctx context.Context, in []domain.CreateInput, atomic bool) ([]domain.BatchResult, error) {
+	res, err := r.Repository.CreateBatch(ctx, in, atomic)
+	for _, v := range in {
+		r.invalidateName(v.ShortName)
+	}
+
+	return res, err
+}
+
+/*Обновление ссылки со сбросом старого и нового short_name*/
+func (r *Repository) Update(ctx context.Context, id int64, in domain.UpdateInput) (entity.Link, error) {
+	l, err := r.Repository.Update(ctx, id, in)
+	r.invalidateID(id)
+	r.invalidateName(in.ShortName)
+
+	return l, err
+}
+
+/*Удаление ссылки со сбросом кеша*/
+func (r *Repository) Delete(ctx context.Context, id int64) error {
+	err := r.Repository.Delete(ctx, id)
+	r.invalidateID(id)
+
+	return err
+}
+
+/*Восстановление ссылки из корзины со сбросом отрицательной записи для её short_name*/
+func (r *Repository) Restore(ctx context.Context, id int64) (entity.Link, error) {
+	l, err := r.Repository.Restore(ctx, id)
+	if err == nil {
+		r.invalidateName(l.ShortName)
+	}
+
+	return l, err
+}
+
+/*Текущие счётчики кеша*/
+func (r *Repository) Stats() Stats {
+	r.mu.Lock()
+	size := r.lru.Len()
+	r.mu.Unlock()
+
+	return Stats{
+		Hits:      r.hits.Load(),
+		Misses:    r.misses.Load(),
+		Evictions: r.evictions.Load(),
+		Size:      size,
+	}
+}
+
+/*Поиск записи в кеше с учётом TTL*/
+func (r *Repository) lookup(shortName string) (entity.Link, bool, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	el, ok := r.byName[shortName]
+	if !ok {
+		return entity.Link{}, false, false
+	}
+
+	e := el.Value.(*entry)
+	if !r.now().Before(e.expiresAt) {
+		r.remove(el)
+		return entity.Link{}, false, false
+	}
+
+	r.lru.MoveToFront(el)
+	return e.link, e.notFound, true
+}
+
+/*Текущее поколение кеша*/
+func (r *Repository) generation() uint64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.gen
+}
+
+/*Сохранение записи с вытеснением самой старой при переполнении*/
+func (r *Repository) store(gen uint64, e entry) {
+	if r.cfg.Size <= 0 {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// пока шёл запрос в хранилище, ссылку могли изменить — такое значение не кешируем
+	if gen != r.gen {
+		return
+	}
+
+	if el, ok := r.byName[e.shortName]; ok {
+		r.remove(el)
+	}
+
+	r.byName[e.shortName] = r.lru.PushFront(&e)
+	if !e.notFound {
+		r.nameByID[e.link.ID] = e.shortName
+	}
+
+	for r.lru.Len() > r.cfg.Size {
+		r.remove(r.lru.Back())
+		r.evictions.Add(1)
+	}
+}
+
+/*Сброс записи по short_name*/
+func (r *Repository) invalidateName(shortName string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.gen++
+
+	if el, ok := r.byName[shortName]; ok {
+		r.remove(el)
+	}
+}
+
+/*Сброс записи по идентификатору ссылки*/
+func (r *Repository) invalidateID(id int64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.gen++
+
+	if name, ok := r.nameByID[id]; ok {
+		if el, ok := r.byName[name]; ok {
+			r.remove(el)
+		}
+	}
+}
+
+/*Удаление элемента из списка и индексов (вызывается под mu)*/
+func (r *Repository) remove(el *list.Element) {
+	e := el.Value.(*entry)
+
+	r.lru.Remove(el)
+	delete(r.byName, e.shortName)
+	if !e.notFound && r.nameByID[e.link.ID] == e.shortName {
+		delete(r.nameByID, e.link.ID)
+	}
+}
+
+var _ domain.Repository = (*Repository)(nil)
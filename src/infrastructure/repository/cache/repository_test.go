@@ -0,0 +1,111 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"link-service/src/domain/entity"
+	domain "link-service/src/domain/link"
+
+	"github.com/go-playground/assert/v2"
+)
+
+type countingRepo struct {
+	domain.Repository
+
+	links map[string]entity.Link
+	calls int
+}
+
+func (r *countingRepo) GetByShortName(ctx context.Context, shortName string) (entity.Link, error) {
+	r.calls++
+	l, ok := r.links[shortName]
+	if !ok {
+		return entity.Link{}, domain.ErrNotFound
+	}
+	return l, nil
+}
+
+func (r *countingRepo) Create(ctx context.Context, in domain.CreateInput) (entity.Link, error) {
+	l := entity.Link{ID: int64(len(r.links) + 1), OriginalURL: in.OriginalURL, ShortName: in.ShortName}
+	r.links[in.ShortName] = l
+	return l, nil
+}
+
+func (r *countingRepo) Update(ctx context.Context, id int64, in domain.UpdateInput) (entity.Link, error) {
+	for name, l := range r.links {
+		if l.ID == id {
+			delete(r.links, name)
+		}
+	}
+	l := entity.Link{ID: id, OriginalURL: in.OriginalURL, ShortName: in.ShortName}
+	r.links[in.ShortName] = l
+	return l, nil
+}
+
+func newCountingRepo() *countingRepo {
+	return &countingRepo{links: map[string]entity.Link{
+		"a": {ID: 1, OriginalURL: "https://a.example", ShortName: "a"},
+		"b": {ID: 2, OriginalURL: "https://b.example", ShortName: "b"},
+		"c": {ID: 3, OriginalURL: "https://c.example", ShortName: "c"},
+	}}
+}
+
+func TestCacheHitsMissesAndEvictions(t *testing.T) {
+	inner := newCountingRepo()
+	repo := New(inner, Config{Size: 2, TTL: time.Minute, NegativeTTL: time.Minute})
+	ctx := context.Background()
+
+	_, _ = repo.GetByShortName(ctx, "a")
+	_, _ = repo.GetByShortName(ctx, "a")
+	_, _ = repo.GetByShortName(ctx, "b")
+	_, _ = repo.GetByShortName(ctx, "c") // вытесняет "a"
+	_, _ = repo.GetByShortName(ctx, "a")
+
+	assert.Equal(t, 4, inner.calls)
+	assert.Equal(t, Stats{Hits: 1, Misses: 4, Evictions: 2, Size: 2}, repo.Stats())
+}
+
+func TestCacheTTLAndNegativeCaching(t *testing.T) {
+	inner := newCountingRepo()
+	repo := New(inner, Config{Size: 10, TTL: time.Minute, NegativeTTL: time.Second})
+	ctx := context.Background()
+
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	repo.now = func() time.Time { return now }
+
+	_, err := repo.GetByShortName(ctx, "missing")
+	assert.Equal(t, domain.ErrNotFound, err)
+	_, err = repo.GetByShortName(ctx, "missing")
+	assert.Equal(t, domain.ErrNotFound, err)
+	assert.Equal(t, 1, inner.calls)
+
+	now = now.Add(2 * time.Second)
+	_, _ = repo.GetByShortName(ctx, "missing")
+	assert.Equal(t, 2, inner.calls)
+
+	_, err = repo.Create(ctx, domain.CreateInput{OriginalURL: "https://new.example", ShortName: "missing"})
+	assert.Equal(t, nil, err)
+
+	l, err := repo.GetByShortName(ctx, "missing")
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "https://new.example", l.OriginalURL)
+}
+
+func TestCacheInvalidatedOnUpdate(t *testing.T) {
+	inner := newCountingRepo()
+	repo := New(inner, Config{Size: 10, TTL: time.Minute, NegativeTTL: time.Minute})
+	ctx := context.Background()
+
+	_, _ = repo.GetByShortName(ctx, "a")
+	_, err := repo.Update(ctx, 1, domain.UpdateInput{OriginalURL: "https://changed.example", ShortName: "a2"})
+	assert.Equal(t, nil, err)
+
+	_, err = repo.GetByShortName(ctx, "a")
+	assert.Equal(t, domain.ErrNotFound, err)
+
+	l, err := repo.GetByShortName(ctx, "a2")
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "https://changed.example", l.OriginalURL)
+}
@@ -0,0 +1,100 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+
+	domain "link-service/src/domain/apikey"
+	"link-service/src/domain/entity"
+)
+
+/*Репозиторий API ключей в памяти*/
+type APIKeyRepository struct {
+	mu     sync.RWMutex
+	keys   []entity.APIKey /*Ключи в порядке идентификаторов*/
+	nextID int64
+}
+
+/*Метод создания нового репозитория API ключей*/
+func NewAPIKeyRepository() *APIKeyRepository {
+	return &APIKeyRepository{}
+}
+
+/*Создание ключа*/
+func (r *APIKeyRepository) Create(ctx context.Context, in domain.CreateInput) (entity.APIKey, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.nextID++
+	ownerID := in.OwnerID
+	k := entity.APIKey{
+		ID:        r.nextID,
+		Name:      in.Name,
+		Prefix:    in.Prefix,
+		KeyHash:   in.KeyHash,
+		Scopes:    slices.Clone(in.Scopes),
+		CreatedAt: time.Now().UTC(),
+		OwnerID:   &ownerID,
+	}
+	r.keys = append(r.keys, k)
+
+	return k, nil
+}
+
+/*Получение действующего ключа по SHA-256*/
+func (r *APIKeyRepository) GetByHash(ctx context.Context, keyHash string) (entity.APIKey, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, k := range r.keys {
+		if k.KeyHash == keyHash && k.RevokedAt == nil {
+			return k, nil
+		}
+	}
+
+	return entity.APIKey{}, domain.ErrNotFound
+}
+
+/*Список всех ключей*/
+func (r *APIKeyRepository) List(ctx context.Context) ([]entity.APIKey, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return append([]entity.APIKey{}, r.keys...), nil
+}
+
+/*Отзыв ключа*/
+func (r *APIKeyRepository) Revoke(ctx context.Context, id int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, k := range r.keys {
+		if k.ID == id && k.RevokedAt == nil {
+			now := time.Now().UTC()
+			r.keys[i].RevokedAt = &now
+			return nil
+		}
+	}
+
+	return domain.ErrNotFound
+}
+
+/*Обновление даты последнего использования*/
+func (r *APIKeyRepository) Touch(ctx context.Context, id int64, usedAt time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, k := range r.keys {
+		if k.ID == id {
+			usedAt = usedAt.UTC()
+			r.keys[i].LastUsedAt = &usedAt
+			return nil
+		}
+	}
+
+	return nil
+}
+
+var _ domain.Repository = (*APIKeyRepository)(nil)
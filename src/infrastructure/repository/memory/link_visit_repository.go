@@ -0,0 +1,279 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"link-service/src/domain/entity"
+	"link-service/src/domain/link"
+	domain "link-service/src/domain/linkvisit"
+)
+
+/*Репозиторий посещений ссылок в памяти*/
+type LinkVisitRepository struct {
+	s *Store
+}
+
+/*Метод создания нового репозитория посещений*/
+func NewLinkVisitRepository(s *Store) *LinkVisitRepository {
+	return &LinkVisitRepository{s: s}
+}
+
+/*Создание записи посещения*/
+func (r *LinkVisitRepository) Create(ctx context.Context, in domain.CreateInput) (entity.LinkVisit, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	if _, ok := r.s.links[in.LinkID]; !ok {
+		return entity.LinkVisit{}, link.ErrNotFound
+	}
+
+	return r.insert(in), nil
+}
+
+/*Атомарное создание записи посещения с учётом лимита посещений ссылки*/
+func (r *LinkVisitRepository) CreateWithinLimit(ctx context.Context, in domain.CreateInput) (entity.LinkVisit, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	l, ok := r.s.links[in.LinkID]
+	if !ok || (l.MaxVisits != nil && l.VisitCount >= int64(*l.MaxVisits)) {
+		return entity.LinkVisit{}, domain.ErrLimitReached
+	}
+
+	l.VisitCount++
+	r.s.links[l.ID] = l
+
+	return r.insert(in), nil
+}
+
+/*Пакетное создание записей посещений (всё или ничего, как один INSERT)*/
+func (r *LinkVisitRepository) CreateBatch(ctx context.Context, in []domain.CreateInput) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	for _, v := range in {
+		if _, ok := r.s.links[v.LinkID]; !ok {
+			return fmt.Errorf("%w: link %d not found", domain.ErrInvalidRow, v.LinkID)
+		}
+	}
+
+	for _, v := range in {
+		r.insert(v)
+	}
+
+	return nil
+}
+
+/*Список посещений с range*/
+func (r *LinkVisitRepository) ListWithRange(ctx context.Context, rng *link.Range, f domain.Filter) ([]entity.LinkVisit, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	return append([]entity.LinkVisit(nil), window(r.filter(f), rng)...), nil
+}
+
+/*Список посещений после курсора*/
+func (r *LinkVisitRepository) ListAfter(ctx context.Context, cur *link.Cursor, f domain.Filter) ([]entity.LinkVisit, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	return after(r.filter(f), cur, func(v entity.LinkVisit) int64 { return v.ID }), nil
+}
+
+/*Обход посещений по фильтру в порядке id (блокировка снимается до вызова fn)*/
+func (r *LinkVisitRepository) Each(ctx context.Context, f domain.Filter, fn func(entity.LinkVisit) error) error {
+	r.s.mu.RLock()
+	visits := r.filter(f)
+	r.s.mu.RUnlock()
+
+	for _, v := range visits {
+		if err := fn(v); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+/*Количество посещений, подходящих под фильтр*/
+func (r *LinkVisitRepository) Count(ctx context.Context, f domain.Filter) (int64, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	return int64(len(r.filter(f))), nil
+}
+
+/*Количество посещений, созданных раньше before*/
+func (r *LinkVisitRepository) CountBefore(ctx context.Context, before time.Time) (int64, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	var res int64
+	for _, v := range r.s.visits {
+		if v.CreatedAt.Before(before) {
+			res++
+		}
+	}
+	return res, nil
+}
+
+/*Удаление не более limit самых старых посещений, созданных раньше before*/
+func (r *LinkVisitRepository) DeleteBefore(ctx context.Context, before time.Time, limit int) (int64, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	// новый срез, а не сдвиг на месте: ранее выданные списки не должны меняться
+	kept := make([]entity.LinkVisit, 0, len(r.s.visits))
+	var deleted int64
+	for _, v := range r.s.visits {
+		if deleted < int64(limit) && v.CreatedAt.Before(before) {
+			deleted++
+			continue
+		}
+		kept = append(kept, v)
+	}
+	r.s.visits = kept
+
+	return deleted, nil
+}
+
+/*Статистика посещений ссылки за период*/
+func (r *LinkVisitRepository) Stats(ctx context.Context, q domain.StatsQuery) (domain.Stats, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	var res domain.Stats
+	buckets := map[time.Time]int64{}
+	referers := map[string]int64{}
+	userAgents := map[string]int64{}
+	statuses := map[int]int64{}
+	browsers := map[string]int64{}
+	systems := map[string]int64{}
+	devices := map[string]int64{}
+	countries := map[string]int64{}
+	hosts := map[string]int64{}
+
+	for _, v := range r.s.visits {
+		if v.LinkID != q.LinkID || v.CreatedAt.Before(q.From) || !v.CreatedAt.Before(q.To) {
+			continue
+		}
+
+		if v.IsBot {
+			res.Bots++
+			if !q.IncludeBots {
+				continue
+			}
+		}
+
+		res.Total++
+		buckets[q.Interval.Truncate(v.CreatedAt)]++
+		referers[v.Referer]++
+		userAgents[v.UserAgent]++
+		statuses[v.Status]++
+		browsers[v.Browser]++
+		systems[v.OS]++
+		devices[v.Device]++
+		countries[v.Country]++
+		hosts[domain.RefererHost(v.Referer)]++
+	}
+
+	for start, clicks := range buckets {
+		res.Series = append(res.Series, domain.Bucket{Start: start, Clicks: clicks})
+	}
+	slices.SortFunc(res.Series, func(a, b domain.Bucket) int { return a.Start.Compare(b.Start) })
+
+	res.TopReferers = top(referers, q.Top)
+	res.TopUserAgents = top(userAgents, q.Top)
+	res.Browsers = top(browsers, q.Top)
+	res.OperatingSystems = top(systems, q.Top)
+	res.Devices = top(devices, len(devices))
+	res.Countries = top(countries, len(countries))
+	res.RefererHosts = domain.SortValueCounts(hosts)
+
+	for status, count := range statuses {
+		res.Statuses = append(res.Statuses, domain.StatusCount{Status: status, Count: count})
+	}
+	slices.SortFunc(res.Statuses, func(a, b domain.StatusCount) int {
+		if a.Count != b.Count {
+			return cmp.Compare(b.Count, a.Count)
+		}
+		return cmp.Compare(a.Status, b.Status)
+	})
+
+	return res, nil
+}
+
+/*Топ значений по убыванию количества (при равенстве — по значению, как ORDER BY count DESC, value)*/
+func top(counts map[string]int64, limit int) []domain.ValueCount {
+	res := domain.SortValueCounts(counts)
+	if len(res) > limit {
+		res = res[:limit]
+	}
+
+	return res
+}
+
+/*Посещения, подходящие под фильтр, в порядке id (вызывается под mu)*/
+func (r *LinkVisitRepository) filter(f domain.Filter) []entity.LinkVisit {
+	if f.IsEmpty() {
+		return r.s.visits
+	}
+
+	ip := strings.ToLower(f.IP)
+	referer := strings.ToLower(f.Referer)
+
+	res := []entity.LinkVisit{}
+	for _, v := range r.s.visits {
+		switch {
+		case f.LinkID != 0 && v.LinkID != f.LinkID,
+			f.From != nil && v.CreatedAt.Before(*f.From),
+			f.To != nil && !v.CreatedAt.Before(*f.To),
+			f.Status != 0 && v.Status != f.Status,
+			ip != "" && !strings.Contains(strings.ToLower(v.IP), ip),
+			referer != "" && !strings.Contains(strings.ToLower(v.Referer), referer),
+			f.Browser != "" && v.Browser != f.Browser,
+			f.OS != "" && v.OS != f.OS,
+			f.Device != "" && v.Device != string(f.Device),
+			f.IsBot != nil && v.IsBot != *f.IsBot,
+			f.OwnerID != 0 && !isOwner(r.s.links[v.LinkID], f.OwnerID):
+			continue
+		}
+
+		res = append(res, v)
+	}
+
+	return res
+}
+
+/*Добавление посещения (вызывается под mu)*/
+func (r *LinkVisitRepository) insert(in domain.CreateInput) entity.LinkVisit {
+	r.s.nextVisitID++
+	v := entity.LinkVisit{
+		ID:             r.s.nextVisitID,
+		LinkID:         in.LinkID,
+		IP:             in.IP,
+		UserAgent:      in.UserAgent,
+		Referer:        in.Referer,
+		Status:         in.Status,
+		Browser:        in.Agent.Browser,
+		BrowserVersion: in.Agent.BrowserVersion,
+		OS:             in.Agent.OS,
+		Device:         string(in.Agent.Device),
+		IsBot:          in.Agent.IsBot,
+		Country:        in.Geo.Country,
+		Region:         in.Geo.Region,
+		City:           in.Geo.City,
+		CreatedAt:      time.Now().UTC(),
+	}
+
+	r.s.visits = append(r.s.visits, v)
+
+	return v
+}
+
+var _ domain.Repository = (*LinkVisitRepository)(nil)
@@ -0,0 +1,92 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"time"
+
+	domain "link-service/src/domain/linkvisit"
+)
+
+/*Количество посещений за период, сгруппированное по ссылке, статусу, признаку бота и рефереру*/
+func (r *LinkVisitRepository) CountGroups(ctx context.Context, q domain.GroupQuery) ([]domain.VisitGroup, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	type groupKey struct {
+		linkID  int64
+		status  int
+		isBot   bool
+		referer string
+	}
+
+	counts := map[groupKey]int64{}
+	for _, v := range r.s.visits {
+		if q.LinkID != 0 && v.LinkID != q.LinkID || v.CreatedAt.Before(q.From) || !v.CreatedAt.Before(q.To) {
+			continue
+		}
+		counts[groupKey{v.LinkID, v.Status, v.IsBot, v.Referer}]++
+	}
+
+	res := make([]domain.VisitGroup, 0, len(counts))
+	for k, count := range counts {
+		res = append(res, domain.VisitGroup{LinkID: k.linkID, Status: k.status, IsBot: k.isBot, Referer: k.referer, Count: count})
+	}
+	return res, nil
+}
+
+/*Сохранение дневных счётчиков и сдвиг границы агрегации*/
+func (r *LinkVisitRepository) SaveDaily(ctx context.Context, day time.Time, counts []domain.DailyCount) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	day = domain.IntervalDay.Truncate(day)
+	for _, c := range counts {
+		r.s.daily[dailyKey{c.LinkID, day.Unix(), c.Status, c.IsBot, c.RefererHost}] = c.Visits
+	}
+
+	if next := day.AddDate(0, 0, 1); next.After(r.s.rolledUntil) {
+		r.s.rolledUntil = next
+	}
+
+	return nil
+}
+
+/*Дневные счётчики ссылки за дни [from, to)*/
+func (r *LinkVisitRepository) ListDaily(ctx context.Context, linkID int64, from, to time.Time) ([]domain.DailyCount, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	res := []domain.DailyCount{}
+	for k, visits := range r.s.daily {
+		day := time.Unix(k.day, 0).UTC()
+		if k.linkID != linkID || day.Before(from) || !day.Before(to) {
+			continue
+		}
+		res = append(res, domain.DailyCount{
+			LinkID:      k.linkID,
+			Day:         day,
+			Status:      k.status,
+			IsBot:       k.isBot,
+			RefererHost: k.refererHost,
+			Visits:      visits,
+		})
+	}
+	slices.SortFunc(res, func(a, b domain.DailyCount) int { return cmp.Compare(a.Day.Unix(), b.Day.Unix()) })
+
+	return res, nil
+}
+
+/*Состояние дневной агрегации*/
+func (r *LinkVisitRepository) RollupState(ctx context.Context) (domain.RollupState, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	res := domain.RollupState{RolledUntil: r.s.rolledUntil}
+	// посещения хранятся в порядке создания
+	if len(r.s.visits) > 0 {
+		res.FirstVisitAt = r.s.visits[0].CreatedAt
+	}
+	return res, nil
+}
@@ -0,0 +1,437 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"link-service/src/domain/entity"
+	domain "link-service/src/domain/link"
+)
+
+/*Репозиторий ссылок в памяти*/
+type Repository struct {
+	s *Store
+}
+
+/*Метод создания нового репозитория*/
+func New(s *Store) *Repository {
+	return &Repository{s: s}
+}
+
+/*Метод получения списка ссылок*/
+func (r *Repository) List(ctx context.Context, q domain.Query) ([]entity.Link, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	return r.query(q), nil
+}
+
+/*Метод получения списка ссылок*/
+func (r *Repository) ListWithRange(ctx context.Context, rng *domain.Range, q domain.Query) ([]entity.Link, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	return window(r.query(q), rng), nil
+}
+
+/*Метод получения списка ссылок после курсора*/
+func (r *Repository) ListAfter(ctx context.Context, cur *domain.Cursor, f domain.Filter) ([]entity.Link, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	return after(r.query(domain.Query{Filter: f}), cur, func(l entity.Link) int64 { return l.ID }), nil
+}
+
+/*Метод получения количества ссылок, подходящих под фильтр*/
+func (r *Repository) Count(ctx context.Context, f domain.Filter) (int64, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	return int64(len(r.query(domain.Query{Filter: f}))), nil
+}
+
+/*Метод обхода ссылок с фильтром и сортировкой (блокировка снимается до вызова fn)*/
+func (r *Repository) Each(ctx context.Context, q domain.Query, fn func(entity.Link) error) error {
+	r.s.mu.RLock()
+	links := r.query(q)
+	r.s.mu.RUnlock()
+
+	for _, l := range links {
+		if err := fn(l); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+/*Метод получения ссылки по идентификатору*/
+func (r *Repository) Get(ctx context.Context, id int64) (entity.Link, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	l, ok := r.s.links[id]
+	if !ok || l.DeletedAt != nil {
+		return entity.Link{}, domain.ErrNotFound
+	}
+
+	return l, nil
+}
+
+/*Метод получения ссылки по short_name*/
+func (r *Repository) GetByShortName(ctx context.Context, shortName string) (entity.Link, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	id, ok := r.s.shortNames[shortName]
+	if !ok || r.s.links[id].DeletedAt != nil {
+		return entity.Link{}, domain.ErrNotFound
+	}
+
+	return r.s.links[id], nil
+}
+
+/*Метод создания новой ссылки*/
+func (r *Repository) Create(ctx context.Context, in domain.CreateInput) (entity.Link, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	if _, ok := r.s.shortNames[in.ShortName]; ok {
+		return entity.Link{}, domain.ErrShortNameConflict
+	}
+
+	return r.insert(ctx, in), nil
+}
+
+/*Метод пакетного создания ссылок (atomic — при любом конфликте ничего не создаётся)*/
+func (r *Repository) CreateBatch(ctx context.Context, in []domain.CreateInput, atomic bool) ([]domain.BatchResult, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	res := make([]domain.BatchResult, len(in))
+	seen := make(map[string]bool, len(in))
+	conflict := false
+
+	for i, v := range in {
+		if _, ok := r.s.shortNames[v.ShortName]; ok || seen[v.ShortName] {
+			res[i].Conflict = true
+			conflict = true
+		}
+		seen[v.ShortName] = true
+	}
+
+	if atomic && conflict {
+		return res, domain.ErrShortNameConflict
+	}
+
+	for i, v := range in {
+		if !res[i].Conflict {
+			res[i].Link = r.insert(ctx, v)
+		}
+	}
+
+	return res, nil
+}
+
+/*Метод обновления ссылки*/
+func (r *Repository) Update(ctx context.Context, id int64, in domain.UpdateInput) (entity.Link, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	l, ok := r.s.links[id]
+	if !ok || l.DeletedAt != nil {
+		return entity.Link{}, domain.ErrNotFound
+	}
+
+	if owner, ok := r.s.shortNames[in.ShortName]; ok && owner != id {
+		return entity.Link{}, domain.ErrShortNameConflict
+	}
+
+	before := l
+	delete(r.s.shortNames, l.ShortName)
+
+	l.OriginalURL = in.OriginalURL
+	l.ShortName = in.ShortName
+	l.ExpiresAt = in.ExpiresAt
+	l.MaxVisits = in.MaxVisits
+	l.PasswordHash = in.PasswordHash
+	l.RedirectType = redirectTypeOrDefault(in.RedirectType)
+	if in.OwnerID != nil {
+		l.OwnerID = in.OwnerID
+	}
+
+	r.s.links[id] = l
+	r.s.shortNames[l.ShortName] = id
+	r.addEvent(ctx, domain.EventUpdated, &before, &l)
+
+	return l, nil
+}
+
+/*Метод перемещения ссылки в корзину*/
+func (r *Repository) Delete(ctx context.Context, id int64) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	l, ok := r.s.links[id]
+	if !ok || l.DeletedAt != nil {
+		return domain.ErrNotFound
+	}
+
+	now := time.Now().UTC()
+	l.DeletedAt = &now
+	r.s.links[id] = l
+	r.addEvent(ctx, domain.EventDeleted, &l, nil)
+
+	return nil
+}
+
+/*Метод восстановления ссылки из корзины*/
+func (r *Repository) Restore(ctx context.Context, id int64) (entity.Link, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	l, ok := r.s.links[id]
+	if !ok || l.DeletedAt == nil {
+		return entity.Link{}, domain.ErrNotFound
+	}
+
+	l.DeletedAt = nil
+	r.s.links[id] = l
+	r.addEvent(ctx, domain.EventRestored, nil, &l)
+
+	return l, nil
+}
+
+/*Метод окончательного удаления ссылок из корзины вместе с их посещениями (как ON DELETE CASCADE); история сохраняется с записью purged*/
+func (r *Repository) PurgeTrashed(ctx context.Context, before time.Time) (int64, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	purged := make(map[int64]bool)
+	for _, l := range r.sortedLinks(true) {
+		if l.DeletedAt.Before(before) {
+			purged[l.ID] = true
+			delete(r.s.links, l.ID)
+			delete(r.s.shortNames, l.ShortName)
+			r.addEvent(ctx, domain.EventPurged, &l, nil)
+		}
+	}
+	if len(purged) == 0 {
+		return 0, nil
+	}
+
+	visits := make([]entity.LinkVisit, 0, len(r.s.visits))
+	for _, v := range r.s.visits {
+		if !purged[v.LinkID] {
+			visits = append(visits, v)
+		}
+	}
+	r.s.visits = visits
+
+	for k := range r.s.daily {
+		if purged[k.linkID] {
+			delete(r.s.daily, k)
+		}
+	}
+
+	return int64(len(purged)), nil
+}
+
+/*Метод получения истории изменений ссылки (сохраняется и после окончательного удаления)*/
+func (r *Repository) History(ctx context.Context, id int64) ([]entity.LinkEvent, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	res := []entity.LinkEvent{}
+	for _, e := range r.s.events {
+		if e.LinkID == id {
+			res = append(res, e)
+		}
+	}
+	// у каждой ссылки есть хотя бы запись о создании
+	if len(res) == 0 {
+		return nil, domain.ErrNotFound
+	}
+
+	return res, nil
+}
+
+/*Добавление ссылки (вызывается под mu, short_name свободен)*/
+func (r *Repository) insert(ctx context.Context, in domain.CreateInput) entity.Link {
+	r.s.nextLinkID++
+	l := entity.Link{
+		ID:           r.s.nextLinkID,
+		OriginalURL:  in.OriginalURL,
+		ShortName:    in.ShortName,
+		CreatedAt:    time.Now().UTC(),
+		ExpiresAt:    in.ExpiresAt,
+		MaxVisits:    in.MaxVisits,
+		PasswordHash: in.PasswordHash,
+		RedirectType: redirectTypeOrDefault(in.RedirectType),
+		OwnerID:      in.OwnerID,
+	}
+
+	r.s.links[l.ID] = l
+	r.s.shortNames[l.ShortName] = l.ID
+	r.addEvent(ctx, domain.EventCreated, nil, &l)
+
+	return l
+}
+
+/*Запись изменения ссылки в историю (вызывается под mu)*/
+func (r *Repository) addEvent(ctx context.Context, eventType string, before, after *entity.Link) {
+	e := domain.NewEvent(ctx, eventType, before, after)
+	r.s.nextEventID++
+	e.ID = r.s.nextEventID
+	e.CreatedAt = time.Now().UTC()
+
+	r.s.events = append(r.s.events, e)
+}
+
+/*Метод получения активных ссылок или ссылок из корзины (trashed), отсортированных по идентификатору (вызывается под mu)*/
+func (r *Repository) sortedLinks(trashed bool) []entity.Link {
+	res := make([]entity.Link, 0, len(r.s.links))
+	for _, l := range r.s.links {
+		if (l.DeletedAt != nil) == trashed {
+			res = append(res, l)
+		}
+	}
+
+	sort.Slice(res, func(i, j int) bool { return res[i].ID < res[j].ID })
+
+	return res
+}
+
+/*Метод выборки ссылок по фильтру и сортировке (вызывается под mu)*/
+func (r *Repository) query(q domain.Query) []entity.Link {
+	links := r.sortedLinks(q.Filter.Trashed)
+
+	res := links[:0]
+	for _, l := range links {
+		if matches(l, q.Filter) {
+			res = append(res, l)
+		}
+	}
+
+	// список уже упорядочен по id — это второй ключ сортировки, как в SQL
+	if q.Sort.Desc {
+		slices.Reverse(res)
+	}
+
+	if q.Sort.Field != "" && q.Sort.Field != "id" {
+		sort.SliceStable(res, func(i, j int) bool { return less(res[i], res[j], q.Sort) })
+	}
+
+	return res
+}
+
+/*Метод проверки ссылки на соответствие фильтру*/
+func matches(l entity.Link, f domain.Filter) bool {
+	if f.Q != "" {
+		q := strings.ToLower(f.Q)
+		if !strings.Contains(strings.ToLower(l.OriginalURL), q) && !strings.Contains(strings.ToLower(l.ShortName), q) {
+			return false
+		}
+	}
+
+	if f.ShortName != "" && l.ShortName != f.ShortName {
+		return false
+	}
+
+	if f.IDs != nil && !slices.Contains(f.IDs, l.ID) {
+		return false
+	}
+
+	if f.OwnerID != 0 && !isOwner(l, f.OwnerID) {
+		return false
+	}
+
+	return true
+}
+
+/*Метод проверки владельца ссылки*/
+func isOwner(l entity.Link, ownerID int64) bool {
+	return l.OwnerID != nil && *l.OwnerID == ownerID
+}
+
+/*Метод сравнения ссылок по полю сортировки (NULL — в конце, как NULLS LAST)*/
+func less(a, b entity.Link, s domain.Sort) bool {
+	switch s.Field {
+	case "expires_at":
+		return lessNullable(a.ExpiresAt, b.ExpiresAt, s.Desc, func(x, y time.Time) bool { return x.Before(y) })
+	case "max_visits":
+		return lessNullable(a.MaxVisits, b.MaxVisits, s.Desc, func(x, y int) bool { return x < y })
+	case "deleted_at":
+		return lessNullable(a.DeletedAt, b.DeletedAt, s.Desc, func(x, y time.Time) bool { return x.Before(y) })
+	}
+
+	if s.Desc {
+		a, b = b, a
+	}
+
+	switch s.Field {
+	case "original_url":
+		return a.OriginalURL < b.OriginalURL
+	case "short_name":
+		return a.ShortName < b.ShortName
+	case "created_at":
+		return a.CreatedAt.Before(b.CreatedAt)
+	case "visit_count":
+		return a.VisitCount < b.VisitCount
+	case "redirect_type":
+		return a.RedirectType < b.RedirectType
+	default:
+		return a.ID < b.ID
+	}
+}
+
+/*Метод сравнения необязательных значений (nil — в конце при любом направлении)*/
+func lessNullable[T any](a, b *T, desc bool, lessFn func(x, y T) bool) bool {
+	switch {
+	case a == nil:
+		return false
+	case b == nil:
+		return true
+	case desc:
+		return lessFn(*b, *a)
+	default:
+		return lessFn(*a, *b)
+	}
+}
+
+/*Метод получения окна из списка по range (аналог LIMIT/OFFSET)*/
+func window[T any](items []T, rng *domain.Range) []T {
+	start := rng.Start
+	end := rng.End + 1
+
+	if start >= len(items) {
+		return []T{}
+	}
+	if end > len(items) {
+		end = len(items)
+	}
+
+	return items[start:end]
+}
+
+/*Метод получения страницы после курсора из отсортированного по id списка (аналог WHERE id > ? LIMIT ?)*/
+func after[T any](items []T, cur *domain.Cursor, id func(T) int64) []T {
+	start := sort.Search(len(items), func(i int) bool { return id(items[i]) > cur.AfterID })
+	end := min(start+cur.Limit, len(items))
+
+	return append([]T(nil), items[start:end]...)
+}
+
+/*Метод подстановки статуса редиректа по умолчанию (как DEFAULT в схеме)*/
+func redirectTypeOrDefault(status int) int {
+	if status == 0 {
+		return domain.DefaultRedirectType
+	}
+
+	return status
+}
+
+var _ domain.Repository = (*Repository)(nil)
@@ -0,0 +1,179 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"link-service/src/domain/link"
+	"link-service/src/domain/linkvisit"
+
+	"github.com/go-playground/assert/v2"
+)
+
+func TestLinkRepositoryConflictsAndNotFound(t *testing.T) {
+	ctx := context.Background()
+	repo := New(NewStore())
+
+	a, err := repo.Create(ctx, link.CreateInput{OriginalURL: "https://a.example", ShortName: "aaa"})
+	assert.Equal(t, nil, err)
+	b, err := repo.Create(ctx, link.CreateInput{OriginalURL: "https://b.example", ShortName: "bbb"})
+	assert.Equal(t, nil, err)
+
+	_, err = repo.Create(ctx, link.CreateInput{OriginalURL: "https://c.example", ShortName: "aaa"})
+	assert.Equal(t, link.ErrShortNameConflict, err)
+
+	_, err = repo.Update(ctx, b.ID, link.UpdateInput{OriginalURL: "https://b.example", ShortName: "aaa"})
+	assert.Equal(t, link.ErrShortNameConflict, err)
+
+	updated, err := repo.Update(ctx, a.ID, link.UpdateInput{OriginalURL: "https://a2.example", ShortName: "aaa2"})
+	assert.Equal(t, nil, err)
+	assert.Equal(t, a.CreatedAt, updated.CreatedAt)
+
+	_, err = repo.GetByShortName(ctx, "aaa")
+	assert.Equal(t, link.ErrNotFound, err)
+
+	_, err = repo.Get(ctx, 100)
+	assert.Equal(t, link.ErrNotFound, err)
+	_, err = repo.Update(ctx, 100, link.UpdateInput{OriginalURL: "https://x.example", ShortName: "xxx"})
+	assert.Equal(t, link.ErrNotFound, err)
+	assert.Equal(t, link.ErrNotFound, repo.Delete(ctx, 100))
+}
+
+func TestLinkRepositoryRangeAndCascadeDelete(t *testing.T) {
+	ctx := context.Background()
+	store := NewStore()
+	links := New(store)
+	visits := NewLinkVisitRepository(store)
+
+	for _, name := range []string{"l1", "l2", "l3", "l4"} {
+		_, err := links.Create(ctx, link.CreateInput{OriginalURL: "https://example.com/" + name, ShortName: name})
+		assert.Equal(t, nil, err)
+	}
+
+	page, err := links.ListWithRange(ctx, &link.Range{Start: 1, End: 2}, link.Query{})
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 2, len(page))
+	assert.Equal(t, "l2", page[0].ShortName)
+	assert.Equal(t, "l3", page[1].ShortName)
+
+	page, err = links.ListAfter(ctx, &link.Cursor{AfterID: 2, Limit: 10}, link.Filter{})
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 2, len(page))
+	assert.Equal(t, "l3", page[0].ShortName)
+
+	page, err = links.ListWithRange(ctx, &link.Range{Start: 10, End: 20}, link.Query{})
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 0, len(page))
+
+	assert.Equal(t, nil, visits.CreateBatch(ctx, []linkvisit.CreateInput{
+		{LinkID: 1, Status: 302},
+		{LinkID: 2, Status: 302},
+		{LinkID: 1, Status: 302},
+	}))
+	assert.Equal(t, true, errors.Is(visits.CreateBatch(ctx, []linkvisit.CreateInput{{LinkID: 99}}), linkvisit.ErrInvalidRow))
+
+	assert.Equal(t, nil, links.Delete(ctx, 1))
+
+	// посещения ссылки в корзине сохраняются до окончательного удаления
+	total, err := visits.Count(ctx, linkvisit.Filter{})
+	assert.Equal(t, nil, err)
+	assert.Equal(t, int64(3), total)
+
+	purged, err := links.PurgeTrashed(ctx, time.Now().Add(time.Minute))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, int64(1), purged)
+
+	total, err = visits.Count(ctx, linkvisit.Filter{})
+	assert.Equal(t, nil, err)
+	assert.Equal(t, int64(1), total)
+}
+
+func TestLinkRepositoryFilterAndSort(t *testing.T) {
+	ctx := context.Background()
+	repo := New(NewStore())
+
+	maxVisits := 5
+	for _, in := range []link.CreateInput{
+		{OriginalURL: "https://shop.example/PROMO", ShortName: "b-promo"},
+		{OriginalURL: "https://blog.example", ShortName: "a-promo", MaxVisits: &maxVisits},
+		{OriginalURL: "https://docs.example", ShortName: "docs"},
+	} {
+		_, err := repo.Create(ctx, in)
+		assert.Equal(t, nil, err)
+	}
+
+	q := link.Query{Filter: link.Filter{Q: "promo"}, Sort: link.Sort{Field: "short_name"}}
+	res, err := repo.List(ctx, q)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 2, len(res))
+	assert.Equal(t, "a-promo", res[0].ShortName)
+	assert.Equal(t, "b-promo", res[1].ShortName)
+
+	total, err := repo.Count(ctx, q.Filter)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, int64(2), total)
+
+	res, err = repo.List(ctx, link.Query{Sort: link.Sort{Field: "max_visits", Desc: true}})
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "a-promo", res[0].ShortName)
+	assert.Equal(t, "docs", res[1].ShortName)
+	assert.Equal(t, "b-promo", res[2].ShortName)
+
+	res, err = repo.List(ctx, link.Query{Filter: link.Filter{IDs: []int64{3, 1}}})
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 2, len(res))
+	assert.Equal(t, int64(1), res[0].ID)
+}
+
+func TestLinkRepositoryHistory(t *testing.T) {
+	ctx := link.WithRequestID(context.Background(), "req-1")
+	store := NewStore()
+	links := New(store)
+
+	l, err := links.Create(ctx, link.CreateInput{OriginalURL: "https://a.example", ShortName: "hist"})
+	assert.Equal(t, nil, err)
+	_, err = links.Update(ctx, l.ID, link.UpdateInput{OriginalURL: "https://b.example", ShortName: "hist"})
+	assert.Equal(t, nil, err)
+	assert.Equal(t, nil, links.Delete(ctx, l.ID))
+
+	events, err := links.History(ctx, l.ID)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 3, len(events))
+	assert.Equal(t, link.EventUpdated, events[1].Type)
+	assert.Equal(t, "https://a.example", events[1].OldOriginalURL)
+	assert.Equal(t, "https://b.example", events[1].NewOriginalURL)
+	assert.Equal(t, "req-1", events[1].RequestID)
+	assert.Equal(t, link.EventDeleted, events[2].Type)
+
+	// окончательное удаление не стирает историю, а дописывает запись purged
+	_, err = links.PurgeTrashed(ctx, time.Now().Add(time.Minute))
+	assert.Equal(t, nil, err)
+	events, err = links.History(ctx, l.ID)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 4, len(events))
+	assert.Equal(t, link.EventPurged, events[3].Type)
+	assert.Equal(t, "hist", events[3].OldShortName)
+	_, err = links.History(ctx, l.ID+1)
+	assert.Equal(t, link.ErrNotFound, err)
+}
+
+func TestLinkVisitRepositoryCreateWithinLimit(t *testing.T) {
+	ctx := context.Background()
+	store := NewStore()
+	links := New(store)
+	visits := NewLinkVisitRepository(store)
+
+	maxVisits := 2
+	l, err := links.Create(ctx, link.CreateInput{OriginalURL: "https://example.com", ShortName: "twice", MaxVisits: &maxVisits})
+	assert.Equal(t, nil, err)
+
+	for i := 0; i < maxVisits; i++ {
+		_, err = visits.CreateWithinLimit(ctx, linkvisit.CreateInput{LinkID: l.ID, Status: 302})
+		assert.Equal(t, nil, err)
+	}
+
+	_, err = visits.CreateWithinLimit(ctx, linkvisit.CreateInput{LinkID: l.ID, Status: 302})
+	assert.Equal(t, linkvisit.ErrLimitReached, err)
+}
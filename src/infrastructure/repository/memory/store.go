@@ -0,0 +1,41 @@
+package memory
+
+import (
+	"sync"
+	"time"
+
+	"link-service/src/domain/entity"
+)
+
+/*Общее хранилище в памяти для ссылок, посещений и истории (нужно для каскадного удаления)*/
+type Store struct {
+	mu sync.RWMutex
+
+	links       map[int64]entity.Link /*Ссылки по идентификатору*/
+	shortNames  map[string]int64      /*Индекс short_name -> идентификатор*/
+	visits      []entity.LinkVisit    /*Посещения в порядке идентификаторов*/
+	events      []entity.LinkEvent    /*История изменений ссылок в порядке идентификаторов*/
+	daily       map[dailyKey]int64    /*Дневные счётчики посещений*/
+	rolledUntil time.Time             /*Граница дневной агрегации*/
+	nextLinkID  int64
+	nextVisitID int64
+	nextEventID int64
+}
+
+/*Ключ дневного счётчика посещений*/
+type dailyKey struct {
+	linkID      int64
+	day         int64 /*Начало суток, Unix*/
+	status      int
+	isBot       bool
+	refererHost string
+}
+
+/*Метод создания нового хранилища*/
+func NewStore() *Store {
+	return &Store{
+		links:      make(map[int64]entity.Link),
+		shortNames: make(map[string]int64),
+		daily:      make(map[dailyKey]int64),
+	}
+}
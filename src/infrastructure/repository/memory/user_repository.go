@@ -0,0 +1,138 @@
+package memory
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"link-service/src/domain/entity"
+	domain "link-service/src/domain/user"
+)
+
+/*Репозиторий пользователей в памяти*/
+type UserRepository struct {
+	mu       sync.RWMutex
+	users    []entity.User      /*Пользователи в порядке идентификаторов*/
+	sessions map[string]session /*Сессии по SHA-256 токена*/
+	nextID   int64
+}
+
+/*Сессия пользователя*/
+type session struct {
+	userID    int64
+	expiresAt time.Time
+}
+
+/*Метод создания нового репозитория пользователей*/
+func NewUserRepository() *UserRepository {
+	return &UserRepository{sessions: make(map[string]session)}
+}
+
+/*Создание пользователя*/
+func (r *UserRepository) Create(ctx context.Context, in domain.CreateInput) (entity.User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, u := range r.users {
+		if u.Username == in.Username {
+			return entity.User{}, domain.ErrUsernameConflict
+		}
+	}
+
+	r.nextID++
+	u := entity.User{
+		ID:           r.nextID,
+		Username:     in.Username,
+		PasswordHash: in.PasswordHash,
+		Role:         in.Role,
+		CreatedAt:    time.Now().UTC(),
+	}
+	r.users = append(r.users, u)
+
+	return u, nil
+}
+
+/*Получение пользователя по идентификатору*/
+func (r *UserRepository) Get(ctx context.Context, id int64) (entity.User, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.find(func(u entity.User) bool { return u.ID == id })
+}
+
+/*Получение пользователя по имени для входа*/
+func (r *UserRepository) GetByUsername(ctx context.Context, username string) (entity.User, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.find(func(u entity.User) bool { return u.Username == username })
+}
+
+/*Список всех пользователей*/
+func (r *UserRepository) List(ctx context.Context) ([]entity.User, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return append([]entity.User{}, r.users...), nil
+}
+
+/*Создание сессии пользователя*/
+func (r *UserRepository) CreateSession(ctx context.Context, userID int64, tokenHash string, expiresAt time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.sessions[tokenHash] = session{userID: userID, expiresAt: expiresAt}
+	return nil
+}
+
+/*Получение пользователя по действующей сессии*/
+func (r *UserRepository) GetBySession(ctx context.Context, tokenHash string) (entity.User, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	s, ok := r.sessions[tokenHash]
+	if !ok || !time.Now().Before(s.expiresAt) {
+		return entity.User{}, domain.ErrNotFound
+	}
+
+	return r.find(func(u entity.User) bool { return u.ID == s.userID })
+}
+
+/*Удаление сессии*/
+func (r *UserRepository) DeleteSession(ctx context.Context, tokenHash string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.sessions, tokenHash)
+	return nil
+}
+
+/*Удаление истёкших сессий*/
+func (r *UserRepository) DeleteExpiredSessions(ctx context.Context) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var n int64
+	now := time.Now()
+	for hash, s := range r.sessions {
+		if !now.Before(s.expiresAt) {
+			delete(r.sessions, hash)
+			n++
+		}
+	}
+
+	return n, nil
+}
+
+/*Метод поиска пользователя (вызывается под mu)*/
+func (r *UserRepository) find(match func(entity.User) bool) (entity.User, error) {
+	for _, u := range r.users {
+		if match(u) {
+			return u, nil
+		}
+	}
+
+	return entity.User{}, domain.ErrNotFound
+}
+
+var _ domain.Repository = (*UserRepository)(nil)
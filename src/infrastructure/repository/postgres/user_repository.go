@@ -0,0 +1,113 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"link-service/src/domain/entity"
+	domain "link-service/src/domain/user"
+	"link-service/src/infrastructure/database/sqlcdb"
+)
+
+/*Репозиторий пользователей для PostgreSQL*/
+type UserRepository struct {
+	q *sqlcdb.Queries
+}
+
+/*Метод создания нового репозитория пользователей*/
+func NewUserRepository(db *sql.DB) *UserRepository {
+	return &UserRepository{q: sqlcdb.New(db)}
+}
+
+/*Создание пользователя*/
+func (r *UserRepository) Create(ctx context.Context, in domain.CreateInput) (entity.User, error) {
+	row, err := r.q.CreateUser(ctx, sqlcdb.CreateUserParams{
+		Username:     in.Username,
+		PasswordHash: in.PasswordHash,
+		Role:         in.Role,
+	})
+	if err != nil {
+		if isUniqueViolation(err) {
+			return entity.User{}, domain.ErrUsernameConflict
+		}
+		return entity.User{}, err
+	}
+
+	return fromSQLCUser(row), nil
+}
+
+/*Получение пользователя по идентификатору*/
+func (r *UserRepository) Get(ctx context.Context, id int64) (entity.User, error) {
+	return userOrNotFound(r.q.GetUser(ctx, id))
+}
+
+/*Получение пользователя по имени для входа*/
+func (r *UserRepository) GetByUsername(ctx context.Context, username string) (entity.User, error) {
+	return userOrNotFound(r.q.GetUserByUsername(ctx, username))
+}
+
+/*Список всех пользователей*/
+func (r *UserRepository) List(ctx context.Context) ([]entity.User, error) {
+	rows, err := r.q.ListUsers(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]entity.User, 0, len(rows))
+	for _, row := range rows {
+		res = append(res, fromSQLCUser(row))
+	}
+
+	return res, nil
+}
+
+/*Создание сессии пользователя*/
+func (r *UserRepository) CreateSession(ctx context.Context, userID int64, tokenHash string, expiresAt time.Time) error {
+	return r.q.CreateUserSession(ctx, sqlcdb.CreateUserSessionParams{
+		UserID:    userID,
+		TokenHash: tokenHash,
+		ExpiresAt: expiresAt.UTC(),
+	})
+}
+
+/*Получение пользователя по действующей сессии*/
+func (r *UserRepository) GetBySession(ctx context.Context, tokenHash string) (entity.User, error) {
+	return userOrNotFound(r.q.GetUserBySession(ctx, tokenHash))
+}
+
+/*Удаление сессии*/
+func (r *UserRepository) DeleteSession(ctx context.Context, tokenHash string) error {
+	return r.q.DeleteUserSession(ctx, tokenHash)
+}
+
+/*Удаление истёкших сессий*/
+func (r *UserRepository) DeleteExpiredSessions(ctx context.Context) (int64, error) {
+	return r.q.DeleteExpiredUserSessions(ctx)
+}
+
+/*Метод преобразования результата запроса одного пользователя (sql.ErrNoRows — ErrNotFound)*/
+func userOrNotFound(row sqlcdb.User, err error) (entity.User, error) {
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return entity.User{}, domain.ErrNotFound
+		}
+		return entity.User{}, err
+	}
+
+	return fromSQLCUser(row), nil
+}
+
+/*Метод преобразования из sqlcdb.User в entity.User*/
+func fromSQLCUser(u sqlcdb.User) entity.User {
+	return entity.User{
+		ID:           u.ID,
+		Username:     u.Username,
+		PasswordHash: u.PasswordHash,
+		Role:         u.Role,
+		CreatedAt:    u.CreatedAt,
+	}
+}
+
+var _ domain.Repository = (*UserRepository)(nil)
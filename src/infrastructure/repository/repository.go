@@ -0,0 +1,64 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+
+	"link-service/src/domain/apikey"
+	"link-service/src/domain/link"
+	"link-service/src/domain/linkvisit"
+	"link-service/src/domain/user"
+	"link-service/src/infrastructure/database"
+	"link-service/src/infrastructure/repository/memory"
+	"link-service/src/infrastructure/repository/postgres"
+	"link-service/src/infrastructure/repository/sqlite"
+)
+
+/*Набор репозиториев выбранного хранилища*/
+type Set struct {
+	Link      link.Repository      /*Репозиторий ссылок*/
+	LinkVisit linkvisit.Repository /*Репозиторий посещений*/
+	APIKey    apikey.Repository    /*Репозиторий API ключей*/
+	User      user.Repository      /*Репозиторий пользователей и сессий*/
+}
+
+/*Метод создания репозиториев для подключённого хранилища*/
+func New(db *database.DatabaseImpl) (*Set, error) {
+	switch db.Driver() {
+	case database.DriverMemory:
+		store := memory.NewStore()
+
+		return &Set{
+			Link:      memory.New(store),
+			LinkVisit: memory.NewLinkVisitRepository(store),
+			APIKey:    memory.NewAPIKeyRepository(),
+			User:      memory.NewUserRepository(),
+		}, nil
+	case database.DriverPostgres:
+		sqlDB, ok := db.GetInstance().(*sql.DB)
+		if !ok || sqlDB == nil {
+			return nil, fmt.Errorf("invalid database instance")
+		}
+
+		return &Set{
+			Link:      postgres.New(sqlDB),
+			LinkVisit: postgres.NewLinkVisitRepository(sqlDB),
+			APIKey:    postgres.NewAPIKeyRepository(sqlDB),
+			User:      postgres.NewUserRepository(sqlDB),
+		}, nil
+	case database.DriverSqlite:
+		sqlDB, ok := db.GetInstance().(*sql.DB)
+		if !ok || sqlDB == nil {
+			return nil, fmt.Errorf("invalid database instance")
+		}
+
+		return &Set{
+			Link:      sqlite.New(sqlDB),
+			LinkVisit: sqlite.NewLinkVisitRepository(sqlDB),
+			APIKey:    sqlite.NewAPIKeyRepository(sqlDB),
+			User:      sqlite.NewUserRepository(sqlDB),
+		}, nil
+	default:
+		return nil, fmt.Errorf("unsupported database driver %q", db.Driver())
+	}
+}
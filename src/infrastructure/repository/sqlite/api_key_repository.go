@@ -0,0 +1,100 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	domain "link-service/src/domain/apikey"
+	"link-service/src/domain/entity"
+	"link-service/src/infrastructure/database/sqlitedb"
+)
+
+/*Репозиторий API ключей для SQLite*/
+type APIKeyRepository struct {
+	q *sqlitedb.Queries
+}
+
+/*Метод создания нового репозитория API ключей*/
+func NewAPIKeyRepository(db *sql.DB) *APIKeyRepository {
+	return &APIKeyRepository{q: sqlitedb.New(db)}
+}
+
+/*Создание ключа*/
+func (r *APIKeyRepository) Create(ctx context.Context, in domain.CreateInput) (entity.APIKey, error) {
+	row, err := r.q.CreateApiKey(ctx, sqlitedb.CreateApiKeyParams{
+		Name:    in.Name,
+		Prefix:  in.Prefix,
+		KeyHash: in.KeyHash,
+		Scopes:  domain.FormatScopes(in.Scopes),
+		OwnerID: sql.NullInt64{Int64: in.OwnerID, Valid: true},
+	})
+	if err != nil {
+		return entity.APIKey{}, err
+	}
+
+	return fromSQLCAPIKey(row), nil
+}
+
+/*Получение действующего ключа по SHA-256*/
+func (r *APIKeyRepository) GetByHash(ctx context.Context, keyHash string) (entity.APIKey, error) {
+	row, err := r.q.GetApiKeyByHash(ctx, keyHash)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return entity.APIKey{}, domain.ErrNotFound
+		}
+		return entity.APIKey{}, err
+	}
+
+	return fromSQLCAPIKey(row), nil
+}
+
+/*Список всех ключей*/
+func (r *APIKeyRepository) List(ctx context.Context) ([]entity.APIKey, error) {
+	rows, err := r.q.ListApiKeys(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]entity.APIKey, 0, len(rows))
+	for _, row := range rows {
+		res = append(res, fromSQLCAPIKey(row))
+	}
+
+	return res, nil
+}
+
+/*Отзыв ключа*/
+func (r *APIKeyRepository) Revoke(ctx context.Context, id int64) error {
+	_, err := r.q.RevokeApiKey(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.ErrNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+/*Обновление даты последнего использования*/
+func (r *APIKeyRepository) Touch(ctx context.Context, id int64, usedAt time.Time) error {
+	return r.q.TouchApiKey(ctx, sqlitedb.TouchApiKeyParams{UsedAt: usedAt.UTC(), ID: id})
+}
+
+/*Метод преобразования из sqlitedb.ApiKey в entity.APIKey*/
+func fromSQLCAPIKey(k sqlitedb.ApiKey) entity.APIKey {
+	return entity.APIKey{
+		ID:         k.ID,
+		Name:       k.Name,
+		Prefix:     k.Prefix,
+		KeyHash:    k.KeyHash,
+		Scopes:     domain.ParseScopes(k.Scopes),
+		CreatedAt:  k.CreatedAt,
+		LastUsedAt: fromNullTime(k.LastUsedAt),
+		RevokedAt:  fromNullTime(k.RevokedAt),
+		OwnerID:    fromNullID(k.OwnerID),
+	}
+}
+
+var _ domain.Repository = (*APIKeyRepository)(nil)
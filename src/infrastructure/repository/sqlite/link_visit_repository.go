@@ -0,0 +1,475 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
+
+	"link-service/src/domain/entity"
+	"link-service/src/domain/link"
+	domain "link-service/src/domain/linkvisit"
+	"link-service/src/infrastructure/database/sqlitedb"
+	"link-service/src/infrastructure/repository/sqlquery"
+)
+
+const (
+	/*Количество колонок в INSERT посещения*/
+	visitInsertColumns = 13
+	/*Формат начала интервала в LinkVisitSeries*/
+	bucketLayout = "2006-01-02 15:04:05"
+	/*Максимум строк в одном INSERT (лимит SQLite — 32766 параметров)*/
+	maxBatchRows = 1000
+)
+
+/*Репозиторий посещений ссылок для SQLite*/
+type LinkVisitRepository struct {
+	db *sql.DB
+	q  *sqlitedb.Queries
+}
+
+/*Метод создания нового репозитория посещений*/
+func NewLinkVisitRepository(db *sql.DB) *LinkVisitRepository {
+	return &LinkVisitRepository{db: db, q: sqlitedb.New(db)}
+}
+
+/*Создание записи посещения*/
+func (r *LinkVisitRepository) Create(ctx context.Context, in domain.CreateInput) (entity.LinkVisit, error) {
+	row, err := r.q.CreateLinkVisit(ctx, createParams(in))
+	if err != nil {
+		return entity.LinkVisit{}, err
+	}
+	return fromSQLCVisit(row), nil
+}
+
+/*Атомарное создание записи посещения с учётом лимита посещений ссылки*/
+func (r *LinkVisitRepository) CreateWithinLimit(ctx context.Context, in domain.CreateInput) (entity.LinkVisit, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return entity.LinkVisit{}, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	qtx := r.q.WithTx(tx)
+
+	/*транзакция берёт блокировку записи сразу (_txlock=immediate), поэтому параллельные запросы не превысят лимит*/
+	if _, err := qtx.ConsumeLinkVisit(ctx, in.LinkID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return entity.LinkVisit{}, domain.ErrLimitReached
+		}
+		return entity.LinkVisit{}, err
+	}
+
+	row, err := qtx.CreateLinkVisit(ctx, createParams(in))
+	if err != nil {
+		return entity.LinkVisit{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return entity.LinkVisit{}, err
+	}
+
+	return fromSQLCVisit(row), nil
+}
+
+/*Пакетное создание записей посещений многострочным INSERT в одной транзакции (ошибка — не записано ничего, пачку можно повторить частями)*/
+func (r *LinkVisitRepository) CreateBatch(ctx context.Context, in []domain.CreateInput) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	for start := 0; start < len(in); start += maxBatchRows {
+		end := min(start+maxBatchRows, len(in))
+		if err := insertBatch(ctx, tx, in[start:end]); err != nil {
+			if isRowViolation(err) {
+				return fmt.Errorf("%w: %v", domain.ErrInvalidRow, err)
+			}
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+/*Метод проверки, что запрос отклонён из-за данных строки (нарушение ограничения), а не из-за хранилища*/
+func isRowViolation(err error) bool {
+	var sqliteErr *sqlite.Error
+
+	if errors.As(err, &sqliteErr) {
+		return sqliteErr.Code()&0xff == sqlite3.SQLITE_CONSTRAINT
+	}
+
+	return false
+}
+
+/*Вставка пачки посещений одним запросом (sqlc не генерирует VALUES переменной длины)*/
+func insertBatch(ctx context.Context, tx *sql.Tx, in []domain.CreateInput) error {
+	if len(in) == 0 {
+		return nil
+	}
+
+	var sb strings.Builder
+	args := make([]any, 0, len(in)*visitInsertColumns)
+
+	sb.WriteString("INSERT INTO link_visits (link_id, ip, user_agent, referer, status, browser, browser_version, os, device, is_bot, country, region, city) VALUES ")
+	for i, v := range in {
+		if i > 0 {
+			sb.WriteString(", ")
+		}
+
+		sb.WriteString("(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)")
+
+		a, g := v.Agent, v.Geo
+		args = append(args, v.LinkID, v.IP, v.UserAgent, v.Referer, int64(v.Status), a.Browser, a.BrowserVersion, a.OS, string(a.Device), a.IsBot, g.Country, g.Region, g.City)
+	}
+
+	_, err := tx.ExecContext(ctx, sb.String(), args...)
+	return err
+}
+
+/*Список посещений с range*/
+func (r *LinkVisitRepository) ListWithRange(ctx context.Context, rng *link.Range, f domain.Filter) ([]entity.LinkVisit, error) {
+	p := filterParams(f)
+	rows, err := r.q.ListLinkVisitsWithRange(ctx, sqlitedb.ListLinkVisitsWithRangeParams{
+		LinkID:    p.LinkID,
+		FromTime:  p.FromTime,
+		ToTime:    p.ToTime,
+		Status:    p.Status,
+		Ip:        p.Ip,
+		Referer:   p.Referer,
+		Browser:   p.Browser,
+		Os:        p.Os,
+		Device:    p.Device,
+		IsBot:     p.IsBot,
+		OwnerID:   p.OwnerID,
+		RowLimit:  int64(rng.End - rng.Start + 1),
+		RowOffset: int64(rng.Start),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]entity.LinkVisit, 0, len(rows))
+	for _, row := range rows {
+		res = append(res, fromSQLCVisit(row))
+	}
+	return res, nil
+}
+
+/*Список посещений после курсора*/
+func (r *LinkVisitRepository) ListAfter(ctx context.Context, cur *link.Cursor, f domain.Filter) ([]entity.LinkVisit, error) {
+	p := filterParams(f)
+	rows, err := r.q.ListLinkVisitsAfter(ctx, sqlitedb.ListLinkVisitsAfterParams{
+		LinkID:   p.LinkID,
+		FromTime: p.FromTime,
+		ToTime:   p.ToTime,
+		Status:   p.Status,
+		Ip:       p.Ip,
+		Referer:  p.Referer,
+		Browser:  p.Browser,
+		Os:       p.Os,
+		Device:   p.Device,
+		IsBot:    p.IsBot,
+		OwnerID:  p.OwnerID,
+		AfterID:  cur.AfterID,
+		RowLimit: int64(cur.Limit),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]entity.LinkVisit, 0, len(rows))
+	for _, row := range rows {
+		res = append(res, fromSQLCVisit(row))
+	}
+	return res, nil
+}
+
+/*Обход посещений по фильтру в порядке id построчно из курсора (без загрузки всего списка в память)*/
+func (r *LinkVisitRepository) Each(ctx context.Context, f domain.Filter, fn func(entity.LinkVisit) error) error {
+	query, args := sqlquery.SelectLinkVisits(sqlquery.SQLite, f)
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var v sqlitedb.LinkVisit
+		if err := rows.Scan(
+			&v.ID,
+			&v.LinkID,
+			&v.Ip,
+			&v.UserAgent,
+			&v.Referer,
+			&v.Status,
+			&v.CreatedAt,
+			&v.Browser,
+			&v.BrowserVersion,
+			&v.Os,
+			&v.Device,
+			&v.IsBot,
+			&v.Country,
+			&v.Region,
+			&v.City,
+		); err != nil {
+			return err
+		}
+		if err := fn(fromSQLCVisit(v)); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
+
+/*Количество посещений, подходящих под фильтр*/
+func (r *LinkVisitRepository) Count(ctx context.Context, f domain.Filter) (int64, error) {
+	return r.q.CountLinkVisits(ctx, filterParams(f))
+}
+
+/*Количество посещений, созданных раньше before*/
+func (r *LinkVisitRepository) CountBefore(ctx context.Context, before time.Time) (int64, error) {
+	return r.q.CountLinkVisitsBefore(ctx, before.UTC())
+}
+
+/*Удаление не более limit самых старых посещений, созданных раньше before*/
+func (r *LinkVisitRepository) DeleteBefore(ctx context.Context, before time.Time, limit int) (int64, error) {
+	return r.q.DeleteLinkVisitsBefore(ctx, sqlitedb.DeleteLinkVisitsBeforeParams{
+		Before:   before.UTC(),
+		RowLimit: int64(limit),
+	})
+}
+
+/*Статистика посещений ссылки за период*/
+func (r *LinkVisitRepository) Stats(ctx context.Context, q domain.StatsQuery) (domain.Stats, error) {
+	var res domain.Stats
+
+	total, err := r.q.CountLinkVisitsInRange(ctx, sqlitedb.CountLinkVisitsInRangeParams{
+		LinkID:   q.LinkID,
+		FromTime: q.From.UTC(),
+		ToTime:   q.To.UTC(),
+	})
+	if err != nil {
+		return domain.Stats{}, err
+	}
+	res.Total, res.Bots = total.Humans, total.Bots
+	if q.IncludeBots {
+		res.Total += total.Bots
+	}
+
+	series, err := r.q.LinkVisitSeries(ctx, sqlitedb.LinkVisitSeriesParams{
+		Interval:    string(q.Interval),
+		LinkID:      q.LinkID,
+		FromTime:    q.From.UTC(),
+		ToTime:      q.To.UTC(),
+		IncludeBots: q.IncludeBots,
+	})
+	if err != nil {
+		return domain.Stats{}, err
+	}
+	for _, row := range series {
+		start, err := time.ParseInLocation(bucketLayout, row.Bucket, time.UTC)
+		if err != nil {
+			return domain.Stats{}, err
+		}
+		res.Series = append(res.Series, domain.Bucket{Start: start, Clicks: row.Clicks})
+	}
+
+	referers, err := r.q.TopLinkVisitReferers(ctx, sqlitedb.TopLinkVisitReferersParams{
+		LinkID:      q.LinkID,
+		FromTime:    q.From.UTC(),
+		ToTime:      q.To.UTC(),
+		IncludeBots: q.IncludeBots,
+		TopLimit:    int64(q.Top),
+	})
+	if err != nil {
+		return domain.Stats{}, err
+	}
+	for _, row := range referers {
+		res.TopReferers = append(res.TopReferers, domain.ValueCount{Value: row.Referer, Count: row.Count})
+	}
+
+	userAgents, err := r.q.TopLinkVisitUserAgents(ctx, sqlitedb.TopLinkVisitUserAgentsParams{
+		LinkID:      q.LinkID,
+		FromTime:    q.From.UTC(),
+		ToTime:      q.To.UTC(),
+		IncludeBots: q.IncludeBots,
+		TopLimit:    int64(q.Top),
+	})
+	if err != nil {
+		return domain.Stats{}, err
+	}
+	for _, row := range userAgents {
+		res.TopUserAgents = append(res.TopUserAgents, domain.ValueCount{Value: row.UserAgent, Count: row.Count})
+	}
+
+	statuses, err := r.q.LinkVisitStatuses(ctx, sqlitedb.LinkVisitStatusesParams{
+		LinkID:      q.LinkID,
+		FromTime:    q.From.UTC(),
+		ToTime:      q.To.UTC(),
+		IncludeBots: q.IncludeBots,
+	})
+	if err != nil {
+		return domain.Stats{}, err
+	}
+	for _, row := range statuses {
+		res.Statuses = append(res.Statuses, domain.StatusCount{Status: int(row.Status), Count: row.Count})
+	}
+
+	browsers, err := r.q.TopLinkVisitBrowsers(ctx, sqlitedb.TopLinkVisitBrowsersParams{
+		LinkID:      q.LinkID,
+		FromTime:    q.From.UTC(),
+		ToTime:      q.To.UTC(),
+		IncludeBots: q.IncludeBots,
+		TopLimit:    int64(q.Top),
+	})
+	if err != nil {
+		return domain.Stats{}, err
+	}
+	for _, row := range browsers {
+		res.Browsers = append(res.Browsers, domain.ValueCount{Value: row.Browser, Count: row.Count})
+	}
+
+	systems, err := r.q.TopLinkVisitOperatingSystems(ctx, sqlitedb.TopLinkVisitOperatingSystemsParams{
+		LinkID:      q.LinkID,
+		FromTime:    q.From.UTC(),
+		ToTime:      q.To.UTC(),
+		IncludeBots: q.IncludeBots,
+		TopLimit:    int64(q.Top),
+	})
+	if err != nil {
+		return domain.Stats{}, err
+	}
+	for _, row := range systems {
+		res.OperatingSystems = append(res.OperatingSystems, domain.ValueCount{Value: row.Os, Count: row.Count})
+	}
+
+	devices, err := r.q.LinkVisitDevices(ctx, sqlitedb.LinkVisitDevicesParams{
+		LinkID:      q.LinkID,
+		FromTime:    q.From.UTC(),
+		ToTime:      q.To.UTC(),
+		IncludeBots: q.IncludeBots,
+	})
+	if err != nil {
+		return domain.Stats{}, err
+	}
+	for _, row := range devices {
+		res.Devices = append(res.Devices, domain.ValueCount{Value: row.Device, Count: row.Count})
+	}
+
+	countries, err := r.q.LinkVisitCountries(ctx, sqlitedb.LinkVisitCountriesParams{
+		LinkID:      q.LinkID,
+		FromTime:    q.From.UTC(),
+		ToTime:      q.To.UTC(),
+		IncludeBots: q.IncludeBots,
+	})
+	if err != nil {
+		return domain.Stats{}, err
+	}
+	for _, row := range countries {
+		res.Countries = append(res.Countries, domain.ValueCount{Value: row.Country, Count: row.Count})
+	}
+
+	// хост выделяется в Go: в SQLite нет разбора URL, а результат должен совпадать с дневной агрегацией
+	groups, err := r.CountGroups(ctx, domain.GroupQuery{LinkID: q.LinkID, From: q.From, To: q.To})
+	if err != nil {
+		return domain.Stats{}, err
+	}
+	res.RefererHosts = domain.RefererHosts(groups, q.IncludeBots)
+
+	return res, nil
+}
+
+/*Параметры фильтра посещений (общие для списков и количества)*/
+func filterParams(f domain.Filter) sqlitedb.CountLinkVisitsParams {
+	return sqlitedb.CountLinkVisitsParams{
+		LinkID:   sql.NullInt64{Int64: f.LinkID, Valid: f.LinkID != 0},
+		FromTime: timeArg(f.From),
+		ToTime:   timeArg(f.To),
+		Status:   sql.NullInt64{Int64: int64(f.Status), Valid: f.Status != 0},
+		Ip:       likeArg(f.IP),
+		Referer:  likeArg(f.Referer),
+		Browser:  sql.NullString{String: f.Browser, Valid: f.Browser != ""},
+		Os:       sql.NullString{String: f.OS, Valid: f.OS != ""},
+		Device:   sql.NullString{String: string(f.Device), Valid: f.Device != ""},
+		IsBot:    toNullBool(f.IsBot),
+		OwnerID:  sql.NullInt64{Int64: f.OwnerID, Valid: f.OwnerID != 0},
+	}
+}
+
+/*Метод преобразования *bool в sql.NullBool*/
+func toNullBool(b *bool) sql.NullBool {
+	if b == nil {
+		return sql.NullBool{}
+	}
+
+	return sql.NullBool{Bool: *b, Valid: true}
+}
+
+/*Параметры INSERT посещения*/
+func createParams(in domain.CreateInput) sqlitedb.CreateLinkVisitParams {
+	return sqlitedb.CreateLinkVisitParams{
+		LinkID:         in.LinkID,
+		Ip:             in.IP,
+		UserAgent:      in.UserAgent,
+		Referer:        in.Referer,
+		Status:         int64(in.Status),
+		Browser:        in.Agent.Browser,
+		BrowserVersion: in.Agent.BrowserVersion,
+		Os:             in.Agent.OS,
+		Device:         string(in.Agent.Device),
+		IsBot:          in.Agent.IsBot,
+		Country:        in.Geo.Country,
+		Region:         in.Geo.Region,
+		City:           in.Geo.City,
+	}
+}
+
+/*Метод преобразования подстроки поиска в параметр LIKE (пустая строка — NULL)*/
+func likeArg(s string) sql.NullString {
+	if s == "" {
+		return sql.NullString{}
+	}
+
+	return sql.NullString{String: sqlquery.EscapeLike(s), Valid: true}
+}
+
+/*Метод преобразования *time.Time в параметр запроса (nil — NULL)*/
+func timeArg(t *time.Time) any {
+	if t == nil {
+		return nil
+	}
+
+	return t.UTC()
+}
+
+func fromSQLCVisit(v sqlitedb.LinkVisit) entity.LinkVisit {
+	return entity.LinkVisit{
+		ID:             v.ID,
+		LinkID:         v.LinkID,
+		IP:             v.Ip,
+		UserAgent:      v.UserAgent,
+		Referer:        v.Referer,
+		Status:         int(v.Status),
+		CreatedAt:      v.CreatedAt,
+		Browser:        v.Browser,
+		BrowserVersion: v.BrowserVersion,
+		OS:             v.Os,
+		Device:         v.Device,
+		IsBot:          v.IsBot,
+		Country:        v.Country,
+		Region:         v.Region,
+		City:           v.City,
+	}
+}
+
+var _ domain.Repository = (*LinkVisitRepository)(nil)
@@ -0,0 +1,113 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	domain "link-service/src/domain/linkvisit"
+	"link-service/src/infrastructure/database/sqlitedb"
+)
+
+/*Количество посещений за период, сгруппированное по ссылке, статусу, признаку бота и рефереру*/
+func (r *LinkVisitRepository) CountGroups(ctx context.Context, q domain.GroupQuery) ([]domain.VisitGroup, error) {
+	rows, err := r.q.CountLinkVisitGroups(ctx, sqlitedb.CountLinkVisitGroupsParams{
+		LinkID:   sql.NullInt64{Int64: q.LinkID, Valid: q.LinkID != 0},
+		FromTime: q.From.UTC(),
+		ToTime:   q.To.UTC(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]domain.VisitGroup, 0, len(rows))
+	for _, row := range rows {
+		res = append(res, domain.VisitGroup{
+			LinkID:  row.LinkID,
+			Status:  int(row.Status),
+			IsBot:   row.IsBot,
+			Referer: row.Referer,
+			Count:   row.Count,
+		})
+	}
+	return res, nil
+}
+
+/*Сохранение дневных счётчиков и сдвиг границы агрегации в одной транзакции*/
+func (r *LinkVisitRepository) SaveDaily(ctx context.Context, day time.Time, counts []domain.DailyCount) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	qtx := r.q.WithTx(tx)
+
+	for _, c := range counts {
+		if err := qtx.UpsertLinkVisitDaily(ctx, sqlitedb.UpsertLinkVisitDailyParams{
+			LinkID:      c.LinkID,
+			Day:         day,
+			Status:      int64(c.Status),
+			IsBot:       c.IsBot,
+			RefererHost: c.RefererHost,
+			Visits:      c.Visits,
+		}); err != nil {
+			return err
+		}
+	}
+
+	if err := qtx.SetLinkVisitRolledUntil(ctx, day.AddDate(0, 0, 1)); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+/*Дневные счётчики ссылки за дни [from, to)*/
+func (r *LinkVisitRepository) ListDaily(ctx context.Context, linkID int64, from, to time.Time) ([]domain.DailyCount, error) {
+	rows, err := r.q.ListLinkVisitDaily(ctx, sqlitedb.ListLinkVisitDailyParams{
+		LinkID:  linkID,
+		FromDay: from,
+		ToDay:   to,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]domain.DailyCount, 0, len(rows))
+	for _, row := range rows {
+		res = append(res, domain.DailyCount{
+			LinkID:      row.LinkID,
+			Day:         domain.IntervalDay.Truncate(row.Day),
+			Status:      int(row.Status),
+			IsBot:       row.IsBot,
+			RefererHost: row.RefererHost,
+			Visits:      row.Visits,
+		})
+	}
+	return res, nil
+}
+
+/*Состояние дневной агрегации*/
+func (r *LinkVisitRepository) RollupState(ctx context.Context) (domain.RollupState, error) {
+	var res domain.RollupState
+
+	rolledUntil, err := r.q.GetLinkVisitRolledUntil(ctx)
+	switch {
+	case err == nil:
+		res.RolledUntil = domain.IntervalDay.Truncate(rolledUntil)
+	case !errors.Is(err, sql.ErrNoRows):
+		return domain.RollupState{}, err
+	}
+
+	firstVisitAt, err := r.q.FirstLinkVisitCreatedAt(ctx)
+	switch {
+	case err == nil:
+		res.FirstVisitAt = firstVisitAt.UTC()
+	case !errors.Is(err, sql.ErrNoRows):
+		return domain.RollupState{}, err
+	}
+
+	return res, nil
+}
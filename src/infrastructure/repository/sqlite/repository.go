@@ -0,0 +1,533 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
+
+	"link-service/src/domain/entity"
+	domain "link-service/src/domain/link"
+	"link-service/src/infrastructure/database/sqlitedb"
+	"link-service/src/infrastructure/repository/sqlquery"
+)
+
+/*Репозиторий для работы с SQLite*/
+type Repository struct {
+	db *sql.DB           /*Соединение для динамических запросов (фильтр и сортировка)*/
+	q  *sqlitedb.Queries /*Queries для работы с базой данных*/
+}
+
+/*Метод создания нового репозитория*/
+func New(db *sql.DB) *Repository {
+	return &Repository{db: db, q: sqlitedb.New(db)}
+}
+
+/*Метод получения списка ссылок (читается тем же курсором, что и выгрузка)*/
+func (r *Repository) List(ctx context.Context, q domain.Query) ([]entity.Link, error) {
+	res := make([]entity.Link, 0)
+	err := r.Each(ctx, q, func(l entity.Link) error {
+		res = append(res, l)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+/*Метод получения списка ссылок*/
+func (r *Repository) ListWithRange(ctx context.Context, rng *domain.Range, q domain.Query) ([]entity.Link, error) {
+	if !q.IsEmpty() {
+		query, args := sqlquery.SelectLinks(sqlquery.SQLite, q, rng)
+		return r.queryLinks(ctx, query, args)
+	}
+
+	rows, err := r.q.ListLinksWithRange(ctx, sqlitedb.ListLinksWithRangeParams{
+		Limit:  int64(rng.End - rng.Start + 1),
+		Offset: int64(rng.Start),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]entity.Link, 0, len(rows))
+	for _, row := range rows {
+		res = append(res, fromSQLC(row))
+	}
+
+	return res, nil
+}
+
+/*Метод получения списка ссылок после курсора*/
+func (r *Repository) ListAfter(ctx context.Context, cur *domain.Cursor, f domain.Filter) ([]entity.Link, error) {
+	if !f.IsEmpty() {
+		query, args := sqlquery.SelectLinksAfter(sqlquery.SQLite, f, cur)
+		return r.queryLinks(ctx, query, args)
+	}
+
+	rows, err := r.q.ListLinksAfter(ctx, sqlitedb.ListLinksAfterParams{
+		ID:    cur.AfterID,
+		Limit: int64(cur.Limit),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]entity.Link, 0, len(rows))
+	for _, row := range rows {
+		res = append(res, fromSQLC(row))
+	}
+
+	return res, nil
+}
+
+/*Метод получения количества ссылок, подходящих под фильтр*/
+func (r *Repository) Count(ctx context.Context, f domain.Filter) (int64, error) {
+	if f.IsEmpty() {
+		return r.q.CountLinks(ctx)
+	}
+
+	query, args := sqlquery.CountLinks(sqlquery.SQLite, f)
+
+	var count int64
+	err := r.db.QueryRowContext(ctx, query, args...).Scan(&count)
+	return count, err
+}
+
+/*Метод обхода ссылок с фильтром и сортировкой без загрузки всего списка в память*/
+func (r *Repository) Each(ctx context.Context, q domain.Query, fn func(entity.Link) error) error {
+	query, args := sqlquery.SelectLinks(sqlquery.SQLite, q, nil)
+	return r.eachLink(ctx, query, args, fn)
+}
+
+/*Метод получения ссылки по идентификатору*/
+func (r *Repository) Get(ctx context.Context, id int64) (entity.Link, error) {
+	row, err := r.q.GetLink(ctx, id)
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return entity.Link{}, domain.ErrNotFound
+		}
+		return entity.Link{}, err
+	}
+
+	return fromSQLC(row), nil
+}
+
+/*Метод получения ссылки по short_name*/
+func (r *Repository) GetByShortName(ctx context.Context, shortName string) (entity.Link, error) {
+	row, err := r.q.GetLinkByShortName(ctx, shortName)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return entity.Link{}, domain.ErrNotFound
+		}
+		return entity.Link{}, err
+	}
+
+	return fromSQLC(row), nil
+}
+
+/*Метод создания новой ссылки*/
+func (r *Repository) Create(ctx context.Context, in domain.CreateInput) (entity.Link, error) {
+	var res entity.Link
+	err := r.inTx(ctx, func(q *sqlitedb.Queries) error {
+		row, err := q.CreateLink(ctx, sqlitedb.CreateLinkParams{
+			OriginalUrl:  in.OriginalURL,
+			ShortName:    in.ShortName,
+			ExpiresAt:    toNullTime(in.ExpiresAt),
+			MaxVisits:    toNullInt64(in.MaxVisits),
+			PasswordHash: in.PasswordHash,
+			RedirectType: int64(in.RedirectType),
+			OwnerID:      toNullID(in.OwnerID),
+		})
+		if err != nil {
+			return err
+		}
+
+		res = fromSQLC(row)
+		return createEvent(ctx, q, domain.NewEvent(ctx, domain.EventCreated, nil, &res))
+	})
+
+	if err != nil {
+		if isUniqueViolation(err) {
+			return entity.Link{}, domain.ErrShortNameConflict
+		}
+		if isForeignKeyViolation(err) {
+			return entity.Link{}, domain.ErrInvalidInput
+		}
+		return entity.Link{}, err
+	}
+
+	return res, nil
+}
+
+/*Метод пакетного создания ссылок в одной транзакции (конфликт short_name не прерывает транзакцию благодаря ON CONFLICT DO NOTHING)*/
+func (r *Repository) CreateBatch(ctx context.Context, in []domain.CreateInput, atomic bool) ([]domain.BatchResult, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	qtx := r.q.WithTx(tx)
+	res := make([]domain.BatchResult, len(in))
+	conflict := false
+
+	for i, v := range in {
+		row, err := qtx.CreateLinkIfAbsent(ctx, sqlitedb.CreateLinkIfAbsentParams{
+			OriginalUrl:  v.OriginalURL,
+			ShortName:    v.ShortName,
+			ExpiresAt:    toNullTime(v.ExpiresAt),
+			MaxVisits:    toNullInt64(v.MaxVisits),
+			PasswordHash: v.PasswordHash,
+			RedirectType: int64(v.RedirectType),
+			OwnerID:      toNullID(v.OwnerID),
+		})
+		if errors.Is(err, sql.ErrNoRows) {
+			res[i].Conflict = true
+			conflict = true
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		res[i].Link = fromSQLC(row)
+		if err := createEvent(ctx, qtx, domain.NewEvent(ctx, domain.EventCreated, nil, &res[i].Link)); err != nil {
+			return nil, err
+		}
+	}
+
+	if atomic && conflict {
+		return res, domain.ErrShortNameConflict
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+/*Метод обновления ссылки (прежние original_url и short_name попадают в историю)*/
+func (r *Repository) Update(ctx context.Context, id int64, in domain.UpdateInput) (entity.Link, error) {
+	var res entity.Link
+	err := r.inTx(ctx, func(q *sqlitedb.Queries) error {
+		// транзакция берёт блокировку записи сразу (_txlock=immediate), поэтому прежнее состояние не изменится до UPDATE
+		old, err := q.GetLink(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		row, err := q.UpdateLink(ctx, sqlitedb.UpdateLinkParams{
+			ID:           id,
+			OriginalUrl:  in.OriginalURL,
+			ShortName:    in.ShortName,
+			ExpiresAt:    toNullTime(in.ExpiresAt),
+			MaxVisits:    toNullInt64(in.MaxVisits),
+			PasswordHash: in.PasswordHash,
+			RedirectType: int64(in.RedirectType),
+			OwnerID:      toNullID(in.OwnerID),
+		})
+		if err != nil {
+			return err
+		}
+
+		before := fromSQLC(old)
+		res = fromSQLC(row)
+		return createEvent(ctx, q, domain.NewEvent(ctx, domain.EventUpdated, &before, &res))
+	})
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return entity.Link{}, domain.ErrNotFound
+		}
+
+		if isUniqueViolation(err) {
+			return entity.Link{}, domain.ErrShortNameConflict
+		}
+
+		if isForeignKeyViolation(err) {
+			return entity.Link{}, domain.ErrInvalidInput
+		}
+
+		return entity.Link{}, err
+	}
+
+	return res, nil
+}
+
+/*Метод перемещения ссылки в корзину*/
+func (r *Repository) Delete(ctx context.Context, id int64) error {
+	err := r.inTx(ctx, func(q *sqlitedb.Queries) error {
+		row, err := q.TrashLink(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		l := fromSQLC(row)
+		return createEvent(ctx, q, domain.NewEvent(ctx, domain.EventDeleted, &l, nil))
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.ErrNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+/*Метод восстановления ссылки из корзины*/
+func (r *Repository) Restore(ctx context.Context, id int64) (entity.Link, error) {
+	var res entity.Link
+	err := r.inTx(ctx, func(q *sqlitedb.Queries) error {
+		row, err := q.RestoreLink(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		res = fromSQLC(row)
+		return createEvent(ctx, q, domain.NewEvent(ctx, domain.EventRestored, nil, &res))
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return entity.Link{}, domain.ErrNotFound
+		}
+		return entity.Link{}, err
+	}
+
+	return res, nil
+}
+
+/*Метод окончательного удаления ссылок, попавших в корзину раньше before (посещения удаляются каскадно, история сохраняется с записью purged)*/
+func (r *Repository) PurgeTrashed(ctx context.Context, before time.Time) (int64, error) {
+	var n int64
+	err := r.inTx(ctx, func(q *sqlitedb.Queries) error {
+		err := q.CreatePurgedLinkEvents(ctx, sqlitedb.CreatePurgedLinkEventsParams{
+			Actor:     domain.ActorFrom(ctx),
+			RequestID: domain.RequestIDFrom(ctx),
+			Before:    before.UTC(),
+		})
+		if err != nil {
+			return err
+		}
+
+		n, err = q.PurgeTrashedLinks(ctx, before.UTC())
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return n, nil
+}
+
+/*Метод получения истории изменений ссылки*/
+func (r *Repository) History(ctx context.Context, id int64) ([]entity.LinkEvent, error) {
+	rows, err := r.q.ListLinkEvents(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	// у каждой ссылки есть хотя бы запись о создании (для старых ссылок её добавляет миграция)
+	if len(rows) == 0 {
+		return nil, domain.ErrNotFound
+	}
+
+	res := make([]entity.LinkEvent, 0, len(rows))
+	for _, e := range rows {
+		res = append(res, entity.LinkEvent{
+			ID:             e.ID,
+			LinkID:         e.LinkID,
+			Type:           e.Type,
+			OldOriginalURL: e.OldOriginalUrl,
+			NewOriginalURL: e.NewOriginalUrl,
+			OldShortName:   e.OldShortName,
+			NewShortName:   e.NewShortName,
+			Actor:          e.Actor,
+			RequestID:      e.RequestID,
+			CreatedAt:      e.CreatedAt,
+		})
+	}
+
+	return res, nil
+}
+
+/*Метод выполнения fn в транзакции (ошибка fn откатывает транзакцию)*/
+func (r *Repository) inTx(ctx context.Context, fn func(q *sqlitedb.Queries) error) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if err := fn(r.q.WithTx(tx)); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+/*Метод записи изменения ссылки в историю*/
+func createEvent(ctx context.Context, q *sqlitedb.Queries, e entity.LinkEvent) error {
+	return q.CreateLinkEvent(ctx, sqlitedb.CreateLinkEventParams{
+		LinkID:         e.LinkID,
+		Type:           e.Type,
+		OldOriginalUrl: e.OldOriginalURL,
+		NewOriginalUrl: e.NewOriginalURL,
+		OldShortName:   e.OldShortName,
+		NewShortName:   e.NewShortName,
+		Actor:          e.Actor,
+		RequestID:      e.RequestID,
+	})
+}
+
+/*Метод выполнения динамического запроса списка ссылок (колонки — sqlquery.LinkColumns)*/
+func (r *Repository) queryLinks(ctx context.Context, query string, args []any) ([]entity.Link, error) {
+	res := []entity.Link{}
+	err := r.eachLink(ctx, query, args, func(l entity.Link) error {
+		res = append(res, l)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+/*Метод построчного чтения динамического запроса ссылок из курсора (колонки — sqlquery.LinkColumns)*/
+func (r *Repository) eachLink(ctx context.Context, query string, args []any, fn func(entity.Link) error) error {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var l sqlitedb.Link
+		if err := rows.Scan(
+			&l.ID,
+			&l.OriginalUrl,
+			&l.ShortName,
+			&l.CreatedAt,
+			&l.ExpiresAt,
+			&l.MaxVisits,
+			&l.VisitCount,
+			&l.PasswordHash,
+			&l.RedirectType,
+			&l.DeletedAt,
+			&l.OwnerID,
+		); err != nil {
+			return err
+		}
+		if err := fn(fromSQLC(l)); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
+
+/*Метод преобразования из sqlitedb.Link в entity.Link*/
+func fromSQLC(l sqlitedb.Link) entity.Link {
+	return entity.Link{
+		ID:           l.ID,
+		OriginalURL:  l.OriginalUrl,
+		ShortName:    l.ShortName,
+		CreatedAt:    l.CreatedAt,
+		ExpiresAt:    fromNullTime(l.ExpiresAt),
+		MaxVisits:    fromNullInt64(l.MaxVisits),
+		VisitCount:   l.VisitCount,
+		PasswordHash: l.PasswordHash,
+		RedirectType: int(l.RedirectType),
+		DeletedAt:    fromNullTime(l.DeletedAt),
+		OwnerID:      fromNullID(l.OwnerID),
+	}
+}
+
+/*Метод преобразования *time.Time в sql.NullTime (в UTC, чтобы строки в базе сравнивались корректно)*/
+func toNullTime(t *time.Time) sql.NullTime {
+	if t == nil {
+		return sql.NullTime{}
+	}
+
+	return sql.NullTime{Time: t.UTC(), Valid: true}
+}
+
+/*Метод преобразования sql.NullTime в *time.Time*/
+func fromNullTime(t sql.NullTime) *time.Time {
+	if !t.Valid {
+		return nil
+	}
+
+	v := t.Time
+	return &v
+}
+
+/*Метод преобразования *int в sql.NullInt64*/
+func toNullInt64(v *int) sql.NullInt64 {
+	if v == nil {
+		return sql.NullInt64{}
+	}
+
+	return sql.NullInt64{Int64: int64(*v), Valid: true}
+}
+
+/*Метод преобразования sql.NullInt64 в *int*/
+func fromNullInt64(v sql.NullInt64) *int {
+	if !v.Valid {
+		return nil
+	}
+
+	n := int(v.Int64)
+	return &n
+}
+
+/*Метод преобразования *int64 (идентификатор) в sql.NullInt64*/
+func toNullID(v *int64) sql.NullInt64 {
+	if v == nil {
+		return sql.NullInt64{}
+	}
+
+	return sql.NullInt64{Int64: *v, Valid: true}
+}
+
+/*Метод преобразования sql.NullInt64 в *int64 (идентификатор)*/
+func fromNullID(v sql.NullInt64) *int64 {
+	if !v.Valid {
+		return nil
+	}
+
+	id := v.Int64
+	return &id
+}
+
+/*Метод проверки на уникальность*/
+func isUniqueViolation(err error) bool {
+	var sqliteErr *sqlite.Error
+
+	if errors.As(err, &sqliteErr) {
+		return sqliteErr.Code() == sqlite3.SQLITE_CONSTRAINT_UNIQUE
+	}
+
+	return false
+}
+
+/*Метод проверки нарушения внешнего ключа (например, несуществующий владелец)*/
+func isForeignKeyViolation(err error) bool {
+	var sqliteErr *sqlite.Error
+
+	if errors.As(err, &sqliteErr) {
+		return sqliteErr.Code() == sqlite3.SQLITE_CONSTRAINT_FOREIGNKEY
+	}
+
+	return false
+}
+
+/*Интерфейс для работы с базой данных*/
+var _ domain.Repository = (*Repository)(nil)
@@ -0,0 +1,617 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"link-service/src/domain/apikey"
+	"link-service/src/domain/entity"
+	"link-service/src/domain/link"
+	"link-service/src/domain/linkvisit"
+	"link-service/src/domain/user"
+	sqlitedb "link-service/src/infrastructure/database/sqlite"
+
+	"github.com/go-playground/assert/v2"
+)
+
+/*Открытие временной базы с применёнными Up-частями миграций goose*/
+func openTestDB(t *testing.T) *sql.DB {
+	t.Helper()
+
+	db, err := sqlitedb.Open("sqlite://" + filepath.Join(t.TempDir(), "links.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = db.Close() })
+
+	files, err := filepath.Glob("../../../../db/migrations/sqlite/*.sql")
+	if err != nil || len(files) == 0 {
+		t.Fatalf("no sqlite migrations found: %v", err)
+	}
+	sort.Strings(files)
+
+	for _, f := range files {
+		raw, err := os.ReadFile(f)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		up, _, _ := strings.Cut(string(raw), "-- +goose Down")
+		if _, err := db.Exec(up); err != nil {
+			t.Fatalf("%s: %v", f, err)
+		}
+	}
+
+	return db
+}
+
+func TestLinkRepositoryConflictsAndNotFound(t *testing.T) {
+	ctx := context.Background()
+	repo := New(openTestDB(t))
+
+	expiresAt := time.Now().Add(time.Hour).Truncate(time.Second)
+	maxVisits := 3
+	a, err := repo.Create(ctx, link.CreateInput{
+		OriginalURL:  "https://a.example",
+		ShortName:    "aaa",
+		ExpiresAt:    &expiresAt,
+		MaxVisits:    &maxVisits,
+		RedirectType: 301,
+	})
+	assert.Equal(t, nil, err)
+	assert.Equal(t, true, a.ExpiresAt.Equal(expiresAt))
+	assert.Equal(t, 3, *a.MaxVisits)
+	assert.Equal(t, 301, a.RedirectType)
+	assert.Equal(t, false, a.CreatedAt.IsZero())
+
+	b, err := repo.Create(ctx, link.CreateInput{OriginalURL: "https://b.example", ShortName: "bbb", RedirectType: 302})
+	assert.Equal(t, nil, err)
+
+	_, err = repo.Create(ctx, link.CreateInput{OriginalURL: "https://c.example", ShortName: "aaa", RedirectType: 302})
+	assert.Equal(t, link.ErrShortNameConflict, err)
+
+	_, err = repo.Update(ctx, b.ID, link.UpdateInput{OriginalURL: "https://b.example", ShortName: "aaa", RedirectType: 302})
+	assert.Equal(t, link.ErrShortNameConflict, err)
+
+	updated, err := repo.Update(ctx, a.ID, link.UpdateInput{OriginalURL: "https://a2.example", ShortName: "aaa2", RedirectType: 307})
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "aaa2", updated.ShortName)
+	assert.Equal(t, 307, updated.RedirectType)
+
+	_, err = repo.GetByShortName(ctx, "aaa")
+	assert.Equal(t, link.ErrNotFound, err)
+	_, err = repo.Get(ctx, 100)
+	assert.Equal(t, link.ErrNotFound, err)
+	_, err = repo.Update(ctx, 100, link.UpdateInput{OriginalURL: "https://x.example", ShortName: "xxx", RedirectType: 302})
+	assert.Equal(t, link.ErrNotFound, err)
+	assert.Equal(t, link.ErrNotFound, repo.Delete(ctx, 100))
+}
+
+func TestLinkRepositoryFilterAndSort(t *testing.T) {
+	ctx := context.Background()
+	repo := New(openTestDB(t))
+
+	maxVisits := 5
+	for _, in := range []link.CreateInput{
+		{OriginalURL: "https://shop.example/PROMO", ShortName: "b-promo", RedirectType: 302},
+		{OriginalURL: "https://blog.example", ShortName: "a-promo", MaxVisits: &maxVisits, RedirectType: 302},
+		{OriginalURL: "https://docs.example/100%_off", ShortName: "docs", RedirectType: 302},
+	} {
+		_, err := repo.Create(ctx, in)
+		assert.Equal(t, nil, err)
+	}
+
+	q := link.Query{Filter: link.Filter{Q: "promo"}, Sort: link.Sort{Field: "short_name", Desc: true}}
+	res, err := repo.ListWithRange(ctx, &link.Range{Start: 0, End: 0}, q)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 1, len(res))
+	assert.Equal(t, "b-promo", res[0].ShortName)
+
+	total, err := repo.Count(ctx, q.Filter)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, int64(2), total)
+
+	res, err = repo.List(ctx, link.Query{Filter: link.Filter{Q: "%_"}})
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 1, len(res))
+	assert.Equal(t, "docs", res[0].ShortName)
+
+	res, err = repo.List(ctx, link.Query{Sort: link.Sort{Field: "max_visits", Desc: true}})
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "a-promo", res[0].ShortName)
+	assert.Equal(t, "docs", res[1].ShortName)
+
+	res, err = repo.List(ctx, link.Query{Filter: link.Filter{IDs: []int64{3, 1}, ShortName: "docs"}})
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 1, len(res))
+	assert.Equal(t, int64(3), res[0].ID)
+
+	var names []string
+	err = repo.Each(ctx, q, func(l entity.Link) error {
+		names = append(names, l.ShortName)
+		return nil
+	})
+	assert.Equal(t, nil, err)
+	assert.Equal(t, []string{"b-promo", "a-promo"}, names)
+}
+
+func TestLinkRepositoryCreateBatch(t *testing.T) {
+	ctx := context.Background()
+	repo := New(openTestDB(t))
+
+	_, err := repo.Create(ctx, link.CreateInput{OriginalURL: "https://taken.example", ShortName: "taken", RedirectType: 302})
+	assert.Equal(t, nil, err)
+
+	in := []link.CreateInput{
+		{OriginalURL: "https://a.example", ShortName: "a1", RedirectType: 302},
+		{OriginalURL: "https://b.example", ShortName: "taken", RedirectType: 302},
+		{OriginalURL: "https://c.example", ShortName: "a1", RedirectType: 302},
+	}
+
+	res, err := repo.CreateBatch(ctx, in, true)
+	assert.Equal(t, link.ErrShortNameConflict, err)
+	assert.Equal(t, []bool{false, true, true}, []bool{res[0].Conflict, res[1].Conflict, res[2].Conflict})
+	_, err = repo.GetByShortName(ctx, "a1")
+	assert.Equal(t, link.ErrNotFound, err)
+
+	res, err = repo.CreateBatch(ctx, in, false)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, []bool{false, true, true}, []bool{res[0].Conflict, res[1].Conflict, res[2].Conflict})
+	assert.Equal(t, "https://a.example", res[0].Link.OriginalURL)
+
+	total, err := repo.Count(ctx, link.Filter{})
+	assert.Equal(t, nil, err)
+	assert.Equal(t, int64(2), total)
+}
+
+func TestLinkRepositoryTrash(t *testing.T) {
+	ctx := context.Background()
+	db := openTestDB(t)
+	links := New(db)
+	visits := NewLinkVisitRepository(db)
+
+	a, err := links.Create(ctx, link.CreateInput{OriginalURL: "https://a.example", ShortName: "trashed", RedirectType: 302})
+	assert.Equal(t, nil, err)
+	_, err = links.Create(ctx, link.CreateInput{OriginalURL: "https://b.example", ShortName: "alive", RedirectType: 302})
+	assert.Equal(t, nil, err)
+	assert.Equal(t, nil, visits.CreateBatch(ctx, []linkvisit.CreateInput{{LinkID: a.ID, Status: 302}}))
+	// посещение несуществующей ссылки отклоняется как ошибка строки, а не хранилища
+	err = visits.CreateBatch(ctx, []linkvisit.CreateInput{{LinkID: a.ID, Status: 302}, {LinkID: 999, Status: 302}})
+	assert.Equal(t, true, errors.Is(err, linkvisit.ErrInvalidRow))
+
+	assert.Equal(t, nil, links.Delete(ctx, a.ID))
+	assert.Equal(t, link.ErrNotFound, links.Delete(ctx, a.ID))
+
+	_, err = links.GetByShortName(ctx, "trashed")
+	assert.Equal(t, link.ErrNotFound, err)
+	_, err = links.Get(ctx, a.ID)
+	assert.Equal(t, link.ErrNotFound, err)
+	_, err = links.Update(ctx, a.ID, link.UpdateInput{OriginalURL: "https://x.example", ShortName: "trashed", RedirectType: 302})
+	assert.Equal(t, link.ErrNotFound, err)
+
+	total, err := links.Count(ctx, link.Filter{})
+	assert.Equal(t, nil, err)
+	assert.Equal(t, int64(1), total)
+
+	trash, err := links.List(ctx, link.Query{Filter: link.Filter{Trashed: true}})
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 1, len(trash))
+	assert.Equal(t, "trashed", trash[0].ShortName)
+	assert.NotEqual(t, nil, trash[0].DeletedAt)
+
+	// короткое имя остаётся занятым, пока ссылка в корзине
+	_, err = links.Create(ctx, link.CreateInput{OriginalURL: "https://c.example", ShortName: "trashed", RedirectType: 302})
+	assert.Equal(t, link.ErrShortNameConflict, err)
+
+	restored, err := links.Restore(ctx, a.ID)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, (*time.Time)(nil), restored.DeletedAt)
+	_, err = links.Restore(ctx, a.ID)
+	assert.Equal(t, link.ErrNotFound, err)
+	_, err = links.GetByShortName(ctx, "trashed")
+	assert.Equal(t, nil, err)
+
+	// окончательно удаляются только ссылки, попавшие в корзину раньше границы, вместе с посещениями
+	assert.Equal(t, nil, links.Delete(ctx, a.ID))
+	purged, err := links.PurgeTrashed(ctx, time.Now().Add(-time.Hour))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, int64(0), purged)
+
+	purged, err = links.PurgeTrashed(link.WithActor(ctx, "system"), time.Now().Add(time.Minute))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, int64(1), purged)
+
+	// история переживает окончательное удаление и заканчивается записью purged
+	history, err := links.History(ctx, a.ID)
+	assert.Equal(t, nil, err)
+	last := history[len(history)-1]
+	assert.Equal(t, link.EventPurged, last.Type)
+	assert.Equal(t, "trashed", last.OldShortName)
+	assert.Equal(t, "system", last.Actor)
+
+	count, err := visits.Count(ctx, linkvisit.Filter{})
+	assert.Equal(t, nil, err)
+	assert.Equal(t, int64(0), count)
+	_, err = links.Restore(ctx, a.ID)
+	assert.Equal(t, link.ErrNotFound, err)
+}
+
+func TestLinkRepositoryHistory(t *testing.T) {
+	ctx := link.WithRequestID(link.WithActor(context.Background(), "admin"), "req-1")
+	repo := New(openTestDB(t))
+
+	l, err := repo.Create(ctx, link.CreateInput{OriginalURL: "https://a.example", ShortName: "hist", RedirectType: 302})
+	assert.Equal(t, nil, err)
+	_, err = repo.Update(context.Background(), l.ID, link.UpdateInput{OriginalURL: "https://b.example", ShortName: "hist2", RedirectType: 302})
+	assert.Equal(t, nil, err)
+	assert.Equal(t, nil, repo.Delete(ctx, l.ID))
+	_, err = repo.Restore(ctx, l.ID)
+	assert.Equal(t, nil, err)
+
+	// неудачное изменение не попадает в историю
+	_, err = repo.Create(ctx, link.CreateInput{OriginalURL: "https://c.example", ShortName: "hist2", RedirectType: 302})
+	assert.Equal(t, link.ErrShortNameConflict, err)
+
+	events, err := repo.History(ctx, l.ID)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 4, len(events))
+	for i := range events {
+		assert.Equal(t, l.ID, events[i].LinkID)
+		assert.Equal(t, false, events[i].CreatedAt.IsZero())
+		events[i].ID, events[i].LinkID, events[i].CreatedAt = 0, 0, time.Time{}
+	}
+	assert.Equal(t, []entity.LinkEvent{
+		{Type: link.EventCreated, NewOriginalURL: "https://a.example", NewShortName: "hist", Actor: "admin", RequestID: "req-1"},
+		{Type: link.EventUpdated, OldOriginalURL: "https://a.example", NewOriginalURL: "https://b.example", OldShortName: "hist", NewShortName: "hist2"},
+		{Type: link.EventDeleted, OldOriginalURL: "https://b.example", OldShortName: "hist2", Actor: "admin", RequestID: "req-1"},
+		{Type: link.EventRestored, NewOriginalURL: "https://b.example", NewShortName: "hist2", Actor: "admin", RequestID: "req-1"},
+	}, events)
+
+	_, err = repo.History(ctx, l.ID+1)
+	assert.Equal(t, link.ErrNotFound, err)
+}
+
+func TestAPIKeyRepository(t *testing.T) {
+	ctx := context.Background()
+	db := openTestDB(t)
+	repo := NewAPIKeyRepository(db)
+
+	owner, err := NewUserRepository(db).Create(ctx, user.CreateInput{Username: "alice", PasswordHash: "x", Role: user.RoleMember})
+	assert.Equal(t, nil, err)
+
+	k, err := repo.Create(ctx, apikey.CreateInput{Name: "ci", Prefix: "lsk_abcdefgh", KeyHash: "hash-1", Scopes: []string{apikey.ScopeLinksRead, apikey.ScopeVisitsRead}, OwnerID: owner.ID})
+	assert.Equal(t, nil, err)
+	assert.Equal(t, []string{"links:read", "visits:read"}, k.Scopes)
+	assert.Equal(t, &owner.ID, k.OwnerID)
+	assert.Equal(t, (*time.Time)(nil), k.LastUsedAt)
+
+	usedAt := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	assert.Equal(t, nil, repo.Touch(ctx, k.ID, usedAt))
+
+	got, err := repo.GetByHash(ctx, "hash-1")
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "ci", got.Name)
+	assert.Equal(t, true, got.LastUsedAt.Equal(usedAt))
+
+	assert.Equal(t, nil, repo.Revoke(ctx, k.ID))
+	assert.Equal(t, apikey.ErrNotFound, repo.Revoke(ctx, k.ID))
+	_, err = repo.GetByHash(ctx, "hash-1")
+	assert.Equal(t, apikey.ErrNotFound, err)
+
+	keys, err := repo.List(ctx)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 1, len(keys))
+	assert.NotEqual(t, (*time.Time)(nil), keys[0].RevokedAt)
+}
+
+func TestLinkVisitRepositoryLimitBatchAndCascade(t *testing.T) {
+	ctx := context.Background()
+	db := openTestDB(t)
+	links := New(db)
+	visits := NewLinkVisitRepository(db)
+
+	maxVisits := 1
+	l, err := links.Create(ctx, link.CreateInput{OriginalURL: "https://example.com", ShortName: "once", MaxVisits: &maxVisits, RedirectType: 302})
+	assert.Equal(t, nil, err)
+
+	_, err = visits.CreateWithinLimit(ctx, linkvisit.CreateInput{LinkID: l.ID, IP: "127.0.0.1", Status: 302})
+	assert.Equal(t, nil, err)
+	_, err = visits.CreateWithinLimit(ctx, linkvisit.CreateInput{LinkID: l.ID, IP: "127.0.0.1", Status: 302})
+	assert.Equal(t, linkvisit.ErrLimitReached, err)
+
+	assert.Equal(t, nil, visits.CreateBatch(ctx, []linkvisit.CreateInput{
+		{LinkID: l.ID, IP: "10.0.0.1", Status: 410},
+		{LinkID: l.ID, IP: "10.0.0.2", Status: 410},
+	}))
+
+	page, err := visits.ListWithRange(ctx, &link.Range{Start: 1, End: 2}, linkvisit.Filter{})
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 2, len(page))
+	assert.Equal(t, "10.0.0.1", page[0].IP)
+
+	page, err = visits.ListAfter(ctx, &link.Cursor{AfterID: page[0].ID, Limit: 5}, linkvisit.Filter{})
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 1, len(page))
+	assert.Equal(t, "10.0.0.2", page[0].IP)
+
+	assert.Equal(t, nil, links.Delete(ctx, l.ID))
+	_, err = links.PurgeTrashed(ctx, time.Now().Add(time.Minute))
+	assert.Equal(t, nil, err)
+
+	total, err := visits.Count(ctx, linkvisit.Filter{})
+	assert.Equal(t, nil, err)
+	assert.Equal(t, int64(0), total)
+}
+
+func TestLinkVisitRepositoryStats(t *testing.T) {
+	ctx := context.Background()
+	db := openTestDB(t)
+	links := New(db)
+	visits := NewLinkVisitRepository(db)
+
+	l, err := links.Create(ctx, link.CreateInput{OriginalURL: "https://example.com", ShortName: "stats", RedirectType: 302})
+	assert.Equal(t, nil, err)
+
+	assert.Equal(t, nil, visits.CreateBatch(ctx, []linkvisit.CreateInput{
+		{LinkID: l.ID, Referer: "https://a.example", UserAgent: "curl", Status: 302},
+		{LinkID: l.ID, Referer: "https://a.example", UserAgent: "firefox", Status: 302},
+		{LinkID: l.ID, Referer: "", UserAgent: "curl", Status: 410},
+		{LinkID: l.ID, Referer: "https://b.example", UserAgent: "curl", Status: 302},
+	}))
+
+	// воскресенье и понедельник — разные недели, последнее посещение вне периода
+	for id, createdAt := range map[int]string{
+		1: "2025-03-30 10:15:00.000",
+		2: "2025-03-30 10:45:00.000",
+		3: "2025-03-31 09:00:00.000",
+		4: "2025-04-10 00:00:00.000",
+	} {
+		_, err := db.Exec("UPDATE link_visits SET created_at = ? WHERE id = ?", createdAt, id)
+		assert.Equal(t, nil, err)
+	}
+
+	q := linkvisit.StatsQuery{
+		LinkID:   l.ID,
+		From:     time.Date(2025, 3, 30, 0, 0, 0, 0, time.UTC),
+		To:       time.Date(2025, 4, 10, 0, 0, 0, 0, time.UTC),
+		Interval: linkvisit.IntervalWeek,
+		Top:      1,
+	}
+
+	stats, err := visits.Stats(ctx, q)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, int64(3), stats.Total)
+	assert.Equal(t, []linkvisit.Bucket{
+		{Start: time.Date(2025, 3, 24, 0, 0, 0, 0, time.UTC), Clicks: 2},
+		{Start: time.Date(2025, 3, 31, 0, 0, 0, 0, time.UTC), Clicks: 1},
+	}, stats.Series)
+	assert.Equal(t, []linkvisit.ValueCount{{Value: "https://a.example", Count: 2}}, stats.TopReferers)
+	assert.Equal(t, []linkvisit.ValueCount{{Value: "curl", Count: 2}}, stats.TopUserAgents)
+	assert.Equal(t, []linkvisit.StatusCount{{Status: 302, Count: 2}, {Status: 410, Count: 1}}, stats.Statuses)
+
+	q.Interval = linkvisit.IntervalHour
+	stats, err = visits.Stats(ctx, q)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, linkvisit.Bucket{Start: time.Date(2025, 3, 30, 10, 0, 0, 0, time.UTC), Clicks: 2}, stats.Series[0])
+}
+
+func TestLinkVisitRepositoryFilter(t *testing.T) {
+	ctx := context.Background()
+	db := openTestDB(t)
+	links := New(db)
+	visits := NewLinkVisitRepository(db)
+
+	a, err := links.Create(ctx, link.CreateInput{OriginalURL: "https://a.example", ShortName: "a", RedirectType: 302})
+	assert.Equal(t, nil, err)
+	b, err := links.Create(ctx, link.CreateInput{OriginalURL: "https://b.example", ShortName: "b", RedirectType: 302})
+	assert.Equal(t, nil, err)
+
+	assert.Equal(t, nil, visits.CreateBatch(ctx, []linkvisit.CreateInput{
+		{LinkID: a.ID, IP: "10.0.0.1", Referer: "https://www.Google.com/", Status: 302},
+		{LinkID: a.ID, IP: "10.0.0.2", Referer: "", Status: 410},
+		{LinkID: a.ID, IP: "192.168.1.1", Referer: "https://google.com/", Status: 302},
+		{LinkID: b.ID, IP: "10.0.0.1", Referer: "https://google.com/", Status: 302},
+	}))
+	_, err = db.Exec("UPDATE link_visits SET created_at = '2025-01-01 12:00:00.000' WHERE id = 3")
+	assert.Equal(t, nil, err)
+
+	since := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	f := linkvisit.Filter{LinkID: a.ID, Status: 302, Referer: "GOOGLE", From: &since}
+
+	total, err := visits.Count(ctx, f)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, int64(1), total)
+
+	page, err := visits.ListWithRange(ctx, &link.Range{Start: 0, End: 9}, linkvisit.Filter{IP: "10.0.0."})
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 3, len(page))
+
+	page, err = visits.ListAfter(ctx, &link.Cursor{AfterID: 1, Limit: 10}, linkvisit.Filter{IP: "10.0.0.1"})
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 1, len(page))
+	assert.Equal(t, b.ID, page[0].LinkID)
+
+	var ids []int64
+	err = visits.Each(ctx, f, func(v entity.LinkVisit) error {
+		ids = append(ids, v.ID)
+		return nil
+	})
+	assert.Equal(t, nil, err)
+	assert.Equal(t, []int64{1}, ids)
+
+	ids = nil
+	stop := errors.New("stop")
+	err = visits.Each(ctx, linkvisit.Filter{IP: "10.0.0."}, func(v entity.LinkVisit) error {
+		ids = append(ids, v.ID)
+		return stop
+	})
+	assert.Equal(t, stop, err)
+	assert.Equal(t, []int64{1}, ids)
+}
+
+func TestLinkVisitRepositoryUserAgentFields(t *testing.T) {
+	ctx := context.Background()
+	db := openTestDB(t)
+	links := New(db)
+	visits := NewLinkVisitRepository(db)
+
+	l, err := links.Create(ctx, link.CreateInput{OriginalURL: "https://example.com", ShortName: "ua", RedirectType: 302})
+	assert.Equal(t, nil, err)
+
+	iphone := linkvisit.UserAgent{Browser: "Safari", BrowserVersion: "17", OS: "iOS", Device: linkvisit.DeviceMobile}
+	bot := linkvisit.UserAgent{Browser: "curl", BrowserVersion: "8", IsBot: true}
+
+	munich := linkvisit.Location{Country: "DE", Region: "Bavaria", City: "Munich"}
+
+	v, err := visits.Create(ctx, linkvisit.CreateInput{LinkID: l.ID, Status: 302, Agent: iphone, Geo: munich})
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "DE", v.Country)
+	assert.Equal(t, "Bavaria", v.Region)
+	assert.Equal(t, "Munich", v.City)
+	assert.Equal(t, "Safari", v.Browser)
+	assert.Equal(t, "17", v.BrowserVersion)
+	assert.Equal(t, "iOS", v.OS)
+	assert.Equal(t, "mobile", v.Device)
+	assert.Equal(t, false, v.IsBot)
+
+	assert.Equal(t, nil, visits.CreateBatch(ctx, []linkvisit.CreateInput{
+		{LinkID: l.ID, Status: 302, Agent: iphone},
+		{LinkID: l.ID, Status: 302, Agent: bot},
+	}))
+
+	humans := false
+	total, err := visits.Count(ctx, linkvisit.Filter{Device: linkvisit.DeviceMobile, IsBot: &humans})
+	assert.Equal(t, nil, err)
+	assert.Equal(t, int64(2), total)
+
+	page, err := visits.ListAfter(ctx, &link.Cursor{Limit: 10}, linkvisit.Filter{Browser: "curl"})
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 1, len(page))
+	assert.Equal(t, true, page[0].IsBot)
+
+	q := linkvisit.StatsQuery{
+		LinkID:   l.ID,
+		From:     time.Now().Add(-time.Hour),
+		To:       time.Now().Add(time.Hour),
+		Interval: linkvisit.IntervalDay,
+		Top:      10,
+	}
+
+	// по умолчанию боты не учитываются
+	stats, err := visits.Stats(ctx, q)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, int64(2), stats.Total)
+	assert.Equal(t, int64(1), stats.Bots)
+	assert.Equal(t, []linkvisit.ValueCount{{Value: "mobile", Count: 2}}, stats.Devices)
+	assert.Equal(t, []linkvisit.ValueCount{{Value: "", Count: 1}, {Value: "DE", Count: 1}}, stats.Countries)
+
+	q.IncludeBots = true
+	stats, err = visits.Stats(ctx, q)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, int64(3), stats.Total)
+	assert.Equal(t, int64(1), stats.Bots)
+	assert.Equal(t, []linkvisit.ValueCount{{Value: "Safari", Count: 2}, {Value: "curl", Count: 1}}, stats.Browsers)
+	assert.Equal(t, []linkvisit.ValueCount{{Value: "iOS", Count: 2}, {Value: "", Count: 1}}, stats.OperatingSystems)
+	assert.Equal(t, []linkvisit.ValueCount{{Value: "mobile", Count: 2}, {Value: "", Count: 1}}, stats.Devices)
+}
+
+func TestLinkVisitRepositoryDeleteBefore(t *testing.T) {
+	ctx := context.Background()
+	db := openTestDB(t)
+	links := New(db)
+	visits := NewLinkVisitRepository(db)
+
+	a, err := links.Create(ctx, link.CreateInput{OriginalURL: "https://a.example", ShortName: "a", RedirectType: 302})
+	assert.Equal(t, nil, err)
+
+	in := make([]linkvisit.CreateInput, 5)
+	for i := range in {
+		in[i] = linkvisit.CreateInput{LinkID: a.ID, Status: 302}
+	}
+	assert.Equal(t, nil, visits.CreateBatch(ctx, in))
+	_, err = db.Exec("UPDATE link_visits SET created_at = '2025-01-01 12:00:00.000' WHERE id <= 3")
+	assert.Equal(t, nil, err)
+
+	before := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	count, err := visits.CountBefore(ctx, before)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, int64(3), count)
+
+	deleted, err := visits.DeleteBefore(ctx, before, 2)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, int64(2), deleted)
+
+	deleted, err = visits.DeleteBefore(ctx, before, 2)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, int64(1), deleted)
+
+	total, err := visits.Count(ctx, linkvisit.Filter{})
+	assert.Equal(t, nil, err)
+	assert.Equal(t, int64(2), total)
+}
+
+func TestLinkVisitRepositoryDailyRollup(t *testing.T) {
+	ctx := context.Background()
+	db := openTestDB(t)
+	links := New(db)
+	visits := NewLinkVisitRepository(db)
+
+	a, err := links.Create(ctx, link.CreateInput{OriginalURL: "https://a.example", ShortName: "a", RedirectType: 302})
+	assert.Equal(t, nil, err)
+
+	state, err := visits.RollupState(ctx)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, linkvisit.RollupState{}, state)
+
+	assert.Equal(t, nil, visits.CreateBatch(ctx, []linkvisit.CreateInput{
+		{LinkID: a.ID, Referer: "https://www.google.com/search", Status: 302},
+		{LinkID: a.ID, Referer: "https://www.google.com/search", Status: 302},
+		{LinkID: a.ID, Status: 410, Agent: linkvisit.UserAgent{IsBot: true}},
+		{LinkID: a.ID, Status: 302},
+	}))
+	_, err = db.Exec("UPDATE link_visits SET created_at = '2025-01-01 12:00:00.000' WHERE id <= 3")
+	assert.Equal(t, nil, err)
+
+	day := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	groups, err := visits.CountGroups(ctx, linkvisit.GroupQuery{From: day, To: day.AddDate(0, 0, 1)})
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 2, len(groups))
+
+	counts := []linkvisit.DailyCount{
+		{LinkID: a.ID, Day: day, Status: 302, RefererHost: "google.com", Visits: 2},
+		{LinkID: a.ID, Day: day, Status: 410, IsBot: true, Visits: 1},
+	}
+	// повторная запись дня не удваивает счётчики
+	assert.Equal(t, nil, visits.SaveDaily(ctx, day, counts))
+	assert.Equal(t, nil, visits.SaveDaily(ctx, day, counts))
+
+	daily, err := visits.ListDaily(ctx, a.ID, day, day.AddDate(0, 0, 1))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, counts, daily)
+
+	state, err = visits.RollupState(ctx)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, day.AddDate(0, 0, 1), state.RolledUntil)
+	assert.Equal(t, time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC), state.FirstVisitAt)
+
+	// граница агрегации не сдвигается назад
+	assert.Equal(t, nil, visits.SaveDaily(ctx, day.AddDate(0, 0, -5), nil))
+	state, err = visits.RollupState(ctx)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, day.AddDate(0, 0, 1), state.RolledUntil)
+
+	assert.Equal(t, nil, links.Delete(ctx, a.ID))
+	_, err = links.PurgeTrashed(ctx, time.Now().Add(time.Minute))
+	assert.Equal(t, nil, err)
+	daily, err = visits.ListDaily(ctx, a.ID, day, day.AddDate(0, 0, 1))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 0, len(daily))
+}
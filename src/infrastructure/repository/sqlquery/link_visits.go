@@ -0,0 +1,64 @@
+package sqlquery
+
+import (
+	"strings"
+
+	"link-service/src/domain/linkvisit"
+)
+
+/*Колонки посещения в порядке полей sqlc-модели LinkVisit*/
+const LinkVisitColumns = "id, link_id, ip, user_agent, referer, status, created_at, browser, browser_version, os, device, is_bot, country, region, city"
+
+/*Метод построения запроса всех посещений по фильтру в порядке id (без LIMIT — для потоковой выгрузки)*/
+func SelectLinkVisits(d Dialect, f linkvisit.Filter) (string, []any) {
+	b := &builder{d: d}
+
+	b.sb.WriteString("SELECT " + LinkVisitColumns + " FROM link_visits")
+	b.whereVisits(f)
+	b.sb.WriteString(" ORDER BY id")
+
+	return b.sb.String(), b.args
+}
+
+/*Метод добавления условий фильтра посещений (те же, что в sqlc-запросах списка)*/
+func (b *builder) whereVisits(f linkvisit.Filter) {
+	var conds []string
+
+	if f.LinkID != 0 {
+		conds = append(conds, "link_id = "+b.arg(f.LinkID))
+	}
+	if f.From != nil {
+		conds = append(conds, "created_at >= "+b.d.Time(b.arg(f.From.UTC())))
+	}
+	if f.To != nil {
+		conds = append(conds, "created_at < "+b.d.Time(b.arg(f.To.UTC())))
+	}
+	if f.Status != 0 {
+		conds = append(conds, "status = "+b.arg(f.Status))
+	}
+	if f.IP != "" {
+		conds = append(conds, "ip "+b.d.ILike+" "+b.arg("%"+EscapeLike(f.IP)+"%")+" ESCAPE '\\'")
+	}
+	if f.Referer != "" {
+		conds = append(conds, "referer "+b.d.ILike+" "+b.arg("%"+EscapeLike(f.Referer)+"%")+" ESCAPE '\\'")
+	}
+	if f.Browser != "" {
+		conds = append(conds, "browser = "+b.arg(f.Browser))
+	}
+	if f.OS != "" {
+		conds = append(conds, "os = "+b.arg(f.OS))
+	}
+	if f.Device != "" {
+		conds = append(conds, "device = "+b.arg(string(f.Device)))
+	}
+	if f.IsBot != nil {
+		conds = append(conds, "is_bot = "+b.arg(*f.IsBot))
+	}
+	if f.OwnerID != 0 {
+		conds = append(conds, "link_id IN (SELECT id FROM links WHERE owner_id = "+b.arg(f.OwnerID)+")")
+	}
+
+	if len(conds) > 0 {
+		b.sb.WriteString(" WHERE " + strings.Join(conds, " AND "))
+	}
+}
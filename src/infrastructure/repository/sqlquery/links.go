@@ -0,0 +1,139 @@
+package sqlquery
+
+import (
+	"strconv"
+	"strings"
+
+	"link-service/src/domain/link"
+)
+
+/*Отличия SQL-диалектов, важные для динамических запросов*/
+type Dialect struct {
+	Placeholder func(n int) string    /*Плейсхолдер n-го параметра (с 1)*/
+	ILike       string                /*Оператор LIKE без учёта регистра*/
+	Time        func(p string) string /*Приведение параметра-времени к виду, сравнимому с колонкой*/
+}
+
+var (
+	/*Диалект PostgreSQL*/
+	Postgres = Dialect{
+		Placeholder: func(n int) string { return "$" + strconv.Itoa(n) },
+		ILike:       "ILIKE",
+		Time:        func(p string) string { return p },
+	}
+	/*Диалект SQLite (LIKE и так не учитывает регистр для ASCII)*/
+	SQLite = Dialect{
+		Placeholder: func(int) string { return "?" },
+		ILike:       "LIKE",
+		Time:        func(p string) string { return "strftime('%Y-%m-%d %H:%M:%f', " + p + ")" },
+	}
+)
+
+/*Колонки ссылки в порядке полей sqlc-модели Link*/
+const LinkColumns = "id, original_url, short_name, created_at, expires_at, max_visits, visit_count, password_hash, redirect_type, deleted_at, owner_id"
+
+/*Построитель запроса с нумерацией параметров*/
+type builder struct {
+	d    Dialect
+	sb   strings.Builder
+	args []any
+}
+
+/*Метод добавления параметра и получения его плейсхолдера*/
+func (b *builder) arg(v any) string {
+	b.args = append(b.args, v)
+	return b.d.Placeholder(len(b.args))
+}
+
+/*Метод построения запроса списка ссылок с фильтром, сортировкой и range (rng == nil — без LIMIT)*/
+func SelectLinks(d Dialect, q link.Query, rng *link.Range) (string, []any) {
+	b := &builder{d: d}
+
+	b.sb.WriteString("SELECT " + LinkColumns + " FROM links")
+	b.where(q.Filter)
+	b.orderBy(q.Sort)
+
+	if rng != nil {
+		b.sb.WriteString(" LIMIT " + b.arg(rng.End-rng.Start+1) + " OFFSET " + b.arg(rng.Start))
+	}
+
+	return b.sb.String(), b.args
+}
+
+/*Метод построения запроса страницы ссылок с фильтром после курсора (keyset-пагинация по id)*/
+func SelectLinksAfter(d Dialect, f link.Filter, cur *link.Cursor) (string, []any) {
+	b := &builder{d: d}
+
+	b.sb.WriteString("SELECT " + LinkColumns + " FROM links")
+	b.where(f)
+	b.sb.WriteString(" AND id > " + b.arg(cur.AfterID) + " ORDER BY id LIMIT " + b.arg(cur.Limit))
+
+	return b.sb.String(), b.args
+}
+
+/*Метод построения запроса количества ссылок по фильтру*/
+func CountLinks(d Dialect, f link.Filter) (string, []any) {
+	b := &builder{d: d}
+
+	b.sb.WriteString("SELECT COUNT(*) FROM links")
+	b.where(f)
+
+	return b.sb.String(), b.args
+}
+
+/*Метод добавления условий фильтра*/
+func (b *builder) where(f link.Filter) {
+	conds := []string{"deleted_at IS NULL"}
+	if f.Trashed {
+		conds[0] = "deleted_at IS NOT NULL"
+	}
+
+	if f.Q != "" {
+		// параметр передаётся дважды: в SQLite плейсхолдер «?» нельзя сослать повторно
+		pattern := "%" + EscapeLike(f.Q) + "%"
+		conds = append(conds, "(original_url "+b.d.ILike+" "+b.arg(pattern)+" ESCAPE '\\' OR short_name "+b.d.ILike+" "+b.arg(pattern)+" ESCAPE '\\')")
+	}
+
+	if f.ShortName != "" {
+		conds = append(conds, "short_name = "+b.arg(f.ShortName))
+	}
+
+	if f.OwnerID != 0 {
+		conds = append(conds, "owner_id = "+b.arg(f.OwnerID))
+	}
+
+	if f.IDs != nil {
+		if len(f.IDs) == 0 {
+			conds = append(conds, "1 = 0")
+		} else {
+			placeholders := make([]string, 0, len(f.IDs))
+			for _, id := range f.IDs {
+				placeholders = append(placeholders, b.arg(id))
+			}
+			conds = append(conds, "id IN ("+strings.Join(placeholders, ", ")+")")
+		}
+	}
+
+	b.sb.WriteString(" WHERE " + strings.Join(conds, " AND "))
+}
+
+/*Метод добавления сортировки (id вторым ключом — для стабильного порядка страниц)*/
+func (b *builder) orderBy(s link.Sort) {
+	dir := " ASC"
+	if s.Desc {
+		dir = " DESC"
+	}
+
+	column, ok := link.SortColumns[s.Field]
+	if !ok || column == "id" {
+		b.sb.WriteString(" ORDER BY id" + dir)
+		return
+	}
+
+	b.sb.WriteString(" ORDER BY " + column + dir + " NULLS LAST, id" + dir)
+}
+
+/*Метод экранирования спецсимволов LIKE (шаблон используется с ESCAPE)*/
+func EscapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
@@ -0,0 +1,28 @@
+package apikey
+
+import "time"
+
+/*DTO ключа для ответа API.*/
+type APIKeyResponse struct {
+	ID         int64      `json:"id"`           /*Идентификатор ключа*/
+	Name       string     `json:"name"`         /*Название ключа*/
+	Prefix     string     `json:"prefix"`       /*Начало ключа*/
+	Scopes     []string   `json:"scopes"`       /*Разрешения ключа*/
+	CreatedAt  time.Time  `json:"created_at"`   /*Дата создания*/
+	LastUsedAt *time.Time `json:"last_used_at"` /*Дата последнего использования*/
+	RevokedAt  *time.Time `json:"revoked_at"`   /*Дата отзыва*/
+	OwnerID    *int64     `json:"owner_id"`     /*Пользователь, от имени которого действует ключ (null — ключ не принимается)*/
+}
+
+/*DTO созданного ключа для ответа API (ключ отдаётся один раз).*/
+type CreatedAPIKeyResponse struct {
+	APIKeyResponse
+	Key string `json:"key"` /*Ключ целиком*/
+}
+
+/*DTO для создания ключа.*/
+type CreateAPIKeyRequest struct {
+	Name    string   `json:"name" binding:"required,max=100"`    /*Название ключа*/
+	Scopes  []string `json:"scopes" binding:"required,min=1"`    /*Разрешения: links:read, links:write, visits:read*/
+	OwnerID *int64   `json:"owner_id" binding:"omitempty,min=1"` /*Пользователь, чьи ссылки видит ключ (по умолчанию — создающий ключ)*/
+}
@@ -0,0 +1,104 @@
+package apikey
+
+import (
+	"net/http"
+	"strconv"
+
+	apikeyusecase "link-service/src/usecase/apikey"
+
+	"github.com/gin-gonic/gin"
+)
+
+/*Обработчик управления API ключами*/
+type Handler struct {
+	useCase apikeyusecase.UseCase
+}
+
+/*Метод создания нового обработчика*/
+func NewHandler(useCase apikeyusecase.UseCase) *Handler {
+	return &Handler{useCase: useCase}
+}
+
+/*Метод получения списка ключей*/
+func (h *Handler) List(c *gin.Context) {
+	keys, err := h.useCase.List(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+		return
+	}
+
+	response := make([]APIKeyResponse, 0, len(keys))
+	for _, k := range keys {
+		response = append(response, mapToResponse(k))
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+/*Метод создания ключа*/
+func (h *Handler) Create(c *gin.Context) {
+	var req CreateAPIKeyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid body"})
+		return
+	}
+
+	res, err := h.useCase.Create(c.Request.Context(), apikeyusecase.CreateInput{
+		Name:    req.Name,
+		Scopes:  req.Scopes,
+		OwnerID: req.OwnerID,
+	})
+	if err != nil {
+		if err == apikeyusecase.ErrInvalidInput {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid name or scopes"})
+			return
+		}
+		if err == apikeyusecase.ErrInvalidOwner {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "owner_id must be an existing user"})
+			return
+		}
+
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, CreatedAPIKeyResponse{
+		APIKeyResponse: mapToResponse(res.APIKeyDTO),
+		Key:            res.Key,
+	})
+}
+
+/*Метод отзыва ключа*/
+func (h *Handler) Revoke(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	if err := h.useCase.Revoke(c.Request.Context(), id); err != nil {
+		if err == apikeyusecase.ErrNotFound {
+			c.JSON(http.StatusNotFound, gin.H{"error": "not found"})
+			return
+		}
+
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+/*Метод преобразования из apikeyusecase.APIKeyDTO в APIKeyResponse*/
+func mapToResponse(k apikeyusecase.APIKeyDTO) APIKeyResponse {
+	return APIKeyResponse{
+		ID:         k.ID,
+		Name:       k.Name,
+		Prefix:     k.Prefix,
+		Scopes:     k.Scopes,
+		CreatedAt:  k.CreatedAt,
+		LastUsedAt: k.LastUsedAt,
+		RevokedAt:  k.RevokedAt,
+		OwnerID:    k.OwnerID,
+	}
+}
@@ -0,0 +1,16 @@
+package apikey
+
+import (
+	"link-service/src/interface/http/auth"
+
+	"github.com/gin-gonic/gin"
+)
+
+/*Метод регистрации маршрутов (только для административного ключа)*/
+func RegisterRoutes(router *gin.RouterGroup, h *Handler) {
+	keys := router.Group("/api_keys", auth.RequireAdmin())
+
+	keys.GET("", h.List)          /*Маршрут для получения списка ключей*/
+	keys.POST("", h.Create)       /*Маршрут для создания ключа*/
+	keys.DELETE("/:id", h.Revoke) /*Маршрут для отзыва ключа*/
+}
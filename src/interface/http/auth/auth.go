@@ -0,0 +1,91 @@
+package auth
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"link-service/src/domain/link"
+	apikeyusecase "link-service/src/usecase/apikey"
+
+	"github.com/gin-gonic/gin"
+)
+
+/*Ключ владельца ключа в gin.Context*/
+const principalKey = "auth.principal"
+
+/*Middleware: проверяет ключ или токен сессии из Authorization: Bearer и кладёт владельца и его доступ к ссылкам в контекст (401 — ключ не передан или не действует)*/
+func Middleware(uc apikeyusecase.UseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		token, ok := BearerToken(c.GetHeader("Authorization"))
+		if !ok {
+			unauthorized(c)
+			return
+		}
+
+		p, err := uc.Authenticate(c.Request.Context(), token)
+		if err != nil {
+			if errors.Is(err, apikeyusecase.ErrUnauthorized) {
+				unauthorized(c)
+				return
+			}
+
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+			return
+		}
+
+		c.Set(principalKey, p)
+		ctx := link.WithActor(c.Request.Context(), p.Actor())
+		c.Request = c.Request.WithContext(link.WithAccess(ctx, p.Access()))
+
+		c.Next()
+	}
+}
+
+/*Middleware: пропускает запрос, только если у ключа есть разрешение scope (403 — нет)*/
+func Require(scope string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !PrincipalFrom(c).Has(scope) {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "api key has no " + scope + " scope"})
+			return
+		}
+
+		c.Next()
+	}
+}
+
+/*Middleware: пропускает только административный ключ из конфигурации и администраторов (403 — другой ключ или пользователь)*/
+func RequireAdmin() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !PrincipalFrom(c).Admin {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "admin access required"})
+			return
+		}
+
+		c.Next()
+	}
+}
+
+/*Метод получения владельца ключа текущего запроса (пустой — Middleware не выполнялся)*/
+func PrincipalFrom(c *gin.Context) apikeyusecase.Principal {
+	p, _ := c.Get(principalKey)
+	res, _ := p.(apikeyusecase.Principal)
+	return res
+}
+
+/*Метод извлечения ключа из заголовка Authorization: Bearer <ключ>*/
+func BearerToken(header string) (string, bool) {
+	scheme, token, ok := strings.Cut(strings.TrimSpace(header), " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return "", false
+	}
+
+	token = strings.TrimSpace(token)
+	return token, token != ""
+}
+
+/*Метод ответа 401 с подсказкой схемы аутентификации*/
+func unauthorized(c *gin.Context) {
+	c.Header("WWW-Authenticate", `Bearer realm="api"`)
+	c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+}
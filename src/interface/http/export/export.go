@@ -0,0 +1,144 @@
+package export
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+/*Формат выгрузки*/
+type Format string
+
+const (
+	FormatCSV    Format = "csv"    /*CSV с заголовком*/
+	FormatNDJSON Format = "ndjson" /*JSON-объект на строку*/
+)
+
+/*Количество строк между сбросами буфера клиенту*/
+const flushEvery = 500
+
+/*Метод разбора параметра format (пустая строка — csv)*/
+func ParseFormat(s string) (Format, error) {
+	switch Format(s) {
+	case "", FormatCSV:
+		return FormatCSV, nil
+	case FormatNDJSON:
+		return FormatNDJSON, nil
+	default:
+		return "", errors.New("invalid format (csv|ndjson)")
+	}
+}
+
+/*Потоковая запись выгрузки в ответ: заголовки отправляются с первой строкой, чтобы до неё ещё можно было ответить ошибкой*/
+type Writer struct {
+	c       *gin.Context
+	format  Format
+	name    string   /*Имя файла без расширения*/
+	columns []string /*Заголовок CSV*/
+	csv     *csv.Writer
+	json    *json.Encoder
+	rows    int
+	started bool
+}
+
+/*Метод создания записи выгрузки name.<format> с колонками CSV columns*/
+func NewWriter(c *gin.Context, format Format, name string, columns []string) *Writer {
+	return &Writer{c: c, format: format, name: name, columns: columns}
+}
+
+/*Метод записи строки: v — для ndjson, record — значения колонок для csv*/
+func (w *Writer) Write(v any, record []string) error {
+	if err := w.start(); err != nil {
+		return err
+	}
+
+	var err error
+	if w.format == FormatCSV {
+		err = w.csv.Write(escapeFormulas(record))
+	} else {
+		err = w.json.Encode(v)
+	}
+	if err != nil {
+		return err
+	}
+
+	w.rows++
+	if w.rows%flushEvery == 0 {
+		return w.flush()
+	}
+
+	return nil
+}
+
+/*Метод завершения выгрузки (пустая выгрузка — только заголовок CSV)*/
+func (w *Writer) Close() error {
+	if err := w.start(); err != nil {
+		return err
+	}
+
+	return w.flush()
+}
+
+/*Метод завершения выгрузки с ошибкой: до первой строки — 500, после — обрыв ответа (статус уже отправлен)*/
+func (w *Writer) Fail(err error) {
+	if !w.started {
+		w.c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+		return
+	}
+
+	_ = w.c.Error(err)
+	w.c.Abort()
+}
+
+/*Метод отправки заголовков ответа и заголовка CSV*/
+func (w *Writer) start() error {
+	if w.started {
+		return nil
+	}
+	w.started = true
+
+	contentType := "text/csv; charset=utf-8"
+	if w.format == FormatNDJSON {
+		contentType = "application/x-ndjson"
+	}
+
+	w.c.Header("Content-Type", contentType)
+	w.c.Header("Content-Disposition", `attachment; filename="`+w.name+"."+string(w.format)+`"`)
+	w.c.Status(http.StatusOK)
+
+	if w.format == FormatNDJSON {
+		w.json = json.NewEncoder(w.c.Writer)
+		return nil
+	}
+
+	w.csv = csv.NewWriter(w.c.Writer)
+	return w.csv.Write(w.columns)
+}
+
+/*Метод сброса буфера CSV и ответа клиенту*/
+func (w *Writer) flush() error {
+	if w.csv != nil {
+		w.csv.Flush()
+		if err := w.csv.Error(); err != nil {
+			return err
+		}
+	}
+
+	w.c.Writer.Flush()
+	return nil
+}
+
+/*Метод экранирования ячеек, которые таблицы приняли бы за формулу (user_agent, referer и original_url приходят от клиентов)*/
+func escapeFormulas(record []string) []string {
+	for i, v := range record {
+		if v != "" && strings.ContainsRune("=+-@\t\r", rune(v[0])) {
+			record[i] = "'" + v
+		}
+	}
+
+	return record
+}
@@ -0,0 +1,12 @@
+package export
+
+import (
+	"testing"
+
+	"github.com/go-playground/assert/v2"
+)
+
+func TestEscapeFormulas(t *testing.T) {
+	record := escapeFormulas([]string{"1", "=HYPERLINK(\"http://evil\")", "+1", "-2", "@SUM(A1)", "https://example.com", ""})
+	assert.Equal(t, []string{"1", "'=HYPERLINK(\"http://evil\")", "'+1", "'-2", "'@SUM(A1)", "https://example.com", ""}, record)
+}
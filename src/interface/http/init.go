@@ -3,12 +3,22 @@ package httpinterface
 import (
 	"net/http"
 
+	domainratelimit "link-service/src/domain/ratelimit"
+
+	"link-service/src/interface/http/apikey"
+	"link-service/src/interface/http/auth"
 	"link-service/src/interface/http/link"
 	"link-service/src/interface/http/linkvisit"
 	"link-service/src/interface/http/ping"
+	"link-service/src/interface/http/ratelimit"
 	"link-service/src/interface/http/redirect"
+	"link-service/src/interface/http/requestid"
+	"link-service/src/interface/http/stats"
+	"link-service/src/interface/http/user"
+	apikeyusecase "link-service/src/usecase/apikey"
 	linkusecase "link-service/src/usecase/link"
 	linkvisitusecase "link-service/src/usecase/linkvisit"
+	userusecase "link-service/src/usecase/user"
 
 	"github.com/gin-gonic/gin"
 )
@@ -17,22 +27,53 @@ import (
 type Deps struct {
 	Link      linkusecase.UseCase
 	LinkVisit linkvisitusecase.UseCase
+	APIKeys   apikeyusecase.UseCase      /*Проверка API ключей и сессий для /api*/
+	Users     userusecase.UseCase        /*Вход и управление пользователями*/
+	Stats     map[string]stats.Source    /*Счётчики для GET /api/stats*/
+	Bots      redirect.BotPolicy         /*Распознавание ботов при редиректе*/
+	Retention linkvisitusecase.Retention /*Очистка устаревших посещений (nil — срок хранения не задан)*/
+	RateLimit RateLimits                 /*Ограничение частоты запросов (пусто — выключено)*/
+}
+
+/*Ограничение частоты запросов*/
+type RateLimits struct {
+	Store      domainratelimit.Store  /*Хранилище корзин токенов (nil — ограничение выключено)*/
+	Redirect   domainratelimit.Policy /*Политика для /r/:code*/
+	CreateLink domainratelimit.Policy /*Политика для POST /api/links и /api/links/import*/
+	Unlock     domainratelimit.Policy /*Политика попыток ввода пароля POST /r/:code (по IP и ссылке)*/
+	Login      domainratelimit.Policy /*Политика попыток входа POST /api/auth/login (по IP клиента)*/
 }
 
 /*Метод инициализации маршрутов*/
 func InitRoutes(router *gin.Engine, deps Deps) {
+	router.Use(requestid.Middleware())
+
 	ping.RegisterRoutes(router)
 
-	redirectHandler := redirect.NewHandler(deps.Link, deps.LinkVisit)
-	router.GET("/r/:code", redirectHandler.Redirect)
+	redirectHandler := redirect.NewHandler(deps.Link, deps.LinkVisit, deps.Bots)
+	redirectLimit := ratelimit.Middleware(deps.RateLimit.Store, "redirect", deps.RateLimit.Redirect)
+	router.GET("/r/:code", redirectLimit, redirectHandler.Redirect)
+	unlockLimit := ratelimit.MiddlewareByKey(deps.RateLimit.Store, "unlock", deps.RateLimit.Unlock, ratelimit.UnlockKey)
+	router.POST("/r/:code", redirectLimit, unlockLimit, redirectHandler.Unlock)
+
+	/*Вход — единственный маршрут /api без ключа: он и выдаёт токен сессии*/
+	userHandler := user.NewHandler(deps.Users)
+	user.RegisterPublicRoutes(router.Group("/api"), userHandler, ratelimit.Middleware(deps.RateLimit.Store, "login", deps.RateLimit.Login))
+
+	/*Весь остальной /api доступен только с API ключом или токеном сессии; /r/:code и /ping остаются публичными*/
+	apiRoute := router.Group("/api", auth.Middleware(deps.APIKeys))
+	user.RegisterRoutes(apiRoute, userHandler)
+	apiKeyHandler := apikey.NewHandler(deps.APIKeys)
+	apikey.RegisterRoutes(apiRoute, apiKeyHandler)
 
-	apiRoute := router.Group("/api")
 	linkHandler := link.NewHandler(deps.Link)
-	link.RegisterRoutes(apiRoute, linkHandler)
+	link.RegisterRoutes(apiRoute, linkHandler, ratelimit.Middleware(deps.RateLimit.Store, "create_link", deps.RateLimit.CreateLink))
 
-	linkVisitHandler := linkvisit.NewHandler(deps.LinkVisit)
+	linkVisitHandler := linkvisit.NewHandler(deps.LinkVisit, deps.Link, deps.Retention)
 	linkvisit.RegisterRoutes(apiRoute, linkVisitHandler)
 
+	stats.RegisterRoutes(apiRoute, deps.Stats)
+
 	/*Метод обработки не найденных маршрутов*/
 	router.NoRoute(func(c *gin.Context) {
 		c.JSON(http.StatusNotFound, gin.H{"error": "not found"})
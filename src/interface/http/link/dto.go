@@ -1,21 +1,56 @@
 package link
 
+import (
+	"time"
+
+	linkusecase "link-service/src/usecase/link"
+)
+
 /*DTO для ответа API.*/
 type LinkResponse struct {
-	ID          int64  `json:"id"`           /*Идентификатор ссылки*/
-	OriginalURL string `json:"original_url"` /*Исходная ссылка*/
-	ShortName   string `json:"short_name"`   /*Короткая ссылка*/
-	ShortURL    string `json:"short_url"`    /*Короткая ссылка*/
+	ID           int64      `json:"id"`                   /*Идентификатор ссылки*/
+	OriginalURL  string     `json:"original_url"`         /*Исходная ссылка*/
+	ShortName    string     `json:"short_name"`           /*Короткая ссылка*/
+	ShortURL     string     `json:"short_url"`            /*Короткая ссылка*/
+	ExpiresAt    *time.Time `json:"expires_at"`           /*Дата истечения срока действия*/
+	MaxVisits    *int       `json:"max_visits"`           /*Лимит посещений*/
+	HasPassword  bool       `json:"has_password"`         /*Ссылка защищена паролем*/
+	RedirectType int        `json:"redirect_type"`        /*HTTP статус редиректа*/
+	DeletedAt    *time.Time `json:"deleted_at,omitempty"` /*Дата перемещения в корзину*/
+	OwnerID      *int64     `json:"owner_id,omitempty"`   /*Владелец ссылки (не передаётся — без владельца)*/
+}
+
+/*DTO записи истории изменений для ответа API.*/
+type LinkEventResponse struct {
+	ID             int64     `json:"id"`                         /*Идентификатор записи*/
+	Type           string    `json:"type"`                       /*Тип изменения: created, updated, deleted, restored*/
+	OldOriginalURL string    `json:"old_original_url,omitempty"` /*Исходная ссылка до изменения*/
+	NewOriginalURL string    `json:"new_original_url,omitempty"` /*Исходная ссылка после изменения*/
+	OldShortName   string    `json:"old_short_name,omitempty"`   /*Короткая ссылка до изменения*/
+	NewShortName   string    `json:"new_short_name,omitempty"`   /*Короткая ссылка после изменения*/
+	Actor          string    `json:"actor,omitempty"`            /*Кто выполнил изменение*/
+	RequestID      string    `json:"request_id,omitempty"`       /*Идентификатор HTTP запроса*/
+	CreatedAt      time.Time `json:"created_at"`                 /*Дата изменения*/
 }
 
 /*DTO для создания ссылки.*/
 type CreateLinkRequest struct {
-	OriginalURL string `json:"original_url" binding:"required,url"`      /*Исходная ссылка*/
-	ShortName   string `json:"short_name" binding:"omitempty,min=3,max=32"` /*Короткая ссылка*/
+	OriginalURL  string     `json:"original_url" binding:"required,url"`                     /*Исходная ссылка*/
+	ShortName    string     `json:"short_name" binding:"omitempty,min=3,max=32"`             /*Короткая ссылка*/
+	ExpiresAt    *time.Time `json:"expires_at" binding:"omitempty,gt"`                       /*Дата истечения срока действия (в будущем)*/
+	MaxVisits    *int       `json:"max_visits" binding:"omitempty,min=1"`                    /*Лимит посещений (1 — одноразовая ссылка)*/
+	Password     string     `json:"password" binding:"omitempty,min=4,max=72"`               /*Пароль для открытия ссылки*/
+	RedirectType int        `json:"redirect_type" binding:"omitempty,oneof=301 302 307 308"` /*HTTP статус редиректа (по умолчанию 302)*/
+	OwnerID      *int64     `json:"owner_id" binding:"omitempty,min=1"`                      /*Владелец (только для администратора; по умолчанию — текущий пользователь)*/
 }
 
 /*DTO для обновления ссылки.*/
 type UpdateLinkRequest struct {
-	OriginalURL string `json:"original_url" binding:"required,url"`      /*Исходная ссылка*/
-	ShortName   string `json:"short_name" binding:"omitempty,min=3,max=32"` /*Короткая ссылка*/
+	OriginalURL  string                          `json:"original_url" binding:"omitempty,url"`                    /*Исходная ссылка (не передана — не менять)*/
+	ShortName    string                          `json:"short_name" binding:"omitempty,min=3,max=32"`             /*Короткая ссылка (не передана — не менять)*/
+	ExpiresAt    linkusecase.Optional[time.Time] `json:"expires_at"`                                              /*Дата истечения срока действия (не передана — не менять, null — бессрочная)*/
+	MaxVisits    linkusecase.Optional[int]       `json:"max_visits"`                                              /*Лимит посещений (не передан — не менять, null — без лимита, иначе не меньше 1)*/
+	Password     *string                         `json:"password" binding:"omitempty,eq=|min=4,max=72"`           /*Пароль (не передан — не менять, "" — снять пароль)*/
+	RedirectType int                             `json:"redirect_type" binding:"omitempty,oneof=301 302 307 308"` /*HTTP статус редиректа (не передан — не менять)*/
+	OwnerID      *int64                          `json:"owner_id" binding:"omitempty,min=1"`                      /*Новый владелец (только для администратора; не передан — не менять)*/
 }
@@ -0,0 +1,63 @@
+package link
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"link-service/src/interface/http/export"
+	linkusecase "link-service/src/usecase/link"
+
+	"github.com/gin-gonic/gin"
+)
+
+/*Колонки CSV-выгрузки ссылок (совпадают с полями LinkResponse)*/
+var exportColumns = []string{"id", "original_url", "short_name", "short_url", "expires_at", "max_visits", "has_password", "redirect_type"}
+
+/*Метод потоковой выгрузки ссылок (format=csv|ndjson) с теми же фильтром и сортировкой, что у списка*/
+func (h *Handler) Export(c *gin.Context) {
+	format, err := export.ParseFormat(c.Query("format"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	query, err := parseQuery(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	w := export.NewWriter(c, format, "links", exportColumns)
+	err = h.useCase.Each(c.Request.Context(), query, func(l linkusecase.LinkDTO) error {
+		return w.Write(mapToResponse(l), exportRecord(l))
+	})
+	if err == nil {
+		err = w.Close()
+	}
+	if err != nil {
+		w.Fail(err)
+	}
+}
+
+/*Метод преобразования ссылки в строку CSV (пустые значения — для null)*/
+func exportRecord(l linkusecase.LinkDTO) []string {
+	var expiresAt, maxVisits string
+	if l.ExpiresAt != nil {
+		expiresAt = l.ExpiresAt.UTC().Format(time.RFC3339)
+	}
+	if l.MaxVisits != nil {
+		maxVisits = strconv.Itoa(*l.MaxVisits)
+	}
+
+	return []string{
+		strconv.FormatInt(l.ID, 10),
+		l.OriginalURL,
+		l.ShortName,
+		l.ShortURL,
+		expiresAt,
+		maxVisits,
+		strconv.FormatBool(l.HasPassword),
+		strconv.Itoa(l.RedirectType),
+	}
+}
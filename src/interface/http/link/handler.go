@@ -9,6 +9,7 @@ import (
 	"strconv"
 
 	"link-service/src/domain/link"
+	"link-service/src/interface/http/pagination"
 	linkusecase "link-service/src/usecase/link"
 
 	"github.com/gin-gonic/gin"
@@ -25,12 +26,39 @@ func NewHandler(useCase linkusecase.UseCase) *Handler {
 	return &Handler{useCase: useCase}
 }
 
-/*Метод получения списка ссылок*/
+/*Метод получения списка ссылок (range — для react-admin, cursor — keyset-пагинация)*/
 func (h *Handler) List(c *gin.Context) {
+	if cursor, ok := c.GetQuery("cursor"); ok {
+		h.listAfter(c, cursor)
+		return
+	}
+
+	h.list(c, false)
+}
+
+/*Метод получения списка ссылок в корзине (фильтры, сортировка и range как у List)*/
+func (h *Handler) Trash(c *gin.Context) {
+	if _, ok := c.GetQuery("cursor"); ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "cursor is not supported for trash"})
+		return
+	}
+
+	h.list(c, true)
+}
+
+/*Метод получения страницы ссылок по range (trashed — только ссылки в корзине)*/
+func (h *Handler) list(c *gin.Context, trashed bool) {
 	var rng *link.Range
 	var res []linkusecase.LinkDTO
 	var err error
 
+	query, err := parseQuery(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	query.Filter.Trashed = trashed
+
 	rngString := c.Query("range")
 	if rngString != "" {
 		rng, err = link.ParseRange(rngString)
@@ -40,13 +68,13 @@ func (h *Handler) List(c *gin.Context) {
 			return
 		}
 
-		res, err = h.useCase.ListWithRange(c.Request.Context(), rng)
+		res, err = h.useCase.ListWithRange(c.Request.Context(), rng, query)
 		if err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
 			return
 		}
 	} else {
-		res, err = h.useCase.List(c.Request.Context())
+		res, err = h.useCase.List(c.Request.Context(), query)
 		if err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
 			return
@@ -58,7 +86,7 @@ func (h *Handler) List(c *gin.Context) {
 		response = append(response, mapToResponse(l))
 	}
 
-	total, err := h.useCase.Count(c.Request.Context())
+	total, err := h.useCase.Count(c.Request.Context(), query.Filter)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
 		return
@@ -77,6 +105,44 @@ func (h *Handler) List(c *gin.Context) {
 	c.JSON(http.StatusOK, response)
 }
 
+/*Метод разбора параметров filter и sort в формате react-admin*/
+func parseQuery(c *gin.Context) (link.Query, error) {
+	filter, err := link.ParseFilter(c.Query("filter"))
+	if err != nil {
+		return link.Query{}, err
+	}
+
+	sort, err := link.ParseSort(c.Query("sort"))
+	if err != nil {
+		return link.Query{}, err
+	}
+
+	return link.Query{Filter: filter, Sort: sort}, nil
+}
+
+/*Метод получения страницы ссылок по курсору (без Content-Range: общее количество не считается)*/
+func (h *Handler) listAfter(c *gin.Context, cursor string) {
+	cur, err := link.ParseCursor(cursor, c.Query("limit"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	res, next, err := h.useCase.ListAfter(c.Request.Context(), cur)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+		return
+	}
+
+	response := make([]LinkResponse, 0, len(res))
+	for _, l := range res {
+		response = append(response, mapToResponse(l))
+	}
+
+	pagination.SetNextLink(c, next)
+	c.JSON(http.StatusOK, response)
+}
+
 /*Метод получения ссылки по идентификатору*/
 func (h *Handler) Get(c *gin.Context) {
 	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
@@ -107,8 +173,13 @@ func (h *Handler) Create(c *gin.Context) {
 	}
 
 	res, err := h.useCase.Create(c.Request.Context(), linkusecase.CreateInput{
-		OriginalURL: req.OriginalURL,
-		ShortName:   req.ShortName,
+		OriginalURL:  req.OriginalURL,
+		ShortName:    req.ShortName,
+		ExpiresAt:    req.ExpiresAt,
+		MaxVisits:    req.MaxVisits,
+		Password:     req.Password,
+		RedirectType: req.RedirectType,
+		OwnerID:      req.OwnerID,
 	})
 
 	if err != nil {
@@ -123,6 +194,12 @@ func (h *Handler) Create(c *gin.Context) {
 				"short_name": "short name already in use",
 			}})
 			return
+		case linkusecase.ErrForbidden:
+			c.JSON(http.StatusForbidden, gin.H{"error": "only admins can set owner_id"})
+			return
+		case linkusecase.ErrInvalidPasswordLength:
+			c.JSON(http.StatusBadRequest, gin.H{"error": "password must be 4 to 72 bytes"})
+			return
 		default:
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
 			return
@@ -148,9 +225,22 @@ func (h *Handler) Update(c *gin.Context) {
 		return
 	}
 
+	if v := req.MaxVisits.Value; v != nil && *v < 1 {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"errors": gin.H{
+			"max_visits": "max_visits must be at least 1",
+		}})
+
+		return
+	}
+
 	res, err := h.useCase.Update(c.Request.Context(), id, linkusecase.UpdateInput{
-		OriginalURL: req.OriginalURL,
-		ShortName:   req.ShortName,
+		OriginalURL:  req.OriginalURL,
+		ShortName:    req.ShortName,
+		ExpiresAt:    req.ExpiresAt,
+		MaxVisits:    req.MaxVisits,
+		Password:     req.Password,
+		RedirectType: req.RedirectType,
+		OwnerID:      req.OwnerID,
 	})
 	if err != nil {
 		switch err {
@@ -169,6 +259,14 @@ func (h *Handler) Update(c *gin.Context) {
 				"short_name": "short name already in use",
 			}})
 
+			return
+		case linkusecase.ErrForbidden:
+			c.JSON(http.StatusForbidden, gin.H{"error": "only admins can set owner_id"})
+
+			return
+		case linkusecase.ErrInvalidPasswordLength:
+			c.JSON(http.StatusBadRequest, gin.H{"error": "password must be 4 to 72 bytes"})
+
 			return
 		default:
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
@@ -204,13 +302,84 @@ func (h *Handler) Delete(c *gin.Context) {
 	c.Status(http.StatusNoContent)
 }
 
+/*Метод восстановления ссылки из корзины*/
+func (h *Handler) Restore(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+
+		return
+	}
+
+	res, err := h.useCase.Restore(c.Request.Context(), id)
+	if err != nil {
+		if err == linkusecase.ErrNotFound {
+			c.JSON(http.StatusNotFound, gin.H{"error": "not found"})
+
+			return
+		}
+
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+
+		return
+	}
+
+	c.JSON(http.StatusOK, mapToResponse(res))
+}
+
+/*Метод получения истории изменений ссылки (от старых к новым)*/
+func (h *Handler) History(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+
+		return
+	}
+
+	events, err := h.useCase.History(c.Request.Context(), id)
+	if err != nil {
+		if err == linkusecase.ErrNotFound {
+			c.JSON(http.StatusNotFound, gin.H{"error": "not found"})
+
+			return
+		}
+
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+
+		return
+	}
+
+	response := make([]LinkEventResponse, 0, len(events))
+	for _, e := range events {
+		response = append(response, LinkEventResponse{
+			ID:             e.ID,
+			Type:           e.Type,
+			OldOriginalURL: e.OldOriginalURL,
+			NewOriginalURL: e.NewOriginalURL,
+			OldShortName:   e.OldShortName,
+			NewShortName:   e.NewShortName,
+			Actor:          e.Actor,
+			RequestID:      e.RequestID,
+			CreatedAt:      e.CreatedAt,
+		})
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
 /*Метод преобразования из linkusecase.LinkDTO в LinkResponse*/
 func mapToResponse(l linkusecase.LinkDTO) LinkResponse {
 	return LinkResponse{
-		ID:          l.ID,
-		OriginalURL: l.OriginalURL,
-		ShortName:   l.ShortName,
-		ShortURL:    l.ShortURL,
+		ID:           l.ID,
+		OriginalURL:  l.OriginalURL,
+		ShortName:    l.ShortName,
+		ShortURL:     l.ShortURL,
+		ExpiresAt:    l.ExpiresAt,
+		MaxVisits:    l.MaxVisits,
+		HasPassword:  l.HasPassword,
+		RedirectType: l.RedirectType,
+		DeletedAt:    l.DeletedAt,
+		OwnerID:      l.OwnerID,
 	}
 }
 
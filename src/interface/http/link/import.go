@@ -0,0 +1,225 @@
+package link
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	linkusecase "link-service/src/usecase/link"
+
+	"github.com/gin-gonic/gin"
+)
+
+/*Тело запроса импорта не разобрано*/
+var errInvalidImport = errors.New("invalid request")
+
+/*Максимальный размер тела запроса импорта: с запасом на MaxImportRows строк с длинными URL*/
+const maxImportBodyBytes = 32 << 20
+
+/*Строка импорта в JSON*/
+type ImportLinkRequest struct {
+	OriginalURL string `json:"original_url"` /*Исходная ссылка*/
+	ShortName   string `json:"short_name"`   /*Короткая ссылка (пустая — сгенерировать)*/
+}
+
+/*Результат импорта строки*/
+type ImportRowResponse struct {
+	Row    int           `json:"row"`             /*Номер строки (с 1, без заголовка CSV)*/
+	Status string        `json:"status"`          /*created, conflict, invalid или skipped*/
+	Error  string        `json:"error,omitempty"` /*Причина для conflict и invalid*/
+	Link   *LinkResponse `json:"link,omitempty"`  /*Созданная ссылка*/
+}
+
+/*Отчёт об импорте*/
+type ImportResponse struct {
+	Created   int                 `json:"created"`   /*Создано ссылок*/
+	Conflicts int                 `json:"conflicts"` /*Строк с занятым short_name*/
+	Invalid   int                 `json:"invalid"`   /*Некорректных строк*/
+	Aborted   bool                `json:"aborted"`   /*all_or_nothing: ничего не создано*/
+	Rows      []ImportRowResponse `json:"rows"`      /*Результаты по строкам*/
+}
+
+/*Метод импорта ссылок из CSV (Content-Type: text/csv) или JSON-массива; all_or_nothing=true — при любой ошибке ничего не создаётся*/
+func (h *Handler) Import(c *gin.Context) {
+	allOrNothing := false
+	if v := c.Query("all_or_nothing"); v != "" {
+		var err error
+		if allOrNothing, err = strconv.ParseBool(v); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid all_or_nothing"})
+			return
+		}
+	}
+
+	var (
+		rows []linkusecase.ImportRow
+		err  error
+	)
+	body := http.MaxBytesReader(c.Writer, c.Request.Body, maxImportBodyBytes)
+	if mediaType, _, _ := mime.ParseMediaType(c.ContentType()); mediaType == "text/csv" {
+		rows, err = readImportCSV(body)
+	} else {
+		rows, err = readImportJSON(body)
+	}
+	if err != nil {
+		writeImportError(c, err)
+		return
+	}
+
+	res, err := h.useCase.Import(c.Request.Context(), rows, allOrNothing)
+	if err != nil {
+		writeImportError(c, err)
+		return
+	}
+
+	status := http.StatusOK
+	if res.Aborted {
+		status = http.StatusUnprocessableEntity
+	}
+
+	c.JSON(status, mapImportResponse(res))
+}
+
+/*Метод записи ошибки импорта*/
+func writeImportError(c *gin.Context, err error) {
+	var tooLarge *http.MaxBytesError
+
+	switch {
+	case errors.As(err, &tooLarge):
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "request body too large (max " + strconv.FormatInt(tooLarge.Limit, 10) + " bytes)"})
+	case errors.Is(err, linkusecase.ErrTooManyRows):
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "too many rows (max " + strconv.Itoa(linkusecase.MaxImportRows) + ")"})
+	case errors.Is(err, errInvalidImport):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+	}
+}
+
+/*Метод чтения строк импорта из JSON-массива: массив читается поэлементно, лишние строки отклоняются до чтения остального тела*/
+func readImportJSON(r io.Reader) ([]linkusecase.ImportRow, error) {
+	dec := json.NewDecoder(r)
+	if t, err := dec.Token(); err != nil || t != json.Delim('[') {
+		return nil, importReadError(err)
+	}
+
+	var rows []linkusecase.ImportRow
+	for dec.More() {
+		if len(rows) == linkusecase.MaxImportRows {
+			return nil, linkusecase.ErrTooManyRows
+		}
+
+		var v ImportLinkRequest
+		if err := dec.Decode(&v); err != nil {
+			return nil, importReadError(err)
+		}
+		rows = append(rows, linkusecase.ImportRow{OriginalURL: v.OriginalURL, ShortName: v.ShortName})
+	}
+
+	if _, err := dec.Token(); err != nil {
+		return nil, importReadError(err)
+	}
+
+	return rows, nil
+}
+
+/*Метод преобразования ошибки чтения тела: превышение размера сохраняется, остальное — errInvalidImport*/
+func importReadError(err error) error {
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		return err
+	}
+
+	return errInvalidImport
+}
+
+/*Метод чтения строк импорта из CSV: с заголовком original_url,short_name (в любом порядке) или без него — original_url первой колонкой*/
+func readImportCSV(r io.Reader) ([]linkusecase.ImportRow, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	cr.TrimLeadingSpace = true
+
+	urlCol, nameCol := 0, 1
+	var rows []linkusecase.ImportRow
+
+	for first := true; ; first = false {
+		record, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			return rows, nil
+		}
+		if err != nil {
+			if err = importReadError(err); errors.Is(err, errInvalidImport) {
+				return nil, fmt.Errorf("%w: invalid csv", errInvalidImport)
+			}
+			return nil, err
+		}
+
+		if first && isImportHeader(record) {
+			urlCol, nameCol = -1, -1
+			for i, name := range record {
+				switch headerName(name) {
+				case "original_url":
+					urlCol = i
+				case "short_name":
+					nameCol = i
+				}
+			}
+			continue
+		}
+
+		if len(rows) == linkusecase.MaxImportRows {
+			return nil, linkusecase.ErrTooManyRows
+		}
+		rows = append(rows, linkusecase.ImportRow{OriginalURL: column(record, urlCol), ShortName: column(record, nameCol)})
+	}
+}
+
+/*Метод проверки, что первая строка CSV — заголовок*/
+func isImportHeader(record []string) bool {
+	for _, name := range record {
+		if headerName(name) == "original_url" {
+			return true
+		}
+	}
+	return false
+}
+
+/*Метод нормализации имени колонки (BOM в начале файла из Excel отбрасывается)*/
+func headerName(s string) string {
+	return strings.ToLower(strings.TrimSpace(strings.TrimPrefix(s, "\ufeff")))
+}
+
+/*Метод получения значения колонки (нет колонки — пустая строка)*/
+func column(record []string, i int) string {
+	if i < 0 || i >= len(record) {
+		return ""
+	}
+	return record[i]
+}
+
+/*Метод преобразования отчёта об импорте в ответ API*/
+func mapImportResponse(res linkusecase.ImportResult) ImportResponse {
+	out := ImportResponse{
+		Created:   res.Created,
+		Conflicts: res.Conflicts,
+		Invalid:   res.Invalid,
+		Aborted:   res.Aborted,
+		Rows:      make([]ImportRowResponse, 0, len(res.Rows)),
+	}
+
+	for _, r := range res.Rows {
+		row := ImportRowResponse{Row: r.Row, Status: string(r.Status), Error: r.Error}
+		if r.Link != nil {
+			l := mapToResponse(*r.Link)
+			row.Link = &l
+		}
+		out.Rows = append(out.Rows, row)
+	}
+
+	return out
+}
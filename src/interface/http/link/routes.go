@@ -1,14 +1,25 @@
 package link
 
 import (
+	"link-service/src/domain/apikey"
+	"link-service/src/interface/http/auth"
+
 	"github.com/gin-gonic/gin"
 )
 
-/*Метод регистрации маршрутов*/
-func RegisterRoutes(router *gin.RouterGroup, h *Handler) {
-	router.GET("/links", h.List)          /*Маршрут для получения списка ссылок*/
-	router.GET("/links/:id", h.Get)       /*Маршрут для получения ссылки по идентификатору*/
-	router.POST("/links", h.Create)       /*Маршрут для создания ссылки*/
-	router.PUT("/links/:id", h.Update)    /*Маршрут для обновления ссылки*/
-	router.DELETE("/links/:id", h.Delete) /*Маршрут для удаления ссылки*/
+/*Метод регистрации маршрутов (createLimit — ограничение частоты создания ссылок, импорт списывает токен за запрос)*/
+func RegisterRoutes(router *gin.RouterGroup, h *Handler, createLimit gin.HandlerFunc) {
+	read := router.Group("", auth.Require(apikey.ScopeLinksRead))
+	read.GET("/links", h.List)                /*Маршрут для получения списка ссылок*/
+	read.GET("/links/export", h.Export)       /*Маршрут для потоковой выгрузки ссылок (csv, ndjson)*/
+	read.GET("/links/trash", h.Trash)         /*Маршрут для получения списка ссылок в корзине*/
+	read.GET("/links/:id", h.Get)             /*Маршрут для получения ссылки по идентификатору*/
+	read.GET("/links/:id/history", h.History) /*Маршрут для получения истории изменений ссылки*/
+
+	write := router.Group("", auth.Require(apikey.ScopeLinksWrite))
+	write.POST("/links", createLimit, h.Create)        /*Маршрут для создания ссылки*/
+	write.POST("/links/import", createLimit, h.Import) /*Маршрут для пакетного импорта ссылок (csv, json)*/
+	write.POST("/links/:id/restore", h.Restore)        /*Маршрут для восстановления ссылки из корзины*/
+	write.PUT("/links/:id", h.Update)                  /*Маршрут для обновления ссылки*/
+	write.DELETE("/links/:id", h.Delete)               /*Маршрут для перемещения ссылки в корзину*/
 }
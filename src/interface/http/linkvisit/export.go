@@ -0,0 +1,65 @@
+package linkvisit
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"link-service/src/interface/http/export"
+	linkvisitusecase "link-service/src/usecase/linkvisit"
+
+	"github.com/gin-gonic/gin"
+)
+
+/*Колонки CSV-выгрузки посещений (совпадают с JSON-полями LinkVisitDTO)*/
+var exportColumns = []string{
+	"id", "link_id", "created_at", "ip", "user_agent", "referer", "status",
+	"browser", "browser_version", "os", "device", "is_bot", "country", "region", "city",
+}
+
+/*Метод потоковой выгрузки посещений (format=csv|ndjson) с тем же фильтром, что у списка*/
+func (h *Handler) Export(c *gin.Context) {
+	format, err := export.ParseFormat(c.Query("format"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	filter, err := parseFilter(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	w := export.NewWriter(c, format, "link_visits", exportColumns)
+	err = h.useCase.Each(c.Request.Context(), filter, func(v linkvisitusecase.LinkVisitDTO) error {
+		return w.Write(v, exportRecord(v))
+	})
+	if err == nil {
+		err = w.Close()
+	}
+	if err != nil {
+		w.Fail(err)
+	}
+}
+
+/*Метод преобразования посещения в строку CSV*/
+func exportRecord(v linkvisitusecase.LinkVisitDTO) []string {
+	return []string{
+		strconv.FormatInt(v.ID, 10),
+		strconv.FormatInt(v.LinkID, 10),
+		v.CreatedAt.UTC().Format(time.RFC3339Nano),
+		v.IP,
+		v.UserAgent,
+		v.Referer,
+		strconv.Itoa(v.Status),
+		v.Browser,
+		v.BrowserVersion,
+		v.OS,
+		v.Device,
+		strconv.FormatBool(v.IsBot),
+		v.Country,
+		v.Region,
+		v.City,
+	}
+}
@@ -1,10 +1,15 @@
 package linkvisit
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
+	"strconv"
 
 	"link-service/src/domain/link"
+	domain "link-service/src/domain/linkvisit"
+	"link-service/src/interface/http/pagination"
+	linkusecase "link-service/src/usecase/link"
 	linkvisitusecase "link-service/src/usecase/linkvisit"
 
 	"github.com/gin-gonic/gin"
@@ -12,16 +17,51 @@ import (
 
 /*Хендлер для работы с посещениями ссылок*/
 type Handler struct {
-	useCase linkvisitusecase.UseCase
+	useCase   linkvisitusecase.UseCase
+	links     linkusecase.UseCase        /*UseCase ссылок — для проверки существования ссылки*/
+	retention linkvisitusecase.Retention /*Очистка устаревших посещений (nil — срок хранения не задан)*/
 }
 
 /*Метод создания нового хендлера*/
-func NewHandler(useCase linkvisitusecase.UseCase) *Handler {
-	return &Handler{useCase: useCase}
+func NewHandler(useCase linkvisitusecase.UseCase, links linkusecase.UseCase, retention linkvisitusecase.Retention) *Handler {
+	return &Handler{useCase: useCase, links: links, retention: retention}
 }
 
-/*Метод получения списка посещений*/
+/*Метод получения списка посещений (range — для react-admin, cursor — keyset-пагинация)*/
 func (h *Handler) List(c *gin.Context) {
+	filter, err := parseFilter(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	h.list(c, filter)
+}
+
+/*Метод получения списка посещений одной ссылки*/
+func (h *Handler) ListByLink(c *gin.Context) {
+	id, ok := h.linkID(c)
+	if !ok {
+		return
+	}
+
+	filter, err := parseFilter(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	filter.LinkID = id
+
+	h.list(c, filter)
+}
+
+/*Метод получения страницы посещений по фильтру*/
+func (h *Handler) list(c *gin.Context, filter domain.Filter) {
+	if cursor, ok := c.GetQuery("cursor"); ok {
+		h.listAfter(c, cursor, filter)
+		return
+	}
+
 	rngString := c.Query("range")
 	var (
 		rng *link.Range
@@ -36,7 +76,7 @@ func (h *Handler) List(c *gin.Context) {
 			return
 		}
 
-		res, err = h.useCase.ListWithRange(c.Request.Context(), rng)
+		res, err = h.useCase.ListWithRange(c.Request.Context(), rng, filter)
 		if err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
 			return
@@ -44,14 +84,14 @@ func (h *Handler) List(c *gin.Context) {
 	} else {
 		// по умолчанию — первая страница
 		rng = &link.Range{Start: 0, End: 49}
-		res, err = h.useCase.ListWithRange(c.Request.Context(), rng)
+		res, err = h.useCase.ListWithRange(c.Request.Context(), rng, filter)
 		if err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
 			return
 		}
 	}
 
-	total, err := h.useCase.Count(c.Request.Context())
+	total, err := h.useCase.Count(c.Request.Context(), filter)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
 		return
@@ -66,3 +106,203 @@ func (h *Handler) List(c *gin.Context) {
 
 	c.JSON(http.StatusOK, res)
 }
+
+/*Метод получения страницы посещений по курсору (без Content-Range: общее количество не считается)*/
+func (h *Handler) listAfter(c *gin.Context, cursor string, filter domain.Filter) {
+	cur, err := link.ParseCursor(cursor, c.Query("limit"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	res, next, err := h.useCase.ListAfter(c.Request.Context(), cur, filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+		return
+	}
+
+	if res == nil {
+		res = []linkvisitusecase.LinkVisitDTO{}
+	}
+
+	pagination.SetNextLink(c, next)
+	c.JSON(http.StatusOK, res)
+}
+
+/*Метод получения статистики переходов по ссылке*/
+func (h *Handler) Stats(c *gin.Context) {
+	from, err := domain.ParseTime(c.Query("from"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid from"})
+		return
+	}
+
+	to, err := domain.ParseTime(c.Query("to"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid to"})
+		return
+	}
+
+	id, ok := h.linkID(c)
+	if !ok {
+		return
+	}
+
+	includeBots, err := includeBots(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	in := linkvisitusecase.StatsInput{LinkID: id, Interval: c.Query("interval"), IncludeBots: includeBots}
+	if from != nil {
+		in.From = *from
+	}
+	if to != nil {
+		in.To = *to
+	}
+
+	res, err := h.useCase.Stats(c.Request.Context(), in)
+	if err != nil {
+		if errors.Is(err, linkvisitusecase.ErrInvalidStatsQuery) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid stats query (from must be before to, interval is hour|day|week)"})
+			return
+		}
+		if errors.Is(err, linkvisitusecase.ErrLinkNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+		return
+	}
+
+	c.JSON(http.StatusOK, res)
+}
+
+/*Метод получения идентификатора существующей ссылки из пути (ответ об ошибке уже записан, если false)*/
+func (h *Handler) linkID(c *gin.Context) (int64, bool) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return 0, false
+	}
+
+	if _, err := h.links.Get(c.Request.Context(), id); err != nil {
+		if errors.Is(err, linkusecase.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "not found"})
+			return 0, false
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+		return 0, false
+	}
+
+	return id, true
+}
+
+/*Метод ручного запуска очистки посещений старше срока хранения (dry_run=true — только подсчёт)*/
+func (h *Handler) Purge(c *gin.Context) {
+	dryRun := false
+	if v := c.Query("dry_run"); v != "" {
+		var err error
+		if dryRun, err = strconv.ParseBool(v); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid dry_run"})
+			return
+		}
+	}
+
+	if h.retention == nil {
+		c.JSON(http.StatusConflict, gin.H{"error": linkvisitusecase.ErrRetentionDisabled.Error()})
+		return
+	}
+
+	res, err := h.retention.Purge(c.Request.Context(), dryRun)
+	if err != nil {
+		if errors.Is(err, linkvisitusecase.ErrRetentionDisabled) || errors.Is(err, linkvisitusecase.ErrPurgeRunning) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+		return
+	}
+
+	c.JSON(http.StatusOK, res)
+}
+
+/*Метод разбора фильтра: JSON filter (react-admin) и отдельные параметры link_id, from, to, status, ip, referer, browser, os, device, is_bot (без is_bot в список попадают и боты)*/
+func parseFilter(c *gin.Context) (domain.Filter, error) {
+	filter, err := domain.ParseFilter(c.Query("filter"))
+	if err != nil {
+		return domain.Filter{}, err
+	}
+
+	if v := c.Query("link_id"); v != "" {
+		if filter.LinkID, err = strconv.ParseInt(v, 10, 64); err != nil {
+			return domain.Filter{}, errors.New("invalid link_id")
+		}
+	}
+
+	if v := c.Query("status"); v != "" {
+		if filter.Status, err = strconv.Atoi(v); err != nil {
+			return domain.Filter{}, errors.New("invalid status")
+		}
+	}
+
+	if v := c.Query("from"); v != "" {
+		if filter.From, err = domain.ParseTime(v); err != nil {
+			return domain.Filter{}, errors.New("invalid from")
+		}
+	}
+
+	if v := c.Query("to"); v != "" {
+		if filter.To, err = domain.ParseTime(v); err != nil {
+			return domain.Filter{}, errors.New("invalid to")
+		}
+	}
+
+	if v, ok := c.GetQuery("ip"); ok {
+		filter.IP = v
+	}
+
+	if v, ok := c.GetQuery("referer"); ok {
+		filter.Referer = v
+	}
+
+	if v, ok := c.GetQuery("browser"); ok {
+		filter.Browser = v
+	}
+
+	if v, ok := c.GetQuery("os"); ok {
+		filter.OS = v
+	}
+
+	if v := c.Query("device"); v != "" {
+		if filter.Device = domain.Device(v); !filter.Device.IsValid() {
+			return domain.Filter{}, errors.New("invalid device")
+		}
+	}
+
+	if v := c.Query("is_bot"); v != "" {
+		isBot, err := strconv.ParseBool(v)
+		if err != nil {
+			return domain.Filter{}, errors.New("invalid is_bot")
+		}
+		filter.IsBot = &isBot
+	}
+
+	return filter, nil
+}
+
+/*Метод разбора параметра include_bots*/
+func includeBots(c *gin.Context) (bool, error) {
+	v := c.Query("include_bots")
+	if v == "" {
+		return false, nil
+	}
+
+	res, err := strconv.ParseBool(v)
+	if err != nil {
+		return false, errors.New("invalid include_bots")
+	}
+
+	return res, nil
+}
@@ -1,9 +1,19 @@
 package linkvisit
 
-import "github.com/gin-gonic/gin"
+import (
+	"link-service/src/domain/apikey"
+	"link-service/src/interface/http/auth"
+
+	"github.com/gin-gonic/gin"
+)
 
 /*Метод регистрации маршрутов*/
 func RegisterRoutes(router *gin.RouterGroup, h *Handler) {
-	router.GET("/link_visits", h.List)
-}
+	read := router.Group("", auth.Require(apikey.ScopeVisitsRead))
+	read.GET("/link_visits", h.List)            /*Маршрут для получения списка посещений*/
+	read.GET("/link_visits/export", h.Export)   /*Маршрут для потоковой выгрузки посещений (csv, ndjson)*/
+	read.GET("/links/:id/visits", h.ListByLink) /*Маршрут для получения посещений ссылки*/
+	read.GET("/links/:id/stats", h.Stats)       /*Маршрут для получения статистики переходов по ссылке*/
 
+	router.POST("/link_visits/purge", auth.RequireAdmin(), h.Purge) /*Маршрут для ручной очистки устаревших посещений (только административный ключ и администраторы)*/
+}
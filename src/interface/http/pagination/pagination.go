@@ -0,0 +1,25 @@
+package pagination
+
+import (
+	"fmt"
+	"strconv"
+
+	"link-service/src/domain/link"
+
+	"github.com/gin-gonic/gin"
+)
+
+/*Метод установки заголовка Link: rel="next" со ссылкой на следующую страницу (next == nil — страница последняя)*/
+func SetNextLink(c *gin.Context, next *link.Cursor) {
+	if next == nil {
+		return
+	}
+
+	u := *c.Request.URL
+	q := u.Query()
+	q.Set("cursor", link.EncodeCursor(next.AfterID))
+	q.Set("limit", strconv.Itoa(next.Limit))
+	u.RawQuery = q.Encode()
+
+	c.Header("Link", fmt.Sprintf("<%s>; rel=\"next\"", u.RequestURI()))
+}
@@ -0,0 +1,66 @@
+package ratelimit
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	domain "link-service/src/domain/ratelimit"
+	"link-service/src/interface/http/auth"
+
+	"github.com/gin-gonic/gin"
+)
+
+/*Middleware: ограничивает запросы корзиной токенов по ключу (API ключ или пользователь, если запрос прошёл auth.Middleware, иначе IP клиента); 429 — токены кончились*/
+func Middleware(store domain.Store, name string, p domain.Policy) gin.HandlerFunc {
+	return MiddlewareByKey(store, name, p, key)
+}
+
+/*Middleware с собственным ключом корзины (например, IP и короткая ссылка)*/
+func MiddlewareByKey(store domain.Store, name string, p domain.Policy, key func(c *gin.Context) string) gin.HandlerFunc {
+	if store == nil || !p.Enabled() {
+		return func(c *gin.Context) { c.Next() }
+	}
+
+	return func(c *gin.Context) {
+		res, err := store.Take(c.Request.Context(), name+":"+key(c), p, time.Now())
+		if err != nil {
+			// недоступное хранилище лимитов не должно останавливать сервис — только попадает в лог запроса
+			_ = c.Error(err)
+			c.Next()
+			return
+		}
+
+		c.Header("RateLimit-Limit", strconv.Itoa(res.Limit))
+		c.Header("RateLimit-Remaining", strconv.Itoa(res.Remaining))
+		c.Header("RateLimit-Reset", ceilSeconds(res.Reset))
+
+		if !res.Allowed {
+			c.Header("Retry-After", ceilSeconds(res.RetryAfter))
+			c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{"error": "too many requests"})
+			return
+		}
+
+		c.Next()
+	}
+}
+
+/*Метод получения ключа корзины: проверенный API ключ или пользователь, иначе IP (как при записи посещения)*/
+func key(c *gin.Context) string {
+	if p := auth.PrincipalFrom(c); p.Admin || p.KeyID != 0 || p.UserID != 0 {
+		return p.Actor()
+	}
+
+	return "ip:" + c.ClientIP()
+}
+
+/*Метод получения ключа корзины попыток ввода пароля: IP клиента и короткая ссылка*/
+func UnlockKey(c *gin.Context) string {
+	return "ip:" + c.ClientIP() + ":" + c.Param("code")
+}
+
+/*Метод округления длительности вверх до целых секунд для заголовков*/
+func ceilSeconds(d time.Duration) string {
+	return strconv.Itoa(int(math.Ceil(d.Seconds())))
+}
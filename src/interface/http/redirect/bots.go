@@ -0,0 +1,73 @@
+package redirect
+
+import (
+	"net/http"
+	"strings"
+
+	domain "link-service/src/domain/linkvisit"
+)
+
+/*Встроенный список подстрок User-Agent ботов и генераторов превью ссылок*/
+var DefaultBotUserAgents = []string{
+	"slackbot", "slack-imgproxy", "telegrambot", "facebookexternalhit", "facebot",
+	"twitterbot", "whatsapp", "discordbot", "linkedinbot", "skypeuripreview",
+	"vkshare", "redditbot", "pinterest", "embedly", "iframely", "bitlybot",
+	"applebot", "googlebot", "bingbot", "yandexbot", "duckduckbot", "baiduspider",
+	"mastodon", "viber", "snapchat", "google-pagerenderer", "headlesschrome",
+}
+
+/*Заголовки, которыми браузеры и мессенджеры помечают превью и предзагрузку*/
+var previewHeaders = []string{"X-Purpose", "Purpose", "Sec-Purpose", "X-Moz"}
+
+/*Политика обработки посещений ботами*/
+type BotPolicy struct {
+	UserAgents []string /*Подстроки User-Agent ботов без учёта регистра (nil — DefaultBotUserAgents и встроенные признаки ParseUserAgent; задан — только он)*/
+	SkipVisits bool     /*Не записывать посещения ботов*/
+}
+
+/*Распознавание ботов по User-Agent и заголовкам превью*/
+type botDetector struct {
+	userAgents []string
+	custom     bool /*Список задан в конфигурации: встроенные признаки ParseUserAgent не применяются*/
+}
+
+/*Метод создания распознавателя ботов*/
+func newBotDetector(userAgents []string) botDetector {
+	custom := userAgents != nil
+	if !custom {
+		userAgents = DefaultBotUserAgents
+	}
+
+	res := botDetector{userAgents: make([]string, 0, len(userAgents)), custom: custom}
+	for _, ua := range userAgents {
+		if ua = strings.ToLower(strings.TrimSpace(ua)); ua != "" {
+			res.userAgents = append(res.userAgents, ua)
+		}
+	}
+
+	return res
+}
+
+/*Метод проверки, что запрос сделан ботом или генератором превью*/
+func (d botDetector) IsBot(r *http.Request) bool {
+	for _, h := range previewHeaders {
+		v := strings.ToLower(r.Header.Get(h))
+		if strings.Contains(v, "preview") || strings.Contains(v, "prefetch") {
+			return true
+		}
+	}
+
+	ua := r.Header.Get("User-Agent")
+	if !d.custom && domain.ParseUserAgent(ua).IsBot {
+		return true
+	}
+
+	ua = strings.ToLower(ua)
+	for _, pattern := range d.userAgents {
+		if strings.Contains(ua, pattern) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,29 @@
+package redirect
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/go-playground/assert/v2"
+)
+
+func TestBotDetectorConfiguredListIsAuthoritative(t *testing.T) {
+	req := func(ua string) *http.Request {
+		r, _ := http.NewRequest("GET", "/r/abc", nil)
+		r.Header.Set("User-Agent", ua)
+		return r
+	}
+
+	defaults := newBotDetector(nil)
+	assert.Equal(t, true, defaults.IsBot(req("curl/8.5.0")))
+	assert.Equal(t, true, defaults.IsBot(req("Slackbot-LinkExpanding 1.0")))
+
+	// заданный список заменяет встроенные признаки, а не дополняет их
+	custom := newBotDetector([]string{"MyCrawler"})
+	assert.Equal(t, false, custom.IsBot(req("curl/8.5.0")))
+	assert.Equal(t, true, custom.IsBot(req("mycrawler/2.0")))
+
+	preview := req("Mozilla/5.0")
+	preview.Header.Set("Sec-Purpose", "prefetch")
+	assert.Equal(t, true, custom.IsBot(preview))
+}
@@ -1,25 +1,36 @@
 package redirect
 
 import (
+	"errors"
+	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/gin-gonic/gin"
 
+	"link-service/src/domain/link"
 	linkusecase "link-service/src/usecase/link"
 	linkvisitusecase "link-service/src/usecase/linkvisit"
 )
 
+/*Время кеширования постоянных редиректов*/
+const permanentRedirectMaxAge = 365 * 24 * time.Hour
+
 /*Хендлер для редиректа по короткой ссылке*/
 type Handler struct {
 	linkUseCase      linkusecase.UseCase
 	linkVisitUseCase linkvisitusecase.UseCase
+	bots             botDetector
+	skipBotVisits    bool
 }
 
 /*Метод создания нового хендлера*/
-func NewHandler(linkUC linkusecase.UseCase, visitUC linkvisitusecase.UseCase) *Handler {
+func NewHandler(linkUC linkusecase.UseCase, visitUC linkvisitusecase.UseCase, bots BotPolicy) *Handler {
 	return &Handler{
 		linkUseCase:      linkUC,
 		linkVisitUseCase: visitUC,
+		bots:             newBotDetector(bots.UserAgents),
+		skipBotVisits:    bots.SkipVisits,
 	}
 }
 
@@ -34,22 +45,143 @@ func (h *Handler) Redirect(c *gin.Context) {
 		return
 	}
 
-	status := http.StatusFound
-	ip := c.ClientIP()
-	userAgent := c.GetHeader("User-Agent")
-	referer := c.GetHeader("Referer")
+	if l.HasPassword && !l.Expired(time.Now()) {
+		renderUnlockForm(c, http.StatusOK, l.ShortName, "")
+		return
+	}
+
+	h.redirect(c, l, redirectStatus(l))
+}
+
+/*Проверка пароля из формы и редирект по защищённой ссылке*/
+func (h *Handler) Unlock(c *gin.Context) {
+	code := c.Param("code")
+
+	l, err := h.linkUseCase.Unlock(c.Request.Context(), code, c.PostForm("password"))
+	if err != nil {
+		if errors.Is(err, linkusecase.ErrInvalidPassword) {
+			renderUnlockForm(c, http.StatusUnauthorized, code, "Invalid password")
+			return
+		}
 
-	if _, err := h.linkVisitUseCase.Create(c.Request.Context(), linkvisitusecase.CreateInput{
-		LinkID:    l.ID,
-		IP:        ip,
-		UserAgent: userAgent,
-		Referer:   referer,
-		Status:    status,
-	}); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+		c.JSON(http.StatusNotFound, gin.H{"error": "not found"})
 		return
 	}
 
+	/*После POST формы отвечаем 303, чтобы 307/308 не повторили POST на целевой URL*/
+	h.redirect(c, l, http.StatusSeeOther)
+}
+
+/*Запись посещения и редирект с учётом срока действия и лимита посещений (status — ответ клиенту, в посещение пишется тип редиректа ссылки)*/
+func (h *Handler) redirect(c *gin.Context, l linkusecase.LinkDTO, status int) {
+	// после разблокировки отвечаем 303, но посещение должно выглядеть как обычный переход по ссылке
+	visitStatus := redirectStatus(l)
+	now := time.Now()
+	if l.Expired(now) {
+		h.gone(c, l, "link expired")
+		return
+	}
+
+	c.Header("Cache-Control", cacheControl(l, status, now))
+
+	if l.MaxVisits != nil && h.bots.IsBot(c.Request) {
+		// боты и превью не расходуют лимит: проверяем его по счётчику из хранилища, ссылка из кеша может отставать
+		reached, err := h.linkUseCase.LimitReached(c.Request.Context(), l.ID)
+		if errors.Is(err, linkusecase.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "not found"})
+			return
+		}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+			return
+		}
+		if reached {
+			h.gone(c, l, "link visit limit reached")
+			return
+		}
+
+		h.recordVisit(c, l.ID, visitStatus)
+		c.Redirect(status, l.OriginalURL)
+		return
+	}
+
+	if l.MaxVisits != nil {
+		// лимит проверяется и учитывается синхронно, в одной транзакции с записью посещения
+		_, err := h.linkVisitUseCase.CreateWithinLimit(c.Request.Context(), h.visitInput(c, l.ID, visitStatus))
+		if errors.Is(err, linkvisitusecase.ErrLimitReached) {
+			h.gone(c, l, "link visit limit reached")
+			return
+		}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+			return
+		}
+
+		c.Redirect(status, l.OriginalURL)
+		return
+	}
+
+	h.recordVisit(c, l.ID, visitStatus)
 	c.Redirect(status, l.OriginalURL)
 }
 
+/*Запись посещения со статусом 410 и ответ 410 Gone*/
+func (h *Handler) gone(c *gin.Context, l linkusecase.LinkDTO, reason string) {
+	c.Header("Cache-Control", "no-store")
+
+	h.recordVisit(c, l.ID, http.StatusGone)
+	c.JSON(http.StatusGone, gin.H{"error": reason})
+}
+
+/*HTTP статус редиректа ссылки (302 по умолчанию)*/
+func redirectStatus(l linkusecase.LinkDTO) int {
+	if !link.IsValidRedirectType(l.RedirectType) {
+		return link.DefaultRedirectType
+	}
+
+	return l.RedirectType
+}
+
+/*Значение Cache-Control для редиректа*/
+func cacheControl(l linkusecase.LinkDTO, status int, now time.Time) string {
+	// постоянные редиректы (301/308) кешируются надолго, но не дольше срока действия ссылки;
+	// временные редиректы и ссылки с лимитом/паролем не кешируются, иначе посещения не будут учтены
+	if !link.IsPermanentRedirect(status) || l.MaxVisits != nil || l.HasPassword {
+		return "no-store"
+	}
+
+	maxAge := permanentRedirectMaxAge
+	if l.ExpiresAt != nil {
+		if left := l.ExpiresAt.Sub(now); left < maxAge {
+			maxAge = left
+		}
+	}
+
+	return fmt.Sprintf("public, max-age=%d", int64(maxAge/time.Second))
+}
+
+/*Запись посещения с данными запроса без ожидания записи в хранилище*/
+func (h *Handler) recordVisit(c *gin.Context, linkID int64, status int) {
+	in := h.visitInput(c, linkID, status)
+	if in.IsBot && h.skipBotVisits {
+		return
+	}
+
+	// ошибка записи посещения не должна мешать редиректу — только попадает в лог запроса
+	if err := h.linkVisitUseCase.Record(c.Request.Context(), in); err != nil {
+		_ = c.Error(err)
+	}
+}
+
+/*Формирование входных параметров посещения из запроса*/
+func (h *Handler) visitInput(c *gin.Context, linkID int64, status int) linkvisitusecase.CreateInput {
+	return linkvisitusecase.CreateInput{
+		LinkID:     linkID,
+		IP:         c.ClientIP(),
+		UserAgent:  c.GetHeader("User-Agent"),
+		Referer:    c.GetHeader("Referer"),
+		Status:     status,
+		IsBot:      h.bots.IsBot(c.Request),
+		DoNotTrack: c.GetHeader("DNT") == "1" || c.GetHeader("Sec-GPC") == "1",
+	}
+}
@@ -0,0 +1,50 @@
+package redirect
+
+import (
+	"html/template"
+
+	"github.com/gin-gonic/gin"
+)
+
+/*Шаблон формы ввода пароля для защищённой ссылки*/
+var unlockTemplate = template.Must(template.New("unlock").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<meta name="robots" content="noindex">
+<title>Password required</title>
+<style>
+body{font-family:system-ui,sans-serif;display:flex;min-height:100vh;margin:0;align-items:center;justify-content:center;background:#f5f5f5}
+form{background:#fff;padding:24px;border-radius:8px;box-shadow:0 1px 4px rgba(0,0,0,.1);min-width:280px}
+input,button{width:100%;box-sizing:border-box;padding:8px;margin-top:8px;font-size:16px}
+.error{color:#c00;margin-top:8px}
+</style>
+</head>
+<body>
+<form method="post" action="/r/{{.Code}}">
+<label for="password">This link is password protected</label>
+<input id="password" name="password" type="password" autocomplete="current-password" autofocus required>
+{{if .Error}}<div class="error">{{.Error}}</div>{{end}}
+<button type="submit">Open</button>
+</form>
+</body>
+</html>
+`))
+
+/*Данные для шаблона формы ввода пароля*/
+type unlockPage struct {
+	Code  string /*Короткое имя ссылки*/
+	Error string /*Сообщение об ошибке*/
+}
+
+/*Метод отрисовки формы ввода пароля*/
+func renderUnlockForm(c *gin.Context, status int, code, errMsg string) {
+	c.Header("Cache-Control", "no-store")
+	c.Header("Content-Type", "text/html; charset=utf-8")
+	c.Status(status)
+
+	if err := unlockTemplate.Execute(c.Writer, unlockPage{Code: code, Error: errMsg}); err != nil {
+		_ = c.Error(err)
+	}
+}
@@ -0,0 +1,54 @@
+package requestid
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+
+	"link-service/src/domain/link"
+
+	"github.com/gin-gonic/gin"
+)
+
+/*Заголовок с идентификатором запроса*/
+const Header = "X-Request-ID"
+
+/*Максимальная длина идентификатора, принятого от клиента*/
+const maxLength = 128
+
+/*Middleware: берёт X-Request-ID клиента или генерирует новый, возвращает его в ответе и кладёт в контекст запроса (для истории изменений)*/
+func Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.GetHeader(Header)
+		if !valid(id) {
+			id = generate()
+		}
+
+		c.Header(Header, id)
+		c.Request = c.Request.WithContext(link.WithRequestID(c.Request.Context(), id))
+
+		c.Next()
+	}
+}
+
+/*Метод проверки идентификатора от клиента: непустой, ограниченной длины, только печатные ASCII символы*/
+func valid(id string) bool {
+	if id == "" || len(id) > maxLength {
+		return false
+	}
+
+	for i := 0; i < len(id); i++ {
+		if id[i] < 0x21 || id[i] > 0x7e {
+			return false
+		}
+	}
+
+	return true
+}
+
+/*Метод генерации случайного идентификатора*/
+func generate() string {
+	var b [16]byte
+	_, _ = rand.Read(b[:])
+
+	return hex.EncodeToString(b[:])
+}
@@ -3,105 +3,156 @@ package httpinterface
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"strings"
 	"testing"
 	"time"
 
+	"link-service/src/domain/entity"
 	"link-service/src/domain/link"
+	"link-service/src/domain/linkvisit"
+	"link-service/src/domain/ratelimit"
+	"link-service/src/domain/user"
+	ratelimitstore "link-service/src/infrastructure/ratelimit"
+	"link-service/src/infrastructure/repository/cache"
+	"link-service/src/infrastructure/repository/memory"
+	linkhttp "link-service/src/interface/http/link"
+	"link-service/src/interface/http/redirect"
+	apikeyusecase "link-service/src/usecase/apikey"
 	linkusecase "link-service/src/usecase/link"
 	linkvisitusecase "link-service/src/usecase/linkvisit"
+	userusecase "link-service/src/usecase/user"
 
 	"github.com/gin-gonic/gin"
 	"github.com/go-playground/assert/v2"
 )
 
-type stubLinkUC struct {
-	getByShortName func(ctx context.Context, shortName string) (linkusecase.LinkDTO, error)
-	create        func(ctx context.Context, in linkusecase.CreateInput) (linkusecase.LinkDTO, error)
-	update        func(ctx context.Context, id int64, in linkusecase.UpdateInput) (linkusecase.LinkDTO, error)
-}
+/*Административный API ключ тестового приложения*/
+const testAPIKey = "test-admin-key"
 
-func (s stubLinkUC) List(ctx context.Context) ([]linkusecase.LinkDTO, error) { 
-	return nil, nil 
+/*Приложение для тестов маршрутов: настоящие сервисы поверх хранилища в памяти*/
+type testApp struct {
+	links  *memory.Repository
+	visits *memory.LinkVisitRepository
+	users  *memory.UserRepository
+	deps   Deps
 }
-func (s stubLinkUC) ListWithRange(ctx context.Context, rng *link.Range) ([]linkusecase.LinkDTO, error) {
-	return nil, nil
+
+func newTestApp() *testApp {
+	store := memory.NewStore()
+	links := memory.New(store)
+	visits := memory.NewLinkVisitRepository(store)
+	users := memory.NewUserRepository()
+
+	return &testApp{
+		links:  links,
+		visits: visits,
+		users:  users,
+		deps: Deps{
+			Link:      linkusecase.NewService(links, "http://localhost"),
+			LinkVisit: linkvisitusecase.NewService(visits, links, nil, nil, linkvisitusecase.Privacy{}),
+			APIKeys:   apikeyusecase.NewService(memory.NewAPIKeyRepository(), users, testAPIKey),
+			Users:     userusecase.NewService(users, time.Hour),
+		},
+	}
 }
-func (s stubLinkUC) Count(ctx context.Context) (int64, error) { 
-	return 0, nil 
+
+/*Роутер со всеми маршрутами приложения*/
+func (a *testApp) router() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	InitRoutes(router, a.deps)
+	return router
 }
-func (s stubLinkUC) Get(ctx context.Context, id int64) (linkusecase.LinkDTO, error) { 
-	return linkusecase.LinkDTO{}, nil 
+
+/*Создание ссылки через сервис в обход API*/
+func (a *testApp) createLink(t *testing.T, in linkusecase.CreateInput) linkusecase.LinkDTO {
+	t.Helper()
+
+	l, err := a.deps.Link.Create(link.WithAccess(context.Background(), link.SystemAccess), in)
+	if err != nil {
+		t.Fatalf("create link: %v", err)
+	}
+	return l
 }
-func (s stubLinkUC) Create(ctx context.Context, in linkusecase.CreateInput) (linkusecase.LinkDTO, error) {
-	if s.create != nil {
-		return s.create(ctx, in)
+
+/*Запись посещения напрямую в репозиторий*/
+func (a *testApp) createVisit(t *testing.T, in linkvisit.CreateInput) entity.LinkVisit {
+	t.Helper()
+
+	v, err := a.visits.Create(context.Background(), in)
+	if err != nil {
+		t.Fatalf("create visit: %v", err)
 	}
-	return linkusecase.LinkDTO{}, nil
+	return v
 }
-func (s stubLinkUC) Update(ctx context.Context, id int64, in linkusecase.UpdateInput) (linkusecase.LinkDTO, error) {
-	if s.update != nil {
-		return s.update(ctx, id, in)
+
+/*Все записанные посещения в порядке id*/
+func (a *testApp) recordedVisits(t *testing.T) []entity.LinkVisit {
+	t.Helper()
+
+	var res []entity.LinkVisit
+	err := a.visits.Each(context.Background(), linkvisit.Filter{}, func(v entity.LinkVisit) error {
+		res = append(res, v)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("list visits: %v", err)
 	}
-	return linkusecase.LinkDTO{}, nil
+	return res
 }
-func (s stubLinkUC) Delete(ctx context.Context, id int64) error { 
-	return nil 
+
+/*Статусы записанных посещений*/
+func (a *testApp) recordedStatuses(t *testing.T) []int {
+	t.Helper()
+
+	var res []int
+	for _, v := range a.recordedVisits(t) {
+		res = append(res, v.Status)
+	}
+	return res
 }
-func (s stubLinkUC) GetByShortName(ctx context.Context, shortName string) (linkusecase.LinkDTO, error) {
-	return s.getByShortName(ctx, shortName)
+
+/*Запрос к роутеру (key — API ключ, пусто — без авторизации)*/
+func serve(router *gin.Engine, method, url, key, body string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(method, url, strings.NewReader(body))
+	if key != "" {
+		req.Header.Set("Authorization", "Bearer "+key)
+	}
+	if body != "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	router.ServeHTTP(w, req)
+	return w
 }
 
-type stubVisitUC struct {
-	create        func(ctx context.Context, in linkvisitusecase.CreateInput) (linkvisitusecase.LinkVisitDTO, error)
-	listWithRange func(ctx context.Context, rng *link.Range) ([]linkvisitusecase.LinkVisitDTO, error)
-	count         func(ctx context.Context) (int64, error)
+/*Репозиторий ссылок, обход которого падает до первой строки*/
+type failingEachRepo struct {
+	link.Repository
 }
 
-func (s stubVisitUC) Create(ctx context.Context, in linkvisitusecase.CreateInput) (linkvisitusecase.LinkVisitDTO, error) {
-	return s.create(ctx, in)
+func (failingEachRepo) Each(ctx context.Context, q link.Query, fn func(entity.Link) error) error {
+	return errors.New("db is down")
 }
-func (s stubVisitUC) ListWithRange(ctx context.Context, rng *link.Range) ([]linkvisitusecase.LinkVisitDTO, error) {
-	return s.listWithRange(ctx, rng)
+
+type stubRetention func(ctx context.Context, dryRun bool) (linkvisitusecase.PurgeResult, error)
+
+func (s stubRetention) Purge(ctx context.Context, dryRun bool) (linkvisitusecase.PurgeResult, error) {
+	return s(ctx, dryRun)
 }
-func (s stubVisitUC) Count(ctx context.Context) (int64, error) { return s.count(ctx) }
 
 func TestRedirectCreatesVisitAndRedirects(t *testing.T) {
-	gin.SetMode(gin.TestMode)
+	app := newTestApp()
+	app.createLink(t, linkusecase.CreateInput{OriginalURL: "https://example.com", ShortName: "abc"})
 
-	var created *linkvisitusecase.CreateInput
-
-	router := gin.New()
+	router := app.router()
 	router.TrustedPlatform = gin.PlatformCloudflare
 
-	linkUC := stubLinkUC{
-		getByShortName: func(ctx context.Context, shortName string) (linkusecase.LinkDTO, error) {
-			assert.Equal(t, "abc", shortName)
-			return linkusecase.LinkDTO{
-				ID:          1,
-				OriginalURL: "https://example.com",
-				ShortName:   "abc",
-				ShortURL:    "http://localhost/r/abc",
-			}, nil
-		},
-	}
-
-	visitUC := stubVisitUC{
-		create: func(ctx context.Context, in linkvisitusecase.CreateInput) (linkvisitusecase.LinkVisitDTO, error) {
-			tmp := in
-			created = &tmp
-			return linkvisitusecase.LinkVisitDTO{ID: 5}, nil
-		},
-		listWithRange: func(ctx context.Context, rng *link.Range) ([]linkvisitusecase.LinkVisitDTO, error) {
-			return nil, nil
-		},
-		count: func(ctx context.Context) (int64, error) { return 0, nil },
-	}
-
-	InitRoutes(router, Deps{Link: linkUC, LinkVisit: visitUC})
-
 	w := httptest.NewRecorder()
 	req, _ := http.NewRequest("GET", "/r/abc", nil)
 	req.Header.Set("CF-Connecting-IP", "1.2.3.4")
@@ -114,106 +165,529 @@ func TestRedirectCreatesVisitAndRedirects(t *testing.T) {
 	assert.Equal(t, http.StatusFound, w.Code)
 	assert.Equal(t, "https://example.com", w.Header().Get("Location"))
 
-	if created == nil {
-		t.Fatalf("expected visit to be created")
+	visits := app.recordedVisits(t)
+	if len(visits) != 1 {
+		t.Fatalf("expected one visit, got %d", len(visits))
 	}
-	assert.Equal(t, int64(1), created.LinkID)
-	assert.Equal(t, "1.2.3.4", created.IP)
-	assert.Equal(t, "curl/8.5.0", created.UserAgent)
-	assert.Equal(t, "https://ref.example/", created.Referer)
-	assert.Equal(t, http.StatusFound, created.Status)
+	assert.Equal(t, int64(1), visits[0].LinkID)
+	assert.Equal(t, "1.2.3.4", visits[0].IP)
+	assert.Equal(t, "curl/8.5.0", visits[0].UserAgent)
+	assert.Equal(t, "https://ref.example/", visits[0].Referer)
+	assert.Equal(t, http.StatusFound, visits[0].Status)
 }
 
 func TestLinkVisitsListPaginationSetsContentRange(t *testing.T) {
-	gin.SetMode(gin.TestMode)
+	app := newTestApp()
+	l := app.createLink(t, linkusecase.CreateInput{OriginalURL: "https://example.com", ShortName: "abc"})
+	for i := 0; i < 357; i++ {
+		app.createVisit(t, linkvisit.CreateInput{LinkID: l.ID, IP: "172.18.0.1", UserAgent: "curl/8.5.0", Status: 302})
+	}
 
-	router := gin.New()
+	router := app.router()
 
-	visits := make([]linkvisitusecase.LinkVisitDTO, 0, 11)
-	now := time.Date(2025, 10, 31, 13, 1, 43, 0, time.UTC)
-	for i := 0; i < 11; i++ {
-		visits = append(visits, linkvisitusecase.LinkVisitDTO{
-			ID:        int64(5 + i),
-			LinkID:    1,
-			CreatedAt: now,
-			IP:        "172.18.0.1",
-			UserAgent: "curl/8.5.0",
-			Referer:   "",
-			Status:    302,
-		})
+	w := serve(router, "GET", "/api/link_visits?range=%5B10,20%5D", testAPIKey, "")
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "link_visits 10-20/357", w.Header().Get("Content-Range"))
+
+	var got []linkvisitusecase.LinkVisitDTO
+	err := json.Unmarshal(w.Body.Bytes(), &got)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 11, len(got))
+	assert.Equal(t, int64(11), got[0].ID)
+}
+
+func TestLinkVisitsListCursorPaginationSetsNextLink(t *testing.T) {
+	app := newTestApp()
+	l := app.createLink(t, linkusecase.CreateInput{OriginalURL: "https://example.com", ShortName: "abc"})
+	for i := 0; i < 45; i++ {
+		app.createVisit(t, linkvisit.CreateInput{LinkID: l.ID, Status: 302})
 	}
 
-	linkUC := stubLinkUC{getByShortName: func(ctx context.Context, shortName string) (linkusecase.LinkDTO, error) {
-		return linkusecase.LinkDTO{}, nil
-	}}
+	router := app.router()
 
-	visitUC := stubVisitUC{
-		create: func(ctx context.Context, in linkvisitusecase.CreateInput) (linkvisitusecase.LinkVisitDTO, error) {
-			return linkvisitusecase.LinkVisitDTO{}, nil
-		},
-		listWithRange: func(ctx context.Context, rng *link.Range) ([]linkvisitusecase.LinkVisitDTO, error) {
-			assert.Equal(t, 10, rng.Start)
-			assert.Equal(t, 20, rng.End)
-			return visits, nil
-		},
-		count: func(ctx context.Context) (int64, error) { return 357, nil },
+	w := serve(router, "GET", "/api/link_visits?cursor="+link.EncodeCursor(40)+"&limit=2", testAPIKey, "")
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "", w.Header().Get("Content-Range"))
+	assert.Equal(t, `</api/link_visits?cursor=`+link.EncodeCursor(42)+`&limit=2>; rel="next"`, w.Header().Get("Link"))
+
+	var got []linkvisitusecase.LinkVisitDTO
+	if err := json.Unmarshal(w.Body.Bytes(), &got); err != nil {
+		t.Fatalf("invalid json: %v", err)
 	}
+	assert.Equal(t, 2, len(got))
+	assert.Equal(t, int64(41), got[0].ID)
 
-	InitRoutes(router, Deps{Link: linkUC, LinkVisit: visitUC})
+	// последняя страница не ссылается на следующую
+	w = serve(router, "GET", "/api/link_visits?cursor="+link.EncodeCursor(43)+"&limit=2", testAPIKey, "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "", w.Header().Get("Link"))
 
-	w := httptest.NewRecorder()
-	req, _ := http.NewRequest("GET", "/api/link_visits", nil)
-	req.URL.RawQuery = "range=%5B10,20%5D"
+	assert.Equal(t, http.StatusBadRequest, serve(router, "GET", "/api/link_visits?cursor=not-a-cursor", testAPIKey, "").Code)
+}
 
-	router.ServeHTTP(w, req)
+func TestLinksListFilterAndSort(t *testing.T) {
+	app := newTestApp()
+	app.createLink(t, linkusecase.CreateInput{OriginalURL: "https://example.com/promo", ShortName: "promo1"})
+	app.createLink(t, linkusecase.CreateInput{OriginalURL: "https://example.com/about", ShortName: "about"})
+	app.createLink(t, linkusecase.CreateInput{OriginalURL: "https://example.com/promo-2", ShortName: "promo2"})
+
+	router := app.router()
+
+	w := serve(router, "GET", `/api/links?range=[0,9]&filter={"q":"promo"}&sort=["short_name","DESC"]`, testAPIKey, "")
 
 	assert.Equal(t, http.StatusOK, w.Code)
-	assert.Equal(t, "link_visits 10-20/357", w.Header().Get("Content-Range"))
+	assert.Equal(t, "links 0-1/2", w.Header().Get("Content-Range"))
+
+	var got []linkhttp.LinkResponse
+	assert.Equal(t, nil, json.Unmarshal(w.Body.Bytes(), &got))
+	assert.Equal(t, 2, len(got))
+	assert.Equal(t, "promo2", got[0].ShortName)
+	assert.Equal(t, "promo1", got[1].ShortName)
+
+	for _, rawQuery := range []string{
+		`filter={"owner":"me"}`,
+		`sort=["password_hash","ASC"]`,
+		`sort=["id","SIDEWAYS"]`,
+	} {
+		assert.Equal(t, http.StatusBadRequest, serve(router, "GET", "/api/links?"+rawQuery, testAPIKey, "").Code)
+	}
+}
 
-	var got []map[string]any
-	err := json.Unmarshal(w.Body.Bytes(), &got)
+func TestLinkUpdateKeepsOmittedFields(t *testing.T) {
+	app := newTestApp()
+	limit := 5
+	expires := time.Now().Add(time.Hour).UTC().Truncate(time.Second)
+	l := app.createLink(t, linkusecase.CreateInput{
+		OriginalURL: "https://example.com", ShortName: "abc", Password: "secret",
+		RedirectType: 307, MaxVisits: &limit, ExpiresAt: &expires,
+	})
+
+	router := app.router()
+	url := fmt.Sprintf("/api/links/%d", l.ID)
+
+	w := serve(router, "PUT", url, testAPIKey, `{"original_url":"https://example.com/new"}`)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	got, err := app.deps.Link.Get(link.WithAccess(context.Background(), link.SystemAccess), l.ID)
 	assert.Equal(t, nil, err)
-	assert.Equal(t, 11, len(got))
+	assert.Equal(t, "https://example.com/new", got.OriginalURL)
+	assert.Equal(t, "abc", got.ShortName)
+	assert.Equal(t, 307, got.RedirectType)
+	assert.Equal(t, true, got.HasPassword)
+	assert.Equal(t, limit, *got.MaxVisits)
+	assert.Equal(t, true, expires.Equal(*got.ExpiresAt))
+
+	// null снимает срок действия и лимит, остальное не меняется
+	w = serve(router, "PUT", url, testAPIKey, `{"expires_at":null,"max_visits":null}`)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	got, err = app.deps.Link.Get(link.WithAccess(context.Background(), link.SystemAccess), l.ID)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "https://example.com/new", got.OriginalURL)
+	assert.Equal(t, (*int)(nil), got.MaxVisits)
+	assert.Equal(t, (*time.Time)(nil), got.ExpiresAt)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, serve(router, "PUT", url, testAPIKey, `{"max_visits":0}`).Code)
 }
 
-func TestCreateLinkValidationErrors(t *testing.T) {
-	gin.SetMode(gin.TestMode)
+func TestLinkStats(t *testing.T) {
+	app := newTestApp()
+	l := app.createLink(t, linkusecase.CreateInput{OriginalURL: "https://example.com", ShortName: "abc"})
+	for i := 0; i < 3; i++ {
+		app.createVisit(t, linkvisit.CreateInput{LinkID: l.ID, Status: 302})
+	}
 
-	router := gin.New()
+	router := app.router()
+
+	now := time.Now().UTC()
+	from := now.Add(-time.Hour).Format(time.RFC3339)
+	to := now.Add(time.Hour).Format(time.RFC3339)
+
+	for _, tc := range []struct {
+		url    string
+		status int
+	}{
+		{"/api/links/1/stats?from=" + from + "&to=" + to + "&interval=hour", http.StatusOK},
+		// за несколько лет по часам интервалов слишком много
+		{"/api/links/1/stats?from=2020-01-01&interval=hour", http.StatusBadRequest},
+		{"/api/links/1/stats?from=yesterday", http.StatusBadRequest},
+		{"/api/links/2/stats", http.StatusNotFound},
+	} {
+		w := serve(router, "GET", tc.url, testAPIKey, "")
+
+		assert.Equal(t, tc.status, w.Code)
+		if tc.status == http.StatusOK {
+			var got linkvisitusecase.StatsDTO
+			if err := json.Unmarshal(w.Body.Bytes(), &got); err != nil {
+				t.Fatalf("invalid json: %v", err)
+			}
+			assert.Equal(t, int64(3), got.Total)
+		}
+	}
+}
 
-	linkUC := stubLinkUC{
-		getByShortName: func(ctx context.Context, shortName string) (linkusecase.LinkDTO, error) {
-			return linkusecase.LinkDTO{}, nil
-		},
-		create: func(ctx context.Context, in linkusecase.CreateInput) (linkusecase.LinkDTO, error) {
-			return linkusecase.LinkDTO{}, nil
-		},
+func TestLinkVisitsFilteredByLink(t *testing.T) {
+	app := newTestApp()
+	l := app.createLink(t, linkusecase.CreateInput{OriginalURL: "https://example.com", ShortName: "abc"})
+	other := app.createLink(t, linkusecase.CreateInput{OriginalURL: "https://example.org", ShortName: "other"})
+
+	want := app.createVisit(t, linkvisit.CreateInput{LinkID: l.ID, Status: 302, Referer: "https://www.google.com/"})
+	app.createVisit(t, linkvisit.CreateInput{LinkID: l.ID, Status: 410, Referer: "https://www.google.com/"})
+	app.createVisit(t, linkvisit.CreateInput{LinkID: l.ID, Status: 302, Referer: "https://duckduckgo.com/"})
+	app.createVisit(t, linkvisit.CreateInput{LinkID: other.ID, Status: 302, Referer: "https://www.google.com/"})
+
+	router := app.router()
+
+	for _, url := range []string{
+		fmt.Sprintf("/api/links/%d/visits?status=302&from=2025-01-01&referer=google", l.ID),
+		fmt.Sprintf(`/api/link_visits?filter={"link_id":%d,"status":302,"from":"2025-01-01T00:00:00Z","referer":"google"}`, l.ID),
+	} {
+		w := serve(router, "GET", url, testAPIKey, "")
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "link_visits 0-0/1", w.Header().Get("Content-Range"))
+
+		var got []linkvisitusecase.LinkVisitDTO
+		assert.Equal(t, nil, json.Unmarshal(w.Body.Bytes(), &got))
+		assert.Equal(t, 1, len(got))
+		assert.Equal(t, want.ID, got[0].ID)
 	}
-	visitUC := stubVisitUC{
-		create: func(ctx context.Context, in linkvisitusecase.CreateInput) (linkvisitusecase.LinkVisitDTO, error) {
-			return linkvisitusecase.LinkVisitDTO{}, nil
-		},
-		listWithRange: func(ctx context.Context, rng *link.Range) ([]linkvisitusecase.LinkVisitDTO, error) {
-			return nil, nil
-		},
-		count: func(ctx context.Context) (int64, error) { return 0, nil },
+
+	for url, status := range map[string]int{
+		"/api/links/8/visits":                http.StatusNotFound,
+		"/api/link_visits?status=ok":         http.StatusBadRequest,
+		`/api/link_visits?filter={"x":1}`:    http.StatusBadRequest,
+		"/api/link_visits?from=last-tuesday": http.StatusBadRequest,
+	} {
+		assert.Equal(t, status, serve(router, "GET", url, testAPIKey, "").Code)
+	}
+}
+
+func TestLinkVisitsPurge(t *testing.T) {
+	purge := func(router *gin.Engine, query string) *httptest.ResponseRecorder {
+		return serve(router, "POST", "/api/link_visits/purge"+query, testAPIKey, "")
 	}
 
-	InitRoutes(router, Deps{Link: linkUC, LinkVisit: visitUC})
+	assert.Equal(t, http.StatusConflict, purge(newTestApp().router(), "").Code)
 
-	{
+	before := time.Date(2025, 3, 3, 0, 0, 0, 0, time.UTC)
+	app := newTestApp()
+	app.deps.Retention = stubRetention(func(ctx context.Context, dryRun bool) (linkvisitusecase.PurgeResult, error) {
+		if !dryRun {
+			return linkvisitusecase.PurgeResult{}, linkvisitusecase.ErrPurgeRunning
+		}
+		return linkvisitusecase.PurgeResult{Before: before, DryRun: true, Deleted: 42}, nil
+	})
+	router := app.router()
+
+	w := purge(router, "?dry_run=true")
+	assert.Equal(t, http.StatusOK, w.Code)
+	var res linkvisitusecase.PurgeResult
+	assert.Equal(t, nil, json.Unmarshal(w.Body.Bytes(), &res))
+	assert.Equal(t, linkvisitusecase.PurgeResult{Before: before, DryRun: true, Deleted: 42}, res)
+
+	assert.Equal(t, http.StatusConflict, purge(router, "").Code)
+	assert.Equal(t, http.StatusBadRequest, purge(router, "?dry_run=maybe").Code)
+}
+
+func TestExportStreamsCSVAndNDJSON(t *testing.T) {
+	app := newTestApp()
+	l := app.createLink(t, linkusecase.CreateInput{OriginalURL: "https://example.com/a,b", ShortName: "abc"})
+	app.createLink(t, linkusecase.CreateInput{OriginalURL: "https://example.org", ShortName: "zzz"})
+	app.createVisit(t, linkvisit.CreateInput{LinkID: l.ID, Status: 302})
+	bot := app.createVisit(t, linkvisit.CreateInput{LinkID: l.ID, Status: 302, Agent: linkvisit.UserAgent{IsBot: true}})
+	app.createVisit(t, linkvisit.CreateInput{LinkID: l.ID, Status: 410})
+
+	router := app.router()
+	get := func(url string) *httptest.ResponseRecorder {
+		return serve(router, "GET", url, testAPIKey, "")
+	}
+
+	w := get(fmt.Sprintf("/api/link_visits/export?link_id=%d&status=302", l.ID))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "text/csv; charset=utf-8", w.Header().Get("Content-Type"))
+	assert.Equal(t, `attachment; filename="link_visits.csv"`, w.Header().Get("Content-Disposition"))
+	lines := strings.Split(strings.TrimSpace(w.Body.String()), "\n")
+	// сырые посещения выгружаются вместе с ботами, если is_bot не передан
+	assert.Equal(t, 3, len(lines))
+	assert.Equal(t, "id,link_id,created_at,ip,user_agent,referer,status,browser,browser_version,os,device,is_bot,country,region,city", lines[0])
+	assert.Equal(t, fmt.Sprintf("2,1,%s,,,,302,,,,,true,,,", bot.CreatedAt.Format(time.RFC3339Nano)), lines[2])
+
+	w = get("/api/link_visits/export?format=ndjson")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "application/x-ndjson", w.Header().Get("Content-Type"))
+	lines = strings.Split(strings.TrimSpace(w.Body.String()), "\n")
+	assert.Equal(t, 3, len(lines))
+	var visit linkvisitusecase.LinkVisitDTO
+	assert.Equal(t, nil, json.Unmarshal([]byte(lines[1]), &visit))
+	assert.Equal(t, bot.ID, visit.ID)
+
+	assert.Equal(t, http.StatusBadRequest, get("/api/link_visits/export?format=xml").Code)
+
+	w = get(`/api/links/export?filter={"q":"abc"}&sort=["short_name","DESC"]`)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "id,original_url,short_name,short_url,expires_at,max_visits,has_password,redirect_type\n1,\"https://example.com/a,b\",abc,http://localhost/r/abc,,,false,302\n", w.Body.String())
+
+	w = get(`/api/links/export?format=ndjson&sort=["short_name","DESC"]`)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, `{"id":2,"original_url":"https://example.org","short_name":"zzz","short_url":"http://localhost/r/zzz","expires_at":null,"max_visits":null,"has_password":false,"redirect_type":302}`+"\n"+
+		`{"id":1,"original_url":"https://example.com/a,b","short_name":"abc","short_url":"http://localhost/r/abc","expires_at":null,"max_visits":null,"has_password":false,"redirect_type":302}`+"\n", w.Body.String())
+}
+
+func TestExportFailsBeforeFirstRow(t *testing.T) {
+	app := newTestApp()
+	app.deps.Link = linkusecase.NewService(failingEachRepo{app.links}, "http://localhost")
+
+	assert.Equal(t, http.StatusInternalServerError, serve(app.router(), "GET", "/api/links/export", testAPIKey, "").Code)
+}
+
+func TestImportLinks(t *testing.T) {
+	app := newTestApp()
+	router := app.router()
+
+	post := func(query, contentType, body string) *httptest.ResponseRecorder {
 		w := httptest.NewRecorder()
-		req, _ := http.NewRequest("POST", "/api/links", strings.NewReader("{"))
-		req.Header.Set("Content-Type", "application/json")
+		req, _ := http.NewRequest("POST", "/api/links/import"+query, strings.NewReader(body))
+		req.Header.Set("Authorization", "Bearer "+testAPIKey)
+		req.Header.Set("Content-Type", contentType)
 		router.ServeHTTP(w, req)
-		assert.Equal(t, http.StatusBadRequest, w.Code)
+		return w
+	}
+	count := func() int64 {
+		n, err := app.links.Count(context.Background(), link.Filter{})
+		assert.Equal(t, nil, err)
+		return n
 	}
 
-	{
+	w := post("", "text/csv; charset=utf-8", "\ufeffshort_name,original_url\nabc,https://a.example\n")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, `{"created":1,"conflicts":0,"invalid":0,"aborted":false,"rows":[{"row":1,"status":"created","link":{"id":1,"original_url":"https://a.example","short_name":"abc","short_url":"http://localhost/r/abc","expires_at":null,"max_visits":null,"has_password":false,"redirect_type":302}}]}`, w.Body.String())
+
+	// без заголовка столбцы читаются как original_url,short_name; пустой short_name генерируется
+	w = post("", "text/csv", "https://c.example,c01\nhttps://d.example\n")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, int64(3), count())
+	c01, err := app.links.GetByShortName(context.Background(), "c01")
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "https://c.example", c01.OriginalURL)
+
+	w = post("?all_or_nothing=true", "application/json", `[{"original_url":"https://e.example","short_name":"e1"},{"original_url":"https://a.example","short_name":"abc"}]`)
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	assert.Equal(t, int64(3), count())
+
+	assert.Equal(t, http.StatusBadRequest, post("", "application/json", `{"original_url":"x"}`).Code)
+	assert.Equal(t, http.StatusBadRequest, post("", "text/csv", "\"unterminated\n").Code)
+	assert.Equal(t, http.StatusBadRequest, post("?all_or_nothing=maybe", "application/json", `[]`).Code)
+
+	// лишние строки JSON отклоняются до разбора остального массива
+	many := "[" + strings.Repeat(`{"original_url":"https://a.example"},`, linkusecase.MaxImportRows) + `{"original_url":"https://a.example"}]`
+	assert.Equal(t, http.StatusRequestEntityTooLarge, post("", "application/json", many).Code)
+	assert.Equal(t, int64(3), count())
+}
+
+func TestLinksTrashAndRestore(t *testing.T) {
+	app := newTestApp()
+	app.createLink(t, linkusecase.CreateInput{OriginalURL: "https://a.example", ShortName: "abc"})
+	app.createLink(t, linkusecase.CreateInput{OriginalURL: "https://a.example/kept", ShortName: "kept"})
+
+	router := app.router()
+
+	assert.Equal(t, http.StatusNoContent, serve(router, "DELETE", "/api/links/1", testAPIKey, "").Code)
+	assert.Equal(t, http.StatusNotFound, serve(router, "GET", "/api/links/1", testAPIKey, "").Code)
+
+	w := serve(router, "GET", `/api/links/trash?range=[0,9]&filter={"q":"a.example"}`, testAPIKey, "")
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "links 0-0/1", w.Header().Get("Content-Range"))
+	var trashed []linkhttp.LinkResponse
+	assert.Equal(t, nil, json.Unmarshal(w.Body.Bytes(), &trashed))
+	assert.Equal(t, 1, len(trashed))
+	assert.Equal(t, "abc", trashed[0].ShortName)
+	assert.Equal(t, true, trashed[0].DeletedAt != nil)
+
+	w = serve(router, "POST", "/api/links/1/restore", testAPIKey, "")
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, `{"id":1,"original_url":"https://a.example","short_name":"abc","short_url":"http://localhost/r/abc","expires_at":null,"max_visits":null,"has_password":false,"redirect_type":302}`, w.Body.String())
+
+	// восстановить можно только ссылку из корзины
+	assert.Equal(t, http.StatusNotFound, serve(router, "POST", "/api/links/1/restore", testAPIKey, "").Code)
+	assert.Equal(t, http.StatusNotFound, serve(router, "POST", "/api/links/4/restore", testAPIKey, "").Code)
+}
+
+func TestLinkHistory(t *testing.T) {
+	app := newTestApp()
+	router := app.router()
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/api/links", strings.NewReader(`{"original_url":"https://a.example","short_name":"abc"}`))
+	req.Header.Set("Authorization", "Bearer "+testAPIKey)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Request-ID", "req-1")
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusCreated, w.Code)
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("GET", "/api/links/1/history", nil)
+	req.Header.Set("Authorization", "Bearer "+testAPIKey)
+	req.Header.Set("X-Request-ID", "req-2")
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "req-2", w.Header().Get("X-Request-ID"))
+	var events []linkhttp.LinkEventResponse
+	assert.Equal(t, nil, json.Unmarshal(w.Body.Bytes(), &events))
+	assert.Equal(t, 1, len(events))
+	assert.Equal(t, "created", events[0].Type)
+	assert.Equal(t, "https://a.example", events[0].NewOriginalURL)
+	assert.Equal(t, "abc", events[0].NewShortName)
+	assert.Equal(t, "admin", events[0].Actor)
+	assert.Equal(t, "req-1", events[0].RequestID)
+
+	// без заголовка идентификатор генерируется
+	w = serve(router, "GET", "/api/links/4/history", testAPIKey, "")
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Equal(t, 32, len(w.Header().Get("X-Request-ID")))
+}
+
+func TestAPIRequiresKeyWithScope(t *testing.T) {
+	app := newTestApp()
+	ctx := context.Background()
+
+	alice, err := app.users.Create(ctx, user.CreateInput{Username: "alice", PasswordHash: "-", Role: user.RoleMember})
+	assert.Equal(t, nil, err)
+	reader, err := app.deps.APIKeys.Create(ctx, apikeyusecase.CreateInput{Name: "dashboard", Scopes: []string{"links:read"}, OwnerID: &alice.ID})
+	assert.Equal(t, nil, err)
+	writer, err := app.deps.APIKeys.Create(ctx, apikeyusecase.CreateInput{Name: "ci", Scopes: []string{"links:read", "links:write"}, OwnerID: &alice.ID})
+	assert.Equal(t, nil, err)
+
+	owned := app.createLink(t, linkusecase.CreateInput{OriginalURL: "https://example.com", ShortName: "abc", OwnerID: &alice.ID})
+	foreign := app.createLink(t, linkusecase.CreateInput{OriginalURL: "https://example.org", ShortName: "foreign"})
+
+	router := app.router()
+	do := func(method, url, key, body string) *httptest.ResponseRecorder {
+		return serve(router, method, url, key, body)
+	}
+	historyURL := func(id int64) string {
+		return fmt.Sprintf("/api/links/%d/history", id)
+	}
+
+	// редирект и ping остаются публичными
+	assert.Equal(t, http.StatusFound, do("GET", "/r/abc", "", "").Code)
+	assert.Equal(t, http.StatusOK, do("GET", "/ping", "", "").Code)
+
+	w := do("GET", historyURL(owned.ID), "", "")
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.Equal(t, `Bearer realm="api"`, w.Header().Get("WWW-Authenticate"))
+	assert.Equal(t, http.StatusUnauthorized, do("GET", historyURL(owned.ID), "unknown", "").Code)
+
+	// ключ видит только ссылки своего владельца
+	assert.Equal(t, http.StatusOK, do("GET", historyURL(owned.ID), reader.Key, "").Code)
+	assert.Equal(t, http.StatusNotFound, do("GET", historyURL(foreign.ID), reader.Key, "").Code)
+	assert.Equal(t, http.StatusOK, do("GET", historyURL(foreign.ID), testAPIKey, "").Code)
+
+	assert.Equal(t, http.StatusForbidden, do("DELETE", fmt.Sprintf("/api/links/%d", owned.ID), reader.Key, "").Code)
+	assert.Equal(t, http.StatusForbidden, do("GET", "/api/link_visits", reader.Key, "").Code)
+	assert.Equal(t, http.StatusForbidden, do("POST", "/api/link_visits/purge", reader.Key, "").Code)
+	assert.Equal(t, http.StatusForbidden, do("GET", "/api/api_keys", reader.Key, "").Code)
+
+	// ссылка, созданная ключом, принадлежит его владельцу, а в истории записан ключ
+	w = do("POST", "/api/links", writer.Key, `{"original_url":"https://example.net","short_name":"byci"}`)
+	assert.Equal(t, http.StatusCreated, w.Code)
+	var created linkhttp.LinkResponse
+	assert.Equal(t, nil, json.Unmarshal(w.Body.Bytes(), &created))
+	assert.Equal(t, alice.ID, *created.OwnerID)
+
+	w = do("GET", historyURL(created.ID), reader.Key, "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	var events []linkhttp.LinkEventResponse
+	assert.Equal(t, nil, json.Unmarshal(w.Body.Bytes(), &events))
+	assert.Equal(t, 1, len(events))
+	assert.Equal(t, fmt.Sprintf("api_key:%d", writer.ID), events[0].Actor)
+
+	// управлять ключами может административный ключ, но не ключ пользователя
+	w = do("POST", "/api/api_keys", testAPIKey, fmt.Sprintf(`{"name":"ci","scopes":["links:write"],"owner_id":%d}`, alice.ID))
+	assert.Equal(t, http.StatusCreated, w.Code)
+	var key struct {
+		Name    string   `json:"name"`
+		Prefix  string   `json:"prefix"`
+		Scopes  []string `json:"scopes"`
+		OwnerID int64    `json:"owner_id"`
+		Key     string   `json:"key"`
+	}
+	assert.Equal(t, nil, json.Unmarshal(w.Body.Bytes(), &key))
+	assert.Equal(t, "ci", key.Name)
+	assert.Equal(t, []string{"links:write"}, key.Scopes)
+	assert.Equal(t, alice.ID, key.OwnerID)
+	assert.Equal(t, true, strings.HasPrefix(key.Key, key.Prefix))
+	assert.Equal(t, http.StatusForbidden, do("GET", "/api/links", key.Key, "").Code)
+
+	assert.Equal(t, http.StatusBadRequest, do("POST", "/api/api_keys", testAPIKey, `{"name":"ci"}`).Code)
+	// административный ключ не привязан к пользователю, поэтому владельца нужно передать
+	assert.Equal(t, http.StatusBadRequest, do("POST", "/api/api_keys", testAPIKey, `{"name":"ci","scopes":["links:write"]}`).Code)
+	assert.Equal(t, http.StatusBadRequest, do("POST", "/api/api_keys", testAPIKey, `{"name":"ci","scopes":["links:write"],"owner_id":99}`).Code)
+	assert.Equal(t, http.StatusNotFound, do("DELETE", "/api/api_keys/5", testAPIKey, "").Code)
+}
+
+func TestAdminSessionManagesKeysButAdminOwnedKeyDoesNot(t *testing.T) {
+	app := newTestApp()
+	ctx := context.Background()
+
+	admin, err := app.deps.Users.Create(ctx, userusecase.CreateInput{Username: "root", Password: "s3cret-pass", Role: user.RoleAdmin})
+	assert.Equal(t, nil, err)
+	key, err := app.deps.APIKeys.Create(ctx, apikeyusecase.CreateInput{Name: "ci", Scopes: []string{"links:read", "links:write", "visits:read"}, OwnerID: &admin.ID})
+	assert.Equal(t, nil, err)
+
+	router := app.router()
+	w := serve(router, "POST", "/api/auth/login", "", `{"username":"root","password":"s3cret-pass"}`)
+	assert.Equal(t, http.StatusOK, w.Code)
+	var session struct {
+		Token string `json:"token"`
+	}
+	assert.Equal(t, nil, json.Unmarshal(w.Body.Bytes(), &session))
+
+	assert.Equal(t, http.StatusOK, serve(router, "GET", "/api/api_keys", session.Token, "").Code)
+	assert.Equal(t, http.StatusForbidden, serve(router, "GET", "/api/api_keys", key.Key, "").Code)
+	assert.Equal(t, http.StatusForbidden, serve(router, "POST", "/api/link_visits/purge", key.Key, "").Code)
+}
+
+func TestLoginAttemptsAreLimited(t *testing.T) {
+	app := newTestApp()
+	_, err := app.deps.Users.Create(context.Background(), userusecase.CreateInput{Username: "alice", Password: "s3cret-pass"})
+	assert.Equal(t, nil, err)
+	app.deps.RateLimit = RateLimits{
+		Store: ratelimitstore.NewMemoryStore(0),
+		Login: ratelimit.Policy{Rate: 0.1, Burst: 2},
+	}
+
+	router := app.router()
+	login := func(ip, password string) int {
 		w := httptest.NewRecorder()
-		req, _ := http.NewRequest("POST", "/api/links", strings.NewReader(`{"original_url":"not-a-url"}`))
+		req, _ := http.NewRequest("POST", "/api/auth/login", strings.NewReader(`{"username":"alice","password":"`+password+`"}`))
 		req.Header.Set("Content-Type", "application/json")
+		req.RemoteAddr = ip + ":1234"
 		router.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	assert.Equal(t, http.StatusUnauthorized, login("10.0.0.1", "guess-one"))
+	assert.Equal(t, http.StatusUnauthorized, login("10.0.0.1", "guess-two"))
+	// попытки кончились: даже верный пароль не проверяется
+	assert.Equal(t, http.StatusTooManyRequests, login("10.0.0.1", "s3cret-pass"))
+	// корзина своя для каждого IP
+	assert.Equal(t, http.StatusOK, login("10.0.0.2", "s3cret-pass"))
+}
+
+func TestCreateLinkValidationErrors(t *testing.T) {
+	router := newTestApp().router()
+
+	assert.Equal(t, http.StatusBadRequest, serve(router, "POST", "/api/links", testAPIKey, "{").Code)
+
+	{
+		w := serve(router, "POST", "/api/links", testAPIKey, `{"original_url":"not-a-url"}`)
 		assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
 
 		var body map[string]any
@@ -229,10 +703,7 @@ func TestCreateLinkValidationErrors(t *testing.T) {
 	}
 
 	{
-		w := httptest.NewRecorder()
-		req, _ := http.NewRequest("POST", "/api/links", strings.NewReader(`{"original_url":"https://example.com","short_name":"ab"}`))
-		req.Header.Set("Content-Type", "application/json")
-		router.ServeHTTP(w, req)
+		w := serve(router, "POST", "/api/links", testAPIKey, `{"original_url":"https://example.com","short_name":"ab"}`)
 		assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
 
 		var body map[string]map[string]string
@@ -244,34 +715,10 @@ func TestCreateLinkValidationErrors(t *testing.T) {
 }
 
 func TestCreateLinkShortNameConflictAsValidationError(t *testing.T) {
-	gin.SetMode(gin.TestMode)
-
-	router := gin.New()
+	app := newTestApp()
+	app.createLink(t, linkusecase.CreateInput{OriginalURL: "https://example.org", ShortName: "abc"})
 
-	linkUC := stubLinkUC{
-		getByShortName: func(ctx context.Context, shortName string) (linkusecase.LinkDTO, error) {
-			return linkusecase.LinkDTO{}, nil
-		},
-		create: func(ctx context.Context, in linkusecase.CreateInput) (linkusecase.LinkDTO, error) {
-			return linkusecase.LinkDTO{}, linkusecase.ErrShortNameConflict
-		},
-	}
-	visitUC := stubVisitUC{
-		create: func(ctx context.Context, in linkvisitusecase.CreateInput) (linkvisitusecase.LinkVisitDTO, error) {
-			return linkvisitusecase.LinkVisitDTO{}, nil
-		},
-		listWithRange: func(ctx context.Context, rng *link.Range) ([]linkvisitusecase.LinkVisitDTO, error) {
-			return nil, nil
-		},
-		count: func(ctx context.Context) (int64, error) { return 0, nil },
-	}
-
-	InitRoutes(router, Deps{Link: linkUC, LinkVisit: visitUC})
-
-	w := httptest.NewRecorder()
-	req, _ := http.NewRequest("POST", "/api/links", strings.NewReader(`{"original_url":"https://example.com","short_name":"abc"}`))
-	req.Header.Set("Content-Type", "application/json")
-	router.ServeHTTP(w, req)
+	w := serve(app.router(), "POST", "/api/links", testAPIKey, `{"original_url":"https://example.com","short_name":"abc"}`)
 
 	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
 
@@ -281,3 +728,245 @@ func TestCreateLinkShortNameConflictAsValidationError(t *testing.T) {
 	assert.Equal(t, "short name already in use", body["errors"]["short_name"])
 }
 
+func TestRedirectExpiredLinkReturnsGone(t *testing.T) {
+	app := newTestApp()
+	expiredAt := time.Now().Add(-time.Hour)
+	app.createLink(t, linkusecase.CreateInput{OriginalURL: "https://example.com", ShortName: "old", ExpiresAt: &expiredAt})
+
+	w := serve(app.router(), "GET", "/r/old", "", "")
+
+	assert.Equal(t, http.StatusGone, w.Code)
+	assert.Equal(t, "", w.Header().Get("Location"))
+	assert.Equal(t, []int{http.StatusGone}, app.recordedStatuses(t))
+}
+
+func TestRedirectLimitedLinkReturnsGoneWhenLimitReached(t *testing.T) {
+	app := newTestApp()
+	maxVisits := 1
+	app.createLink(t, linkusecase.CreateInput{OriginalURL: "https://example.com/onboarding", ShortName: "once", MaxVisits: &maxVisits})
+
+	router := app.router()
+
+	assert.Equal(t, http.StatusFound, serve(router, "GET", "/r/once", "", "").Code)
+	assert.Equal(t, http.StatusGone, serve(router, "GET", "/r/once", "", "").Code)
+
+	assert.Equal(t, []int{http.StatusFound, http.StatusGone}, app.recordedStatuses(t))
+}
+
+func TestRedirectBotsDoNotConsumeLimitAndCanBeSkipped(t *testing.T) {
+	maxVisits := 1
+
+	for _, skip := range []bool{false, true} {
+		app := newTestApp()
+		app.createLink(t, linkusecase.CreateInput{OriginalURL: "https://example.com/onboarding", ShortName: "once", MaxVisits: &maxVisits})
+		app.deps.Bots = redirect.BotPolicy{
+			UserAgents: []string{"ACME-Unfurler", "Slackbot"},
+			SkipVisits: skip,
+		}
+		router := app.router()
+
+		for _, headers := range []map[string]string{
+			{"User-Agent": "Slackbot-LinkExpanding 1.0 (+https://api.slack.com/robots)"},
+			{"User-Agent": "acme-unfurler/2.1"},
+			{"User-Agent": "Mozilla/5.0 (Macintosh; Intel Mac OS X 14_4) AppleWebKit/605.1.15 Safari/605.1.15", "X-Purpose": "preview"},
+		} {
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest("GET", "/r/once", nil)
+			for k, v := range headers {
+				req.Header.Set(k, v)
+			}
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusFound, w.Code)
+		}
+
+		visits := app.recordedVisits(t)
+		if skip {
+			assert.Equal(t, 0, len(visits))
+		} else {
+			assert.Equal(t, 3, len(visits))
+			for _, v := range visits {
+				assert.Equal(t, true, v.IsBot)
+			}
+		}
+
+		// боты не израсходовали лимит: первый человек всё ещё проходит
+		assert.Equal(t, http.StatusFound, serve(router, "GET", "/r/once", "", "").Code)
+		assert.Equal(t, http.StatusGone, serve(router, "GET", "/r/once", "", "").Code)
+	}
+}
+
+func TestRedirectBotsSeeLimitReachedThroughLinkCache(t *testing.T) {
+	app := newTestApp()
+	app.deps.Link = linkusecase.NewService(cache.New(app.links, cache.Config{Size: 10, TTL: time.Hour, NegativeTTL: time.Hour}), "http://localhost")
+	app.deps.Bots = redirect.BotPolicy{UserAgents: []string{"Slackbot"}}
+	maxVisits := 1
+	app.createLink(t, linkusecase.CreateInput{OriginalURL: "https://example.com/onboarding", ShortName: "once", MaxVisits: &maxVisits})
+
+	router := app.router()
+	bot := func() int {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/r/once", nil)
+		req.Header.Set("User-Agent", "Slackbot-LinkExpanding 1.0")
+		router.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	// ссылка попадает в кеш со счётчиком 0, человек расходует лимит
+	assert.Equal(t, http.StatusFound, bot())
+	assert.Equal(t, http.StatusFound, serve(router, "GET", "/r/once", "", "").Code)
+
+	// бот видит исчерпанный лимит, хотя в кеше счётчик ещё старый
+	assert.Equal(t, http.StatusGone, bot())
+}
+
+func TestRedirectPasswordProtectedLink(t *testing.T) {
+	app := newTestApp()
+	app.createLink(t, linkusecase.CreateInput{OriginalURL: "https://docs.example.com/internal", ShortName: "docs", Password: "s3cret"})
+
+	router := app.router()
+	unlock := func(password string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/r/docs", strings.NewReader("password="+password))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	w := serve(router, "GET", "/r/docs", "", "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, true, strings.Contains(w.Body.String(), `action="/r/docs"`))
+	assert.Equal(t, 0, len(app.recordedVisits(t)))
+
+	w = unlock("wrong")
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.Equal(t, 0, len(app.recordedVisits(t)))
+
+	w = unlock("s3cret")
+	assert.Equal(t, http.StatusSeeOther, w.Code)
+	assert.Equal(t, "https://docs.example.com/internal", w.Header().Get("Location"))
+	visits := app.recordedVisits(t)
+	assert.Equal(t, 1, len(visits))
+	assert.Equal(t, int64(1), visits[0].LinkID)
+	// в посещение пишется тип редиректа ссылки, а не 303 ответа формы
+	assert.Equal(t, http.StatusFound, visits[0].Status)
+}
+
+func TestRedirectUnlockAttemptsAreLimited(t *testing.T) {
+	app := newTestApp()
+	app.createLink(t, linkusecase.CreateInput{OriginalURL: "https://docs.example.com/internal", ShortName: "docs", Password: "s3cret"})
+	app.createLink(t, linkusecase.CreateInput{OriginalURL: "https://docs.example.com/other", ShortName: "other", Password: "s3cret"})
+	app.deps.RateLimit = RateLimits{
+		Store:  ratelimitstore.NewMemoryStore(0),
+		Unlock: ratelimit.Policy{Rate: 0.1, Burst: 2},
+	}
+
+	router := app.router()
+	unlock := func(code, ip, password string) int {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/r/"+code, strings.NewReader("password="+password))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		req.RemoteAddr = ip + ":1234"
+		router.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	assert.Equal(t, http.StatusUnauthorized, unlock("docs", "10.0.0.1", "guess1"))
+	assert.Equal(t, http.StatusUnauthorized, unlock("docs", "10.0.0.1", "guess2"))
+	// попытки кончились: даже верный пароль не проверяется
+	assert.Equal(t, http.StatusTooManyRequests, unlock("docs", "10.0.0.1", "s3cret"))
+
+	// корзина своя для каждой ссылки и каждого IP, а GET формы не ограничен
+	assert.Equal(t, http.StatusSeeOther, unlock("other", "10.0.0.1", "s3cret"))
+	assert.Equal(t, http.StatusSeeOther, unlock("docs", "10.0.0.2", "s3cret"))
+	assert.Equal(t, http.StatusOK, serve(router, "GET", "/r/docs", "", "").Code)
+}
+
+func TestRedirectUsesLinkRedirectTypeAndCacheHeaders(t *testing.T) {
+	cases := []struct {
+		redirectType int
+		cacheControl string
+	}{
+		{redirectType: http.StatusMovedPermanently, cacheControl: "public, max-age=31536000"},
+		{redirectType: http.StatusFound, cacheControl: "no-store"},
+		{redirectType: http.StatusTemporaryRedirect, cacheControl: "no-store"},
+		{redirectType: http.StatusPermanentRedirect, cacheControl: "public, max-age=31536000"},
+	}
+
+	app := newTestApp()
+	for _, tc := range cases {
+		app.createLink(t, linkusecase.CreateInput{
+			OriginalURL:  "https://example.com/new-page",
+			ShortName:    fmt.Sprintf("page%d", tc.redirectType),
+			RedirectType: tc.redirectType,
+		})
+	}
+
+	router := app.router()
+	for _, tc := range cases {
+		w := serve(router, "GET", fmt.Sprintf("/r/page%d", tc.redirectType), "", "")
+
+		assert.Equal(t, tc.redirectType, w.Code)
+		assert.Equal(t, "https://example.com/new-page", w.Header().Get("Location"))
+		assert.Equal(t, tc.cacheControl, w.Header().Get("Cache-Control"))
+	}
+
+	assert.Equal(t, []int{301, 302, 307, 308}, app.recordedStatuses(t))
+}
+
+func TestRateLimitRedirectAndCreateLink(t *testing.T) {
+	app := newTestApp()
+	app.createLink(t, linkusecase.CreateInput{OriginalURL: "https://example.com", ShortName: "abc"})
+	app.deps.RateLimit = RateLimits{
+		Store:      ratelimitstore.NewMemoryStore(0),
+		Redirect:   ratelimit.Policy{Rate: 0.1, Burst: 2},
+		CreateLink: ratelimit.Policy{Rate: 0.1, Burst: 1},
+	}
+
+	router := app.router()
+	// как в main при пустом TRUSTED_PROXIES
+	assert.Equal(t, nil, router.SetTrustedProxies(nil))
+
+	redirect := func(ip string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/r/abc", nil)
+		req.RemoteAddr = ip + ":1234"
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	w := redirect("10.0.0.1")
+	assert.Equal(t, http.StatusFound, w.Code)
+	assert.Equal(t, "2", w.Header().Get("RateLimit-Limit"))
+	assert.Equal(t, "1", w.Header().Get("RateLimit-Remaining"))
+	assert.Equal(t, http.StatusFound, redirect("10.0.0.1").Code)
+
+	w = redirect("10.0.0.1")
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Equal(t, "10", w.Header().Get("Retry-After"))
+	assert.Equal(t, "0", w.Header().Get("RateLimit-Remaining"))
+	assert.Equal(t, "20", w.Header().Get("RateLimit-Reset"))
+	// отклонённый переход не записывается
+	assert.Equal(t, 2, len(app.recordedVisits(t)))
+
+	// подставленный X-Forwarded-For не даёт новую корзину
+	w = httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/r/abc", nil)
+	req.RemoteAddr = "10.0.0.1:1234"
+	req.Header.Set("X-Forwarded-For", "203.0.113.7")
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+
+	assert.Equal(t, http.StatusFound, redirect("10.0.0.2").Code)
+
+	create := func() *httptest.ResponseRecorder {
+		return serve(router, "POST", "/api/links", testAPIKey, `{"original_url":"https://example.com"}`)
+	}
+
+	assert.Equal(t, http.StatusCreated, create().Code)
+	assert.Equal(t, http.StatusTooManyRequests, create().Code)
+
+	// импорт расходует ту же корзину, что и создание
+	assert.Equal(t, http.StatusTooManyRequests, serve(router, "POST", "/api/links/import", testAPIKey, `[{"original_url":"https://example.com"}]`).Code)
+}
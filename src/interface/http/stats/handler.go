@@ -0,0 +1,22 @@
+package stats
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+/*Источник счётчиков (кеш, очередь записи посещений и т.п.)*/
+type Source func() any
+
+/*Метод регистрации маршрутов*/
+func RegisterRoutes(router *gin.RouterGroup, sources map[string]Source) {
+	router.GET("/stats", func(c *gin.Context) {
+		res := make(gin.H, len(sources))
+		for name, source := range sources {
+			res[name] = source()
+		}
+
+		c.JSON(http.StatusOK, res)
+	})
+}
@@ -0,0 +1,31 @@
+package user
+
+import "time"
+
+/*DTO пользователя для ответа API.*/
+type UserResponse struct {
+	ID        int64     `json:"id"`         /*Идентификатор пользователя*/
+	Username  string    `json:"username"`   /*Имя для входа*/
+	Role      string    `json:"role"`       /*Роль: admin или member*/
+	CreatedAt time.Time `json:"created_at"` /*Дата создания*/
+}
+
+/*DTO сессии для ответа API (токен отдаётся один раз).*/
+type SessionResponse struct {
+	Token     string       `json:"token"`      /*Токен для Authorization: Bearer*/
+	ExpiresAt time.Time    `json:"expires_at"` /*Дата истечения сессии*/
+	User      UserResponse `json:"user"`       /*Вошедший пользователь*/
+}
+
+/*DTO для входа.*/
+type LoginRequest struct {
+	Username string `json:"username" binding:"required"` /*Имя для входа*/
+	Password string `json:"password" binding:"required"` /*Пароль*/
+}
+
+/*DTO для создания пользователя.*/
+type CreateUserRequest struct {
+	Username string `json:"username" binding:"required,max=64"`          /*Имя для входа*/
+	Password string `json:"password" binding:"required,min=8,max=72"`    /*Пароль*/
+	Role     string `json:"role" binding:"omitempty,oneof=admin member"` /*Роль (по умолчанию member)*/
+}
@@ -0,0 +1,114 @@
+package user
+
+import (
+	"net/http"
+
+	"link-service/src/interface/http/auth"
+	userusecase "link-service/src/usecase/user"
+
+	"github.com/gin-gonic/gin"
+)
+
+/*Обработчик входа и управления пользователями*/
+type Handler struct {
+	useCase userusecase.UseCase
+}
+
+/*Метод создания нового обработчика*/
+func NewHandler(useCase userusecase.UseCase) *Handler {
+	return &Handler{useCase: useCase}
+}
+
+/*Метод входа по имени и паролю*/
+func (h *Handler) Login(c *gin.Context) {
+	var req LoginRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid body"})
+		return
+	}
+
+	res, err := h.useCase.Login(c.Request.Context(), req.Username, req.Password)
+	if err != nil {
+		if err == userusecase.ErrInvalidCredentials {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid username or password"})
+			return
+		}
+
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+		return
+	}
+
+	c.JSON(http.StatusOK, SessionResponse{
+		Token:     res.Token,
+		ExpiresAt: res.ExpiresAt,
+		User:      mapToResponse(res.User),
+	})
+}
+
+/*Метод выхода: удаляет сессию из заголовка Authorization (для API ключей ничего не делает)*/
+func (h *Handler) Logout(c *gin.Context) {
+	token, _ := auth.BearerToken(c.GetHeader("Authorization"))
+
+	if err := h.useCase.Logout(c.Request.Context(), token); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+/*Метод получения списка пользователей*/
+func (h *Handler) List(c *gin.Context) {
+	users, err := h.useCase.List(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+		return
+	}
+
+	response := make([]UserResponse, 0, len(users))
+	for _, u := range users {
+		response = append(response, mapToResponse(u))
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+/*Метод создания пользователя*/
+func (h *Handler) Create(c *gin.Context) {
+	var req CreateUserRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid body"})
+		return
+	}
+
+	res, err := h.useCase.Create(c.Request.Context(), userusecase.CreateInput{
+		Username: req.Username,
+		Password: req.Password,
+		Role:     req.Role,
+	})
+	if err != nil {
+		switch err {
+		case userusecase.ErrInvalidInput:
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid username, password or role"})
+			return
+		case userusecase.ErrUsernameConflict:
+			c.JSON(http.StatusConflict, gin.H{"error": "username already exists"})
+			return
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+			return
+		}
+	}
+
+	c.JSON(http.StatusCreated, mapToResponse(res))
+}
+
+/*Метод преобразования из userusecase.UserDTO в UserResponse*/
+func mapToResponse(u userusecase.UserDTO) UserResponse {
+	return UserResponse{
+		ID:        u.ID,
+		Username:  u.Username,
+		Role:      u.Role,
+		CreatedAt: u.CreatedAt,
+	}
+}
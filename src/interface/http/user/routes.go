@@ -0,0 +1,21 @@
+package user
+
+import (
+	"link-service/src/interface/http/auth"
+
+	"github.com/gin-gonic/gin"
+)
+
+/*Метод регистрации маршрута входа (без API ключа: по нему токен и получают); loginLimit ограничивает попытки по IP*/
+func RegisterPublicRoutes(router *gin.RouterGroup, h *Handler, loginLimit gin.HandlerFunc) {
+	router.POST("/auth/login", loginLimit, h.Login) /*Маршрут для входа по имени и паролю*/
+}
+
+/*Метод регистрации маршрутов выхода и управления пользователями (управление — только для администраторов)*/
+func RegisterRoutes(router *gin.RouterGroup, h *Handler) {
+	router.POST("/auth/logout", h.Logout) /*Маршрут для выхода*/
+
+	users := router.Group("/users", auth.RequireAdmin())
+	users.GET("", h.List)    /*Маршрут для получения списка пользователей*/
+	users.POST("", h.Create) /*Маршрут для создания пользователя*/
+}
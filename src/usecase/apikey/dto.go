@@ -0,0 +1,66 @@
+package apikeyusecase
+
+import (
+	"slices"
+	"strconv"
+	"time"
+
+	"link-service/src/domain/link"
+)
+
+/*DTO для работы с API ключами*/
+type APIKeyDTO struct {
+	ID         int64      /*Идентификатор ключа*/
+	Name       string     /*Название ключа*/
+	Prefix     string     /*Начало ключа для отображения*/
+	Scopes     []string   /*Разрешения ключа*/
+	CreatedAt  time.Time  /*Дата создания*/
+	LastUsedAt *time.Time /*Дата последнего использования (с точностью до touchInterval)*/
+	RevokedAt  *time.Time /*Дата отзыва*/
+	OwnerID    *int64     /*Пользователь, от имени которого действует ключ*/
+}
+
+/*DTO созданного ключа*/
+type CreatedAPIKeyDTO struct {
+	APIKeyDTO
+	Key string /*Ключ целиком (больше нигде не отдаётся)*/
+}
+
+/*Владелец ключа или пользователь, выполняющий запрос*/
+type Principal struct {
+	KeyID   int64    /*Идентификатор ключа (0 — административный ключ из конфигурации или сессия пользователя)*/
+	UserID  int64    /*Идентификатор пользователя (0 — запрос с API ключом)*/
+	OwnerID int64    /*Владелец API ключа: ключ видит только его ссылки*/
+	Name    string   /*Название ключа или имя пользователя*/
+	Scopes  []string /*Разрешения ключа*/
+	Admin   bool     /*Административный ключ из конфигурации или пользователь-администратор: все разрешения, все ссылки, управление ключами и пользователями*/
+}
+
+/*Метод проверки разрешения*/
+func (p Principal) Has(scope string) bool {
+	return p.Admin || slices.Contains(p.Scopes, scope)
+}
+
+/*Метод получения автора изменений для истории ссылок*/
+func (p Principal) Actor() string {
+	switch {
+	case p.UserID != 0:
+		return "user:" + strconv.FormatInt(p.UserID, 10)
+	case p.Admin:
+		return "admin"
+	default:
+		return "api_key:" + strconv.FormatInt(p.KeyID, 10)
+	}
+}
+
+/*Метод получения доступа к ссылкам: все ссылки видит только администратор, пользователь — свои, API ключ — ссылки своего владельца*/
+func (p Principal) Access() link.Access {
+	switch {
+	case p.Admin:
+		return link.Access{UserID: p.UserID, All: true}
+	case p.UserID != 0:
+		return link.Access{UserID: p.UserID}
+	default:
+		return link.Access{UserID: p.OwnerID}
+	}
+}
@@ -0,0 +1,14 @@
+package apikeyusecase
+
+import "errors"
+
+var (
+	/*Не найден*/
+	ErrNotFound = errors.New("api key not found")
+	/*Ключ не передан, неизвестен или отозван*/
+	ErrUnauthorized = errors.New("invalid api key")
+	/*Невалидный ввод*/
+	ErrInvalidInput = errors.New("invalid input")
+	/*Владелец ключа не указан или не существует*/
+	ErrInvalidOwner = errors.New("invalid owner")
+)
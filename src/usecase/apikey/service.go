@@ -0,0 +1,230 @@
+package apikeyusecase
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"log"
+	"slices"
+	"strings"
+	"time"
+
+	domain "link-service/src/domain/apikey"
+	"link-service/src/domain/entity"
+	"link-service/src/domain/link"
+	"link-service/src/domain/user"
+)
+
+const (
+	/*Префикс выдаваемых ключей (чтобы ключ было легко узнать в логах и секретах)*/
+	keyPrefix = "lsk_"
+	/*Длина начала ключа, сохраняемого для отображения*/
+	displayPrefixLen = len(keyPrefix) + 8
+	/*Дата последнего использования обновляется не чаще этого интервала, чтобы не писать в базу на каждый запрос*/
+	touchInterval = time.Minute
+	/*Максимальная длина названия ключа*/
+	maxNameLen = 100
+)
+
+/*Сервис для работы с API ключами*/
+type Service struct {
+	repo     domain.Repository
+	users    user.Repository   /*Сессии пользователей (nil — вход пользователей не поддерживается)*/
+	adminKey [sha256.Size]byte /*SHA-256 административного ключа*/
+	hasAdmin bool              /*Административный ключ задан*/
+	now      func() time.Time
+}
+
+/*Метод создания нового сервиса (users — сессии пользователей, adminKey — административный ключ из конфигурации, пусто — не задан)*/
+func NewService(repo domain.Repository, users user.Repository, adminKey string) *Service {
+	return &Service{
+		repo:     repo,
+		users:    users,
+		adminKey: sha256.Sum256([]byte(adminKey)),
+		hasAdmin: adminKey != "",
+		now:      time.Now,
+	}
+}
+
+/*Метод проверки ключа или токена сессии пользователя*/
+func (s *Service) Authenticate(ctx context.Context, token string) (Principal, error) {
+	if token == "" {
+		return Principal{}, ErrUnauthorized
+	}
+
+	if strings.HasPrefix(token, user.SessionTokenPrefix) {
+		return s.authenticateSession(ctx, token)
+	}
+
+	sum := sha256.Sum256([]byte(token))
+	if s.hasAdmin && subtle.ConstantTimeCompare(sum[:], s.adminKey[:]) == 1 {
+		return Principal{Name: "admin", Admin: true}, nil
+	}
+
+	k, err := s.repo.GetByHash(ctx, hex.EncodeToString(sum[:]))
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return Principal{}, ErrUnauthorized
+		}
+		return Principal{}, err
+	}
+
+	now := s.now()
+	if k.LastUsedAt == nil || now.Sub(*k.LastUsedAt) >= touchInterval {
+		// ошибка записи даты использования не должна блокировать запрос
+		if err := s.repo.Touch(ctx, k.ID, now); err != nil {
+			log.Printf("api keys: touch key %d: %v", k.ID, err)
+		}
+	}
+
+	// ключ без владельца выдан до привязки ключей к пользователям: без владельца ему не к чему дать доступ
+	if k.OwnerID == nil {
+		return Principal{}, ErrUnauthorized
+	}
+
+	return Principal{KeyID: k.ID, OwnerID: *k.OwnerID, Name: k.Name, Scopes: k.Scopes}, nil
+}
+
+/*Метод проверки токена сессии: пользователю доступны все разрешения, но только на свои ссылки (кроме администратора)*/
+func (s *Service) authenticateSession(ctx context.Context, token string) (Principal, error) {
+	if s.users == nil {
+		return Principal{}, ErrUnauthorized
+	}
+
+	u, err := s.users.GetBySession(ctx, user.HashToken(token))
+	if err != nil {
+		if errors.Is(err, user.ErrNotFound) {
+			return Principal{}, ErrUnauthorized
+		}
+		return Principal{}, err
+	}
+
+	return Principal{
+		UserID: u.ID,
+		Name:   u.Username,
+		Scopes: slices.Clone(domain.Scopes),
+		Admin:  u.Role == user.RoleAdmin,
+	}, nil
+}
+
+/*Метод получения списка ключей*/
+func (s *Service) List(ctx context.Context) ([]APIKeyDTO, error) {
+	keys, err := s.repo.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]APIKeyDTO, 0, len(keys))
+	for _, k := range keys {
+		res = append(res, toDTO(k))
+	}
+
+	return res, nil
+}
+
+/*Метод создания ключа*/
+func (s *Service) Create(ctx context.Context, in CreateInput) (CreatedAPIKeyDTO, error) {
+	name := strings.TrimSpace(in.Name)
+	if name == "" || len(name) > maxNameLen {
+		return CreatedAPIKeyDTO{}, ErrInvalidInput
+	}
+
+	scopes := make([]string, 0, len(in.Scopes))
+	for _, scope := range in.Scopes {
+		if !domain.IsValidScope(scope) {
+			return CreatedAPIKeyDTO{}, ErrInvalidInput
+		}
+		if !slices.Contains(scopes, scope) {
+			scopes = append(scopes, scope)
+		}
+	}
+	if len(scopes) == 0 {
+		return CreatedAPIKeyDTO{}, ErrInvalidInput
+	}
+
+	ownerID, err := s.ownerForKey(ctx, in.OwnerID)
+	if err != nil {
+		return CreatedAPIKeyDTO{}, err
+	}
+
+	key, err := generateKey()
+	if err != nil {
+		return CreatedAPIKeyDTO{}, err
+	}
+	sum := sha256.Sum256([]byte(key))
+
+	k, err := s.repo.Create(ctx, domain.CreateInput{
+		Name:    name,
+		Prefix:  key[:displayPrefixLen],
+		KeyHash: hex.EncodeToString(sum[:]),
+		Scopes:  scopes,
+		OwnerID: ownerID,
+	})
+	if err != nil {
+		return CreatedAPIKeyDTO{}, err
+	}
+
+	return CreatedAPIKeyDTO{APIKeyDTO: toDTO(k), Key: key}, nil
+}
+
+/*Метод отзыва ключа*/
+func (s *Service) Revoke(ctx context.Context, id int64) error {
+	if err := s.repo.Revoke(ctx, id); err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return ErrNotFound
+		}
+		return err
+	}
+
+	return nil
+}
+
+/*Метод выбора владельца ключа: указанный явно или пользователь, создающий ключ (административному ключу из конфигурации владельца нужно указать)*/
+func (s *Service) ownerForKey(ctx context.Context, ownerID *int64) (int64, error) {
+	id := link.UserFrom(ctx)
+	if ownerID != nil {
+		id = *ownerID
+	}
+	if id <= 0 || s.users == nil {
+		return 0, ErrInvalidOwner
+	}
+
+	if _, err := s.users.Get(ctx, id); err != nil {
+		if errors.Is(err, user.ErrNotFound) {
+			return 0, ErrInvalidOwner
+		}
+		return 0, err
+	}
+
+	return id, nil
+}
+
+/*Метод генерации ключа: префикс и 32 случайных байта*/
+func generateKey() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return keyPrefix + base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+/*Метод преобразования из entity.APIKey в APIKeyDTO*/
+func toDTO(k entity.APIKey) APIKeyDTO {
+	return APIKeyDTO{
+		ID:         k.ID,
+		Name:       k.Name,
+		Prefix:     k.Prefix,
+		Scopes:     k.Scopes,
+		CreatedAt:  k.CreatedAt,
+		LastUsedAt: k.LastUsedAt,
+		RevokedAt:  k.RevokedAt,
+		OwnerID:    k.OwnerID,
+	}
+}
+
+var _ UseCase = (*Service)(nil)
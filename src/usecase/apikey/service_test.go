@@ -0,0 +1,147 @@
+package apikeyusecase
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"testing"
+	"time"
+
+	domain "link-service/src/domain/apikey"
+	"link-service/src/domain/entity"
+	"link-service/src/domain/link"
+	"link-service/src/domain/user"
+
+	"github.com/go-playground/assert/v2"
+)
+
+type keyRepo struct {
+	domain.Repository
+
+	keys    []entity.APIKey
+	touches int
+}
+
+func (r *keyRepo) Create(ctx context.Context, in domain.CreateInput) (entity.APIKey, error) {
+	ownerID := in.OwnerID
+	k := entity.APIKey{ID: int64(len(r.keys) + 1), Name: in.Name, Prefix: in.Prefix, KeyHash: in.KeyHash, Scopes: in.Scopes, OwnerID: &ownerID}
+	r.keys = append(r.keys, k)
+	return k, nil
+}
+
+func (r *keyRepo) GetByHash(ctx context.Context, keyHash string) (entity.APIKey, error) {
+	for _, k := range r.keys {
+		if k.KeyHash == keyHash && k.RevokedAt == nil {
+			return k, nil
+		}
+	}
+	return entity.APIKey{}, domain.ErrNotFound
+}
+
+func (r *keyRepo) Revoke(ctx context.Context, id int64) error {
+	for i := range r.keys {
+		if r.keys[i].ID == id && r.keys[i].RevokedAt == nil {
+			now := time.Now()
+			r.keys[i].RevokedAt = &now
+			return nil
+		}
+	}
+	return domain.ErrNotFound
+}
+
+func (r *keyRepo) Touch(ctx context.Context, id int64, usedAt time.Time) error {
+	r.touches++
+	r.keys[id-1].LastUsedAt = &usedAt
+	return nil
+}
+
+type userRepo struct {
+	user.Repository
+}
+
+func (r userRepo) Get(ctx context.Context, id int64) (entity.User, error) {
+	if id != 7 {
+		return entity.User{}, user.ErrNotFound
+	}
+	return entity.User{ID: id, Username: "alice", Role: user.RoleMember}, nil
+}
+
+func TestServiceCreateAuthenticateRevoke(t *testing.T) {
+	ctx := context.Background()
+	repo := &keyRepo{}
+	s := NewService(repo, userRepo{}, "bootstrap")
+	owner := int64(7)
+
+	now := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	s.now = func() time.Time { return now }
+
+	_, err := s.Create(ctx, CreateInput{Name: "ci", Scopes: []string{"links:write", "links:admin"}})
+	assert.Equal(t, ErrInvalidInput, err)
+	_, err = s.Create(ctx, CreateInput{Name: " ", Scopes: []string{"links:read"}})
+	assert.Equal(t, ErrInvalidInput, err)
+
+	// административному ключу из конфигурации владельца нужно указать явно, и он должен существовать
+	_, err = s.Create(ctx, CreateInput{Name: "ci", Scopes: []string{"links:read"}})
+	assert.Equal(t, ErrInvalidOwner, err)
+	missing := int64(99)
+	_, err = s.Create(ctx, CreateInput{Name: "ci", Scopes: []string{"links:read"}, OwnerID: &missing})
+	assert.Equal(t, ErrInvalidOwner, err)
+
+	created, err := s.Create(ctx, CreateInput{Name: "ci", Scopes: []string{"links:write", "links:read", "links:write"}, OwnerID: &owner})
+	assert.Equal(t, nil, err)
+	assert.Equal(t, true, strings.HasPrefix(created.Key, "lsk_"))
+	assert.Equal(t, created.Key[:12], created.Prefix)
+	assert.Equal(t, []string{"links:write", "links:read"}, created.Scopes)
+	// в базе только хеш
+	assert.NotEqual(t, created.Key, repo.keys[0].KeyHash)
+
+	p, err := s.Authenticate(ctx, created.Key)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, Principal{KeyID: 1, OwnerID: 7, Name: "ci", Scopes: []string{"links:write", "links:read"}}, p)
+	assert.Equal(t, true, p.Has("links:read"))
+	assert.Equal(t, false, p.Has("visits:read"))
+	// ключ видит только ссылки владельца
+	assert.Equal(t, link.Access{UserID: 7}, p.Access())
+
+	// дата использования обновляется не чаще touchInterval
+	_, _ = s.Authenticate(ctx, created.Key)
+	assert.Equal(t, 1, repo.touches)
+	now = now.Add(touchInterval)
+	_, _ = s.Authenticate(ctx, created.Key)
+	assert.Equal(t, 2, repo.touches)
+
+	admin, err := s.Authenticate(ctx, "bootstrap")
+	assert.Equal(t, nil, err)
+	assert.Equal(t, true, admin.Has("visits:read"))
+	assert.Equal(t, "admin", admin.Actor())
+	assert.Equal(t, link.Access{All: true}, admin.Access())
+
+	// пользователь, создающий ключ, по умолчанию становится его владельцем
+	asUser := link.WithAccess(ctx, link.Access{UserID: 7, All: true})
+	own, err := s.Create(asUser, CreateInput{Name: "mine", Scopes: []string{"links:read"}})
+	assert.Equal(t, nil, err)
+	assert.Equal(t, &owner, own.OwnerID)
+
+	// ключ, выданный до привязки к пользователям, больше не принимается
+	legacy := sha256.Sum256([]byte("lsk_legacy"))
+	repo.keys = append(repo.keys, entity.APIKey{ID: 3, KeyHash: hex.EncodeToString(legacy[:])})
+	_, err = s.Authenticate(ctx, "lsk_legacy")
+	assert.Equal(t, ErrUnauthorized, err)
+
+	assert.Equal(t, nil, s.Revoke(ctx, 1))
+	assert.Equal(t, ErrNotFound, s.Revoke(ctx, 1))
+	_, err = s.Authenticate(ctx, created.Key)
+	assert.Equal(t, ErrUnauthorized, err)
+	_, err = s.Authenticate(ctx, "")
+	assert.Equal(t, ErrUnauthorized, err)
+}
+
+func TestServiceWithoutAdminKey(t *testing.T) {
+	s := NewService(&keyRepo{}, nil, "")
+
+	_, err := s.Authenticate(context.Background(), "")
+	assert.Equal(t, ErrUnauthorized, err)
+	_, err = s.Authenticate(context.Background(), "anything")
+	assert.Equal(t, ErrUnauthorized, err)
+}
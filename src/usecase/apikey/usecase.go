@@ -0,0 +1,22 @@
+package apikeyusecase
+
+import "context"
+
+/*Интерфейс для работы с API ключами*/
+type UseCase interface {
+	/*Метод проверки ключа из заголовка Authorization (ErrUnauthorized — ключ неизвестен или отозван)*/
+	Authenticate(ctx context.Context, token string) (Principal, error)
+	/*Метод получения списка ключей, включая отозванные*/
+	List(ctx context.Context) ([]APIKeyDTO, error)
+	/*Метод создания ключа (сам ключ возвращается только здесь)*/
+	Create(ctx context.Context, in CreateInput) (CreatedAPIKeyDTO, error)
+	/*Метод отзыва ключа*/
+	Revoke(ctx context.Context, id int64) error
+}
+
+/*DTO для создания ключа*/
+type CreateInput struct {
+	Name    string   /*Название ключа*/
+	Scopes  []string /*Разрешения ключа*/
+	OwnerID *int64   /*Пользователь, от имени которого действует ключ (nil — пользователь, создающий ключ)*/
+}
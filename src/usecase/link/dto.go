@@ -1,9 +1,72 @@
 package linkusecase
 
+import (
+	"encoding/json"
+	"time"
+)
+
 /*DTO для работы с ссылками*/
 type LinkDTO struct {
-	ID          int64  /*Идентификатор ссылки*/
-	OriginalURL string /*Исходная ссылка*/
-	ShortName   string /*Короткая ссылка*/
-	ShortURL    string /*Короткая ссылка*/
+	ID           int64      /*Идентификатор ссылки*/
+	OriginalURL  string     /*Исходная ссылка*/
+	ShortName    string     /*Короткая ссылка*/
+	ShortURL     string     /*Короткая ссылка*/
+	ExpiresAt    *time.Time /*Дата истечения срока действия*/
+	MaxVisits    *int       /*Лимит посещений*/
+	VisitCount   int64      /*Учтённые посещения (для кешированной ссылки может отставать)*/
+	HasPassword  bool       /*Ссылка защищена паролем*/
+	RedirectType int        /*HTTP статус редиректа*/
+	DeletedAt    *time.Time /*Дата перемещения в корзину (nil — ссылка активна)*/
+	OwnerID      *int64     /*Владелец ссылки (nil — без владельца)*/
+}
+
+/*DTO записи истории изменений ссылки*/
+type LinkEventDTO struct {
+	ID             int64     /*Идентификатор записи*/
+	LinkID         int64     /*Идентификатор ссылки*/
+	Type           string    /*Тип изменения: created, updated, deleted, restored*/
+	OldOriginalURL string    /*Исходная ссылка до изменения*/
+	NewOriginalURL string    /*Исходная ссылка после изменения*/
+	OldShortName   string    /*Короткая ссылка до изменения*/
+	NewShortName   string    /*Короткая ссылка после изменения*/
+	Actor          string    /*Кто выполнил изменение (пусто — неизвестно)*/
+	RequestID      string    /*Идентификатор HTTP запроса*/
+	CreatedAt      time.Time /*Дата изменения*/
+}
+
+/*Метод проверки, что лимит посещений исчерпан по известному счётчику*/
+func (l LinkDTO) LimitReached() bool {
+	return l.MaxVisits != nil && l.VisitCount >= int64(*l.MaxVisits)
+}
+
+/*Метод проверки истечения срока действия ссылки*/
+func (l LinkDTO) Expired(now time.Time) bool {
+	return l.ExpiresAt != nil && !now.Before(*l.ExpiresAt)
+}
+
+/*Поле обновления, которое может быть не передано, передано как null или со значением*/
+type Optional[T any] struct {
+	Set   bool /*Поле передано (в том числе null)*/
+	Value *T   /*Новое значение (nil — очистить)*/
+}
+
+/*Метод создания переданного поля со значением (nil — очистить)*/
+func Some[T any](v *T) Optional[T] {
+	return Optional[T]{Set: true, Value: v}
+}
+
+/*Метод разбора JSON: отсутствующее поле не вызывает его, поэтому Set остаётся false*/
+func (o *Optional[T]) UnmarshalJSON(data []byte) error {
+	o.Set = true
+	if string(data) == "null" {
+		o.Value = nil
+		return nil
+	}
+
+	var v T
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	o.Value = &v
+	return nil
 }
@@ -9,4 +9,12 @@ var (
 	ErrShortNameConflict = errors.New("short_name already exists")
 	/*Невалидный ввод*/
 	ErrInvalidInput      = errors.New("invalid input")
+	/*Неверный пароль*/
+	ErrInvalidPassword   = errors.New("invalid password")
+	/*Слишком много строк импорта*/
+	ErrTooManyRows       = errors.New("too many rows")
+	/*Недостаточно прав*/
+	ErrForbidden         = errors.New("forbidden")
+	/*Пароль короче 4 или длиннее 72 байт (предел bcrypt)*/
+	ErrInvalidPasswordLength = errors.New("invalid password length")
 )
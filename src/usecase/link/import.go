@@ -0,0 +1,207 @@
+package linkusecase
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"unicode/utf8"
+
+	domain "link-service/src/domain/link"
+)
+
+const (
+	/*Количество строк импорта в одной транзакции*/
+	importChunkSize = 500
+	/*Максимальное количество строк в одном импорте*/
+	MaxImportRows = 10000
+	/*Допустимая длина short_name (как в запросе создания ссылки)*/
+	minShortNameLen = 3
+	maxShortNameLen = 32
+)
+
+/*Статус строки импорта*/
+type ImportStatus string
+
+const (
+	ImportCreated  ImportStatus = "created"  /*Ссылка создана*/
+	ImportConflict ImportStatus = "conflict" /*short_name уже занят*/
+	ImportInvalid  ImportStatus = "invalid"  /*Строка не прошла валидацию*/
+	ImportSkipped  ImportStatus = "skipped"  /*Строка корректна, но импорт all_or_nothing отменён*/
+)
+
+/*Строка импорта ссылок*/
+type ImportRow struct {
+	OriginalURL string /*Исходная ссылка*/
+	ShortName   string /*Короткая ссылка (пустая — сгенерировать)*/
+}
+
+/*Результат импорта одной строки*/
+type ImportRowResult struct {
+	Row    int          /*Номер строки (с 1)*/
+	Status ImportStatus /*Статус строки*/
+	Error  string       /*Причина для invalid и conflict*/
+	Link   *LinkDTO     /*Созданная ссылка (для created)*/
+}
+
+/*Отчёт об импорте ссылок*/
+type ImportResult struct {
+	Created   int               /*Создано ссылок*/
+	Conflicts int               /*Строк с занятым short_name*/
+	Invalid   int               /*Некорректных строк*/
+	Aborted   bool              /*all_or_nothing: из-за ошибок в строках ничего не создано*/
+	Rows      []ImportRowResult /*Результаты по строкам в порядке ввода*/
+}
+
+/*Метод импорта ссылок: строки проверяются по правилам Create и создаются транзакционными пачками (allOrNothing — одна транзакция, при любой ошибке ничего не создаётся)*/
+func (s *Service) Import(ctx context.Context, rows []ImportRow, allOrNothing bool) (ImportResult, error) {
+	if len(rows) > MaxImportRows {
+		return ImportResult{}, ErrTooManyRows
+	}
+
+	res := ImportResult{Rows: make([]ImportRowResult, len(rows))}
+	var valid []importItem
+
+	for i, row := range rows {
+		res.Rows[i].Row = i + 1
+
+		item, err := newImportItem(i, row)
+		if err != nil {
+			res.Rows[i].Status = ImportInvalid
+			res.Rows[i].Error = err.Error()
+			res.Invalid++
+			continue
+		}
+
+		valid = append(valid, item)
+	}
+
+	if allOrNothing {
+		if res.Invalid > 0 {
+			res.Aborted = true
+			for _, item := range valid {
+				res.Rows[item.row].Status = ImportSkipped
+			}
+			return res, nil
+		}
+		if err := s.importChunk(ctx, &res, valid, true); err != nil {
+			return ImportResult{}, err
+		}
+		return res, nil
+	}
+
+	for start := 0; start < len(valid); start += importChunkSize {
+		end := min(start+importChunkSize, len(valid))
+		if err := s.importChunk(ctx, &res, valid[start:end], false); err != nil {
+			return ImportResult{}, err
+		}
+	}
+
+	return res, nil
+}
+
+/*Проверенная строка импорта*/
+type importItem struct {
+	row       int                /*Индекс строки во входных данных*/
+	in        domain.CreateInput /*Параметры создания*/
+	generated bool               /*short_name не задан и генерируется*/
+}
+
+/*Метод проверки строки импорта по правилам Create*/
+func newImportItem(i int, row ImportRow) (importItem, error) {
+	originalURL, err := normalizeOriginalURL(row.OriginalURL)
+	if err != nil {
+		return importItem{}, errors.New("invalid original_url")
+	}
+
+	shortName := strings.TrimSpace(row.ShortName)
+	if n := utf8.RuneCountInString(shortName); shortName != "" && (n < minShortNameLen || n > maxShortNameLen) {
+		return importItem{}, errors.New("short_name must be 3 to 32 characters")
+	}
+
+	return importItem{
+		row: i,
+		in: domain.CreateInput{
+			OriginalURL:  originalURL,
+			ShortName:    shortName,
+			RedirectType: domain.DefaultRedirectType,
+		},
+		generated: shortName == "",
+	}, nil
+}
+
+/*Метод создания пачки ссылок в одной транзакции с повторной генерацией занятых сгенерированных short_name*/
+func (s *Service) importChunk(ctx context.Context, res *ImportResult, items []importItem, atomic bool) error {
+	ownerID, err := ownerForCreate(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	out := make([]domain.BatchResult, len(items))
+	todo := make([]int, len(items))
+	for k := range items {
+		todo[k] = k
+	}
+
+	aborted, explicit := false, false
+	for attempt := 0; attempt < shortNameAttempts && len(todo) > 0; attempt++ {
+		batch := make([]domain.CreateInput, len(todo))
+		for k, j := range todo {
+			if items[j].generated {
+				items[j].in.ShortName = generateShortName(6)
+			}
+			batch[k] = items[j].in
+			batch[k].OwnerID = ownerID
+		}
+
+		results, err := s.repo.CreateBatch(ctx, batch, atomic)
+		aborted = errors.Is(err, domain.ErrShortNameConflict)
+		if err != nil && !aborted {
+			return err
+		}
+
+		var retry []int
+		explicit = false
+		for k, r := range results {
+			j := todo[k]
+			out[j] = r
+			if r.Conflict {
+				if items[j].generated {
+					retry = append(retry, j)
+				} else {
+					explicit = true
+				}
+			}
+		}
+
+		// в атомарном режиме конфликт сгенерированного имени откатывает всю транзакцию — повторяем её целиком
+		if atomic && aborted && !explicit {
+			retry = todo
+		}
+		if atomic && explicit {
+			break
+		}
+		todo = retry
+	}
+
+	for k, item := range items {
+		r := &res.Rows[item.row]
+		switch {
+		// сгенерированное имя в откатившейся транзакции получило бы новое значение — строка не конфликтует
+		case out[k].Conflict && !(aborted && explicit && item.generated):
+			r.Status = ImportConflict
+			r.Error = ErrShortNameConflict.Error()
+			res.Conflicts++
+		case aborted:
+			r.Status = ImportSkipped
+		default:
+			dto := s.toDTO(out[k].Link)
+			r.Status = ImportCreated
+			r.Link = &dto
+			res.Created++
+		}
+	}
+
+	res.Aborted = aborted
+
+	return nil
+}
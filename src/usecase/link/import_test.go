@@ -0,0 +1,133 @@
+package linkusecase
+
+import (
+	"context"
+	"testing"
+
+	"link-service/src/domain/entity"
+	domain "link-service/src/domain/link"
+
+	"github.com/go-playground/assert/v2"
+)
+
+type importRepo struct {
+	domain.Repository
+
+	taken      map[string]bool
+	collisions int /*Сколько сгенерированных имён подряд считать занятыми*/
+	calls      int
+	nextID     int64
+}
+
+func (r *importRepo) CreateBatch(ctx context.Context, in []domain.CreateInput, atomic bool) ([]domain.BatchResult, error) {
+	r.calls++
+
+	res := make([]domain.BatchResult, len(in))
+	created := map[string]bool{}
+	conflict := false
+	for i, v := range in {
+		if r.taken[v.ShortName] || created[v.ShortName] || (len(v.ShortName) == 6 && r.collisions > 0) {
+			if len(v.ShortName) == 6 && r.collisions > 0 {
+				r.collisions--
+			}
+			res[i].Conflict = true
+			conflict = true
+			continue
+		}
+		created[v.ShortName] = true
+		r.nextID++
+		res[i].Link = entity.Link{ID: r.nextID, OriginalURL: v.OriginalURL, ShortName: v.ShortName, RedirectType: v.RedirectType}
+	}
+
+	if atomic && conflict {
+		return res, domain.ErrShortNameConflict
+	}
+	for name := range created {
+		r.taken[name] = true
+	}
+
+	return res, nil
+}
+
+func statuses(res ImportResult) []ImportStatus {
+	out := make([]ImportStatus, 0, len(res.Rows))
+	for _, r := range res.Rows {
+		out = append(out, r.Status)
+	}
+	return out
+}
+
+func TestImportReportsEachRow(t *testing.T) {
+	repo := &importRepo{taken: map[string]bool{"taken": true}, collisions: 1}
+	s := NewService(repo, "http://localhost")
+
+	res, err := s.Import(context.Background(), []ImportRow{
+		{OriginalURL: "https://a.example", ShortName: " first "},
+		{OriginalURL: "not a url", ShortName: "bad-url"},
+		{OriginalURL: "https://b.example", ShortName: "ab"},
+		{OriginalURL: "https://c.example", ShortName: "taken"},
+		{OriginalURL: "https://d.example"},
+		{OriginalURL: "https://e.example", ShortName: "first"},
+	}, false)
+	assert.Equal(t, nil, err)
+
+	assert.Equal(t, []ImportStatus{ImportCreated, ImportInvalid, ImportInvalid, ImportConflict, ImportCreated, ImportConflict}, statuses(res))
+	assert.Equal(t, 2, res.Created)
+	assert.Equal(t, 2, res.Conflicts)
+	assert.Equal(t, 2, res.Invalid)
+	assert.Equal(t, false, res.Aborted)
+	assert.Equal(t, "first", res.Rows[0].Link.ShortName)
+	assert.Equal(t, "http://localhost/r/first", res.Rows[0].Link.ShortURL)
+	assert.Equal(t, 6, len(res.Rows[4].Link.ShortName))
+	assert.Equal(t, 2, repo.calls) // повтор только для сгенерированного имени
+}
+
+func TestImportAllOrNothing(t *testing.T) {
+	repo := &importRepo{taken: map[string]bool{"taken": true}}
+	s := NewService(repo, "http://localhost")
+	ctx := context.Background()
+
+	res, err := s.Import(ctx, []ImportRow{
+		{OriginalURL: "https://a.example", ShortName: "one"},
+		{OriginalURL: "https://b.example", ShortName: "taken"},
+		{OriginalURL: "https://c.example"},
+	}, true)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, true, res.Aborted)
+	assert.Equal(t, []ImportStatus{ImportSkipped, ImportConflict, ImportSkipped}, statuses(res))
+	assert.Equal(t, 0, res.Created)
+	assert.Equal(t, false, repo.taken["one"])
+
+	res, err = s.Import(ctx, []ImportRow{
+		{OriginalURL: "https://a.example", ShortName: "one"},
+		{OriginalURL: "", ShortName: "two"},
+	}, true)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, true, res.Aborted)
+	assert.Equal(t, []ImportStatus{ImportSkipped, ImportInvalid}, statuses(res))
+	assert.Equal(t, 1, repo.calls)
+
+	repo.collisions = 1
+	res, err = s.Import(ctx, []ImportRow{
+		{OriginalURL: "https://a.example", ShortName: "one"},
+		{OriginalURL: "https://b.example"},
+	}, true)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, false, res.Aborted)
+	assert.Equal(t, []ImportStatus{ImportCreated, ImportCreated}, statuses(res))
+	assert.Equal(t, true, repo.taken["one"])
+	assert.Equal(t, 3, repo.calls)
+
+	_, err = s.Import(ctx, make([]ImportRow, MaxImportRows+1), false)
+	assert.Equal(t, ErrTooManyRows, err)
+}
+
+func TestImportNormalizesLikeCreate(t *testing.T) {
+	item, err := newImportItem(0, ImportRow{OriginalURL: "  https://example.com/a \n"})
+	assert.Equal(t, nil, err)
+
+	originalURL, err := normalizeOriginalURL("  https://example.com/a \n")
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "https://example.com/a", originalURL)
+	assert.Equal(t, originalURL, item.in.OriginalURL)
+}
@@ -0,0 +1,110 @@
+package linkusecase
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"link-service/src/domain/entity"
+	domain "link-service/src/domain/link"
+
+	"github.com/go-playground/assert/v2"
+)
+
+type ownerRepo struct {
+	domain.Repository
+
+	links   []entity.Link
+	filters []domain.Filter
+	deleted []int64
+}
+
+func (r *ownerRepo) Get(ctx context.Context, id int64) (entity.Link, error) {
+	for _, l := range r.links {
+		if l.ID == id {
+			return l, nil
+		}
+	}
+	return entity.Link{}, domain.ErrNotFound
+}
+
+func (r *ownerRepo) Count(ctx context.Context, f domain.Filter) (int64, error) {
+	r.filters = append(r.filters, f)
+
+	var n int64
+	for _, l := range r.links {
+		if f.IDs != nil && !slices.Contains(f.IDs, l.ID) {
+			continue
+		}
+		if f.OwnerID != 0 && (l.OwnerID == nil || *l.OwnerID != f.OwnerID) {
+			continue
+		}
+		n++
+	}
+	return n, nil
+}
+
+func (r *ownerRepo) Delete(ctx context.Context, id int64) error {
+	r.deleted = append(r.deleted, id)
+	return nil
+}
+
+func TestServiceScopesLinksToOwner(t *testing.T) {
+	alice, bob := int64(1), int64(2)
+	repo := &ownerRepo{links: []entity.Link{
+		{ID: 10, ShortName: "alice", OwnerID: &alice},
+		{ID: 11, ShortName: "bob", OwnerID: &bob},
+	}}
+	s := NewService(repo, "http://localhost")
+
+	member := domain.WithAccess(context.Background(), domain.Access{UserID: alice})
+	admin := domain.WithAccess(context.Background(), domain.Access{UserID: bob, All: true})
+
+	_, err := s.Get(member, 10)
+	assert.Equal(t, nil, err)
+	_, err = s.Get(member, 11)
+	assert.Equal(t, ErrNotFound, err)
+	_, err = s.Get(admin, 10)
+	assert.Equal(t, nil, err)
+
+	n, err := s.Count(member, domain.Filter{})
+	assert.Equal(t, nil, err)
+	assert.Equal(t, int64(1), n)
+	// владелец в фильтре из запроса не даёт обойти доступ
+	n, err = s.Count(member, domain.Filter{OwnerID: bob})
+	assert.Equal(t, nil, err)
+	assert.Equal(t, int64(1), n)
+	n, err = s.Count(admin, domain.Filter{})
+	assert.Equal(t, nil, err)
+	assert.Equal(t, int64(2), n)
+
+	assert.Equal(t, ErrNotFound, s.Delete(member, 11))
+	assert.Equal(t, nil, s.Delete(member, 10))
+	assert.Equal(t, nil, s.Delete(admin, 11))
+	assert.Equal(t, []int64{10, 11}, repo.deleted)
+
+	_, err = s.Update(member, 10, UpdateInput{OriginalURL: "https://example.com", OwnerID: &bob})
+	assert.Equal(t, ErrForbidden, err)
+}
+
+func TestOwnerForCreate(t *testing.T) {
+	alice, bob := int64(1), int64(2)
+	member := domain.WithAccess(context.Background(), domain.Access{UserID: alice})
+	admin := domain.WithAccess(context.Background(), domain.Access{UserID: bob, All: true})
+
+	owner, err := ownerForCreate(member, nil)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, alice, *owner)
+
+	_, err = ownerForCreate(member, &bob)
+	assert.Equal(t, ErrForbidden, err)
+
+	owner, err = ownerForCreate(admin, &alice)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, alice, *owner)
+
+	// API ключ без пользователя создаёт ссылку без владельца
+	owner, err = ownerForCreate(domain.WithAccess(context.Background(), domain.Access{All: true}), nil)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, (*int64)(nil), owner)
+}
@@ -8,10 +8,22 @@ import (
 	"net/url"
 	"strings"
 
+	"golang.org/x/crypto/bcrypt"
+
 	"link-service/src/domain/entity"
 	domain "link-service/src/domain/link"
 )
 
+/*Количество попыток сгенерировать свободный short_name*/
+const shortNameAttempts = 8
+
+const (
+	/*Минимальная длина пароля ссылки*/
+	minPasswordLen = 4
+	/*Максимальная длина пароля в байтах (bcrypt учитывает только первые 72 байта)*/
+	maxPasswordLen = 72
+)
+
 /*Сервис для работы с ссылками*/
 type Service struct {
 	repo    domain.Repository
@@ -27,8 +39,9 @@ func NewService(repo domain.Repository, baseURL string) *Service {
 }
 
 /*Метод получения списка ссылок*/
-func (s *Service) List(ctx context.Context) ([]LinkDTO, error) {
-	links, err := s.repo.List(ctx)
+func (s *Service) List(ctx context.Context, q domain.Query) ([]LinkDTO, error) {
+	q.Filter.OwnerID = domain.OwnerScope(ctx)
+	links, err := s.repo.List(ctx, q)
 	if err != nil {
 		return nil, err
 	}
@@ -42,8 +55,9 @@ func (s *Service) List(ctx context.Context) ([]LinkDTO, error) {
 }
 
 /*Метод получения списка ссылок*/
-func (s *Service) ListWithRange(ctx context.Context, rng *domain.Range) ([]LinkDTO, error) {
-	links, err := s.repo.ListWithRange(ctx, rng)
+func (s *Service) ListWithRange(ctx context.Context, rng *domain.Range, q domain.Query) ([]LinkDTO, error) {
+	q.Filter.OwnerID = domain.OwnerScope(ctx)
+	links, err := s.repo.ListWithRange(ctx, rng, q)
 	if err != nil {
 		return nil, err
 	}
@@ -56,17 +70,52 @@ func (s *Service) ListWithRange(ctx context.Context, rng *domain.Range) ([]LinkD
 	return res, nil
 }
 
-/*Метод получения общего количества ссылок*/
-func (s *Service) Count(ctx context.Context) (int64, error) {
-	return s.repo.Count(ctx)
+/*Метод получения страницы ссылок после курсора*/
+func (s *Service) ListAfter(ctx context.Context, cur *domain.Cursor) ([]LinkDTO, *domain.Cursor, error) {
+	// запрашиваем на одну запись больше, чтобы узнать, есть ли следующая страница
+	f := domain.Filter{OwnerID: domain.OwnerScope(ctx)}
+	links, err := s.repo.ListAfter(ctx, &domain.Cursor{AfterID: cur.AfterID, Limit: cur.Limit + 1}, f)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var next *domain.Cursor
+	if len(links) > cur.Limit {
+		links = links[:cur.Limit]
+		next = &domain.Cursor{AfterID: links[len(links)-1].ID, Limit: cur.Limit}
+	}
+
+	res := make([]LinkDTO, 0, len(links))
+	for _, l := range links {
+		res = append(res, s.toDTO(l))
+	}
+
+	return res, next, nil
+}
+
+/*Метод обхода ссылок с фильтром и сортировкой без загрузки всего списка в память*/
+func (s *Service) Each(ctx context.Context, q domain.Query, fn func(LinkDTO) error) error {
+	q.Filter.OwnerID = domain.OwnerScope(ctx)
+	return s.repo.Each(ctx, q, func(l entity.Link) error {
+		return fn(s.toDTO(l))
+	})
 }
 
-/*Метод получения ссылки по идентификатору*/
+/*Метод получения количества ссылок, подходящих под фильтр*/
+func (s *Service) Count(ctx context.Context, f domain.Filter) (int64, error) {
+	f.OwnerID = domain.OwnerScope(ctx)
+	return s.repo.Count(ctx, f)
+}
+
+/*Метод получения ссылки по идентификатору (чужая ссылка — ErrNotFound)*/
 func (s *Service) Get(ctx context.Context, id int64) (LinkDTO, error) {
 	l, err := s.repo.Get(ctx, id)
 	if err != nil {
 		return LinkDTO{}, mapDomainError(err)
 	}
+	if owner := domain.OwnerScope(ctx); owner != 0 && (l.OwnerID == nil || *l.OwnerID != owner) {
+		return LinkDTO{}, ErrNotFound
+	}
 	return s.toDTO(l), nil
 }
 
@@ -81,13 +130,41 @@ func (s *Service) GetByShortName(ctx context.Context, shortName string) (LinkDTO
 
 /*Метод создания новой ссылки*/
 func (s *Service) Create(ctx context.Context, in CreateInput) (LinkDTO, error) {
-	if err := validateOriginalURL(in.OriginalURL); err != nil {
+	originalURL, err := normalizeOriginalURL(in.OriginalURL)
+	if err != nil {
+		return LinkDTO{}, ErrInvalidInput
+	}
+
+	redirectType := in.RedirectType
+	if redirectType == 0 {
+		redirectType = domain.DefaultRedirectType
+	}
+	if !domain.IsValidRedirectType(redirectType) {
 		return LinkDTO{}, ErrInvalidInput
 	}
 
-	shortName := strings.TrimSpace(in.ShortName)
-	if shortName != "" {
-		l, err := s.repo.Create(ctx, in.OriginalURL, shortName)
+	ownerID, err := ownerForCreate(ctx, in.OwnerID)
+	if err != nil {
+		return LinkDTO{}, err
+	}
+
+	passwordHash, err := hashPassword(in.Password)
+	if err != nil {
+		return LinkDTO{}, err
+	}
+
+	repoIn := domain.CreateInput{
+		OriginalURL:  originalURL,
+		ShortName:    strings.TrimSpace(in.ShortName),
+		ExpiresAt:    in.ExpiresAt,
+		MaxVisits:    in.MaxVisits,
+		PasswordHash: passwordHash,
+		RedirectType: redirectType,
+		OwnerID:      ownerID,
+	}
+
+	if repoIn.ShortName != "" {
+		l, err := s.repo.Create(ctx, repoIn)
 		if err != nil {
 			return LinkDTO{}, mapDomainError(err)
 		}
@@ -96,10 +173,9 @@ func (s *Service) Create(ctx context.Context, in CreateInput) (LinkDTO, error) {
 	}
 
 	/*Если short_name не задан — генерируем уникальное имя.*/
-	const attempts = 8
-	for i := 0; i < attempts; i++ {
-		gen := generateShortName(6)
-		l, err := s.repo.Create(ctx, in.OriginalURL, gen)
+	for i := 0; i < shortNameAttempts; i++ {
+		repoIn.ShortName = generateShortName(6)
+		l, err := s.repo.Create(ctx, repoIn)
 
 		if err == nil {
 			return s.toDTO(l), nil
@@ -115,59 +191,243 @@ func (s *Service) Create(ctx context.Context, in CreateInput) (LinkDTO, error) {
 	return LinkDTO{}, ErrShortNameConflict
 }
 
-/*Метод обновления ссылки*/
+/*Метод обновления ссылки: не переданные поля сохраняют текущие значения*/
 func (s *Service) Update(ctx context.Context, id int64, in UpdateInput) (LinkDTO, error) {
-	if err := validateOriginalURL(in.OriginalURL); err != nil {
+	originalURL := in.OriginalURL
+	if originalURL != "" {
+		var err error
+		if originalURL, err = normalizeOriginalURL(originalURL); err != nil {
+			return LinkDTO{}, ErrInvalidInput
+		}
+	}
+
+	if in.RedirectType != 0 && !domain.IsValidRedirectType(in.RedirectType) {
 		return LinkDTO{}, ErrInvalidInput
 	}
 
-	shortName := strings.TrimSpace(in.ShortName)
-	if shortName == "" {
-		existing, err := s.repo.Get(ctx, id)
-		if err != nil {
-			return LinkDTO{}, mapDomainError(err)
+	var passwordHash string
+	if in.Password != nil {
+		var err error
+		if passwordHash, err = hashPassword(*in.Password); err != nil {
+			return LinkDTO{}, err
 		}
+	}
+
+	if in.OwnerID != nil && domain.OwnerScope(ctx) != 0 {
+		return LinkDTO{}, ErrForbidden
+	}
+
+	if err := s.authorize(ctx, id, false); err != nil {
+		return LinkDTO{}, err
+	}
+
+	existing, err := s.repo.Get(ctx, id)
+	if err != nil {
+		return LinkDTO{}, mapDomainError(err)
+	}
 
-		shortName = existing.ShortName
+	repoIn := domain.UpdateInput{
+		OriginalURL:  existing.OriginalURL,
+		ShortName:    existing.ShortName,
+		ExpiresAt:    existing.ExpiresAt,
+		MaxVisits:    existing.MaxVisits,
+		PasswordHash: existing.PasswordHash,
+		RedirectType: existing.RedirectType,
+		OwnerID:      in.OwnerID,
 	}
 
-	l, err := s.repo.Update(ctx, id, in.OriginalURL, shortName)
+	if originalURL != "" {
+		repoIn.OriginalURL = originalURL
+	}
+	if shortName := strings.TrimSpace(in.ShortName); shortName != "" {
+		repoIn.ShortName = shortName
+	}
+	if in.ExpiresAt.Set {
+		repoIn.ExpiresAt = in.ExpiresAt.Value
+	}
+	if in.MaxVisits.Set {
+		repoIn.MaxVisits = in.MaxVisits.Value
+	}
+	if in.RedirectType != 0 {
+		repoIn.RedirectType = in.RedirectType
+	}
+	if in.Password != nil {
+		repoIn.PasswordHash = passwordHash
+	}
+
+	l, err := s.repo.Update(ctx, id, repoIn)
+	if err != nil {
+		return LinkDTO{}, mapDomainError(err)
+	}
+
+	return s.toDTO(l), nil
+}
+
+/*Метод проверки лимита посещений по счётчику из хранилища: ссылка из кеша short_name может отставать*/
+func (s *Service) LimitReached(ctx context.Context, id int64) (bool, error) {
+	l, err := s.repo.Get(ctx, id)
+	if err != nil {
+		return false, mapDomainError(err)
+	}
+	return s.toDTO(l).LimitReached(), nil
+}
 
+/*Метод получения защищённой паролем ссылки по short_name с проверкой пароля*/
+func (s *Service) Unlock(ctx context.Context, shortName, password string) (LinkDTO, error) {
+	l, err := s.repo.GetByShortName(ctx, shortName)
 	if err != nil {
 		return LinkDTO{}, mapDomainError(err)
 	}
 
+	if l.PasswordHash == "" {
+		return s.toDTO(l), nil
+	}
+
+	if bcrypt.CompareHashAndPassword([]byte(l.PasswordHash), []byte(password)) != nil {
+		return LinkDTO{}, ErrInvalidPassword
+	}
+
 	return s.toDTO(l), nil
 }
 
-/*Метод удаления ссылки*/
+/*Метод перемещения ссылки в корзину*/
 func (s *Service) Delete(ctx context.Context, id int64) error {
+	if err := s.authorize(ctx, id, false); err != nil {
+		return err
+	}
 	return mapDomainError(s.repo.Delete(ctx, id))
 }
 
+/*Метод восстановления ссылки из корзины*/
+func (s *Service) Restore(ctx context.Context, id int64) (LinkDTO, error) {
+	if err := s.authorize(ctx, id, true); err != nil {
+		return LinkDTO{}, err
+	}
+
+	l, err := s.repo.Restore(ctx, id)
+	if err != nil {
+		return LinkDTO{}, mapDomainError(err)
+	}
+	return s.toDTO(l), nil
+}
+
+/*Метод получения истории изменений ссылки*/
+func (s *Service) History(ctx context.Context, id int64) ([]LinkEventDTO, error) {
+	// история есть и у ссылки в корзине, поэтому ищем владельца среди активных и удалённых
+	err := s.authorize(ctx, id, false)
+	if errors.Is(err, ErrNotFound) {
+		err = s.authorize(ctx, id, true)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	events, err := s.repo.History(ctx, id)
+	if err != nil {
+		return nil, mapDomainError(err)
+	}
+
+	res := make([]LinkEventDTO, 0, len(events))
+	for _, e := range events {
+		res = append(res, LinkEventDTO{
+			ID:             e.ID,
+			LinkID:         e.LinkID,
+			Type:           e.Type,
+			OldOriginalURL: e.OldOriginalURL,
+			NewOriginalURL: e.NewOriginalURL,
+			OldShortName:   e.OldShortName,
+			NewShortName:   e.NewShortName,
+			Actor:          e.Actor,
+			RequestID:      e.RequestID,
+			CreatedAt:      e.CreatedAt,
+		})
+	}
+
+	return res, nil
+}
+
 /*Метод преобразования из entity.Link в LinkDTO*/
 func (s *Service) toDTO(l entity.Link) LinkDTO {
 	return LinkDTO{
-		ID:          l.ID,
-		OriginalURL: l.OriginalURL,
-		ShortName:   l.ShortName,
-		ShortURL:    s.baseURL + "/r/" + l.ShortName,
+		ID:           l.ID,
+		OriginalURL:  l.OriginalURL,
+		ShortName:    l.ShortName,
+		ShortURL:     s.baseURL + "/r/" + l.ShortName,
+		ExpiresAt:    l.ExpiresAt,
+		MaxVisits:    l.MaxVisits,
+		VisitCount:   l.VisitCount,
+		HasPassword:  l.PasswordHash != "",
+		RedirectType: l.RedirectType,
+		DeletedAt:    l.DeletedAt,
+		OwnerID:      l.OwnerID,
 	}
 }
 
-/*Метод валидации исходной ссылки*/
-func validateOriginalURL(raw string) error {
+/*Метод проверки, что ссылка принадлежит пользователю из контекста (trashed — искать в корзине; чужая или несуществующая — ErrNotFound)*/
+func (s *Service) authorize(ctx context.Context, id int64, trashed bool) error {
+	owner := domain.OwnerScope(ctx)
+	if owner == 0 {
+		return nil
+	}
+
+	n, err := s.repo.Count(ctx, domain.Filter{IDs: []int64{id}, Trashed: trashed, OwnerID: owner})
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+/*Метод выбора владельца новой ссылки: явно — только при доступе ко всем ссылкам, иначе — пользователь из контекста (nil — без владельца)*/
+func ownerForCreate(ctx context.Context, ownerID *int64) (*int64, error) {
+	if ownerID != nil {
+		if domain.OwnerScope(ctx) != 0 {
+			return nil, ErrForbidden
+		}
+		return ownerID, nil
+	}
+
+	if id := domain.UserFrom(ctx); id != 0 {
+		return &id, nil
+	}
+
+	return nil, nil
+}
+
+/*Метод нормализации и валидации исходной ссылки (общий для создания, обновления и импорта)*/
+func normalizeOriginalURL(raw string) (string, error) {
 	raw = strings.TrimSpace(raw)
 	if raw == "" {
-		return ErrInvalidInput
+		return "", ErrInvalidInput
 	}
 
 	u, err := url.Parse(raw)
 	if err != nil || u.Scheme == "" || u.Host == "" {
-		return ErrInvalidInput
+		return "", ErrInvalidInput
 	}
 
-	return nil
+	return raw, nil
+}
+
+/*Метод получения соленого хеша пароля (bcrypt)*/
+func hashPassword(password string) (string, error) {
+	if password == "" {
+		return "", nil
+	}
+
+	if len(password) < minPasswordLen || len(password) > maxPasswordLen {
+		return "", ErrInvalidPasswordLength
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return "", err
+	}
+
+	return string(hash), nil
 }
 
 /*Метод генерации короткой ссылки*/
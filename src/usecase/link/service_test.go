@@ -0,0 +1,27 @@
+package linkusecase
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	domain "link-service/src/domain/link"
+
+	"github.com/go-playground/assert/v2"
+)
+
+func TestPasswordLengthCountsBytes(t *testing.T) {
+	s := NewService(&ownerRepo{}, "http://localhost")
+	ctx := domain.WithAccess(context.Background(), domain.SystemAccess)
+
+	// 40 символов кириллицы — 80 байт: больше предела bcrypt
+	long := strings.Repeat("п", 40)
+	_, err := s.Create(ctx, CreateInput{OriginalURL: "https://example.com", Password: long})
+	assert.Equal(t, ErrInvalidPasswordLength, err)
+
+	_, err = s.Create(ctx, CreateInput{OriginalURL: "https://example.com", Password: "abc"})
+	assert.Equal(t, ErrInvalidPasswordLength, err)
+
+	_, err = s.Update(ctx, 1, UpdateInput{OriginalURL: "https://example.com", ShortName: "abc", RedirectType: 302, Password: &long})
+	assert.Equal(t, ErrInvalidPasswordLength, err)
+}
@@ -0,0 +1,126 @@
+package linkusecase
+
+import (
+	"context"
+	"log"
+	"sync/atomic"
+	"time"
+
+	domain "link-service/src/domain/link"
+)
+
+/*Параметры корзины ссылок*/
+type TrashConfig struct {
+	MaxAge   time.Duration /*Срок хранения ссылок в корзине (0 — не удаляются)*/
+	Interval time.Duration /*Период фоновой очистки*/
+}
+
+/*Счётчики очистки корзины*/
+type TrashStats struct {
+	Enabled bool       `json:"enabled"`  /*Срок хранения задан*/
+	Purged  int64      `json:"purged"`   /*Окончательно удалено ссылок с момента запуска*/
+	Failed  int64      `json:"failed"`   /*Очисток, завершившихся ошибкой*/
+	LastRun *time.Time `json:"last_run"` /*Время последней успешной очистки*/
+}
+
+/*Окончательное удаление ссылок, пролежавших в корзине дольше срока хранения, по таймеру*/
+type TrashPurger struct {
+	repo domain.Repository
+	cfg  TrashConfig
+	now  func() time.Time
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	done   chan struct{}
+
+	purged  atomic.Int64
+	failed  atomic.Int64
+	lastRun atomic.Pointer[time.Time]
+}
+
+/*Метод создания очистки корзины; фоновая очистка запускается, только если задан срок хранения*/
+func NewTrashPurger(repo domain.Repository, cfg TrashConfig) *TrashPurger {
+	if cfg.Interval <= 0 {
+		cfg.Interval = time.Hour
+	}
+
+	p := &TrashPurger{
+		repo: repo,
+		cfg:  cfg,
+		now:  time.Now,
+		done: make(chan struct{}),
+	}
+	// фоновая очистка записывается в историю от имени system
+	p.ctx, p.cancel = context.WithCancel(domain.WithActor(context.Background(), "system"))
+
+	if cfg.MaxAge <= 0 {
+		close(p.done)
+		return p
+	}
+
+	go p.run()
+
+	return p
+}
+
+/*Окончательное удаление ссылок, попавших в корзину раньше срока хранения, вместе с посещениями*/
+func (p *TrashPurger) Purge(ctx context.Context) (int64, error) {
+	now := p.now()
+
+	n, err := p.repo.PurgeTrashed(ctx, now.Add(-p.cfg.MaxAge))
+	if err != nil {
+		p.failed.Add(1)
+		return 0, err
+	}
+
+	p.purged.Add(n)
+	p.lastRun.Store(&now)
+
+	return n, nil
+}
+
+/*Остановка фоновой очистки*/
+func (p *TrashPurger) Close(ctx context.Context) error {
+	p.cancel()
+
+	select {
+	case <-p.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+/*Текущие счётчики*/
+func (p *TrashPurger) Stats() TrashStats {
+	return TrashStats{
+		Enabled: p.cfg.MaxAge > 0,
+		Purged:  p.purged.Load(),
+		Failed:  p.failed.Load(),
+		LastRun: p.lastRun.Load(),
+	}
+}
+
+/*Основной цикл: очистка при запуске и далее по таймеру*/
+func (p *TrashPurger) run() {
+	defer close(p.done)
+
+	ticker := time.NewTicker(p.cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		n, err := p.Purge(p.ctx)
+		switch {
+		case err == nil && n > 0:
+			log.Printf("links: trash purge deleted %d links", n)
+		case err != nil && p.ctx.Err() == nil:
+			log.Printf("links: trash purge failed: %v", err)
+		}
+
+		select {
+		case <-p.ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
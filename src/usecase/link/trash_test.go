@@ -0,0 +1,58 @@
+package linkusecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	domain "link-service/src/domain/link"
+
+	"github.com/go-playground/assert/v2"
+)
+
+type trashRepo struct {
+	domain.Repository
+
+	before time.Time
+	purged int64
+	err    error
+}
+
+func (r *trashRepo) PurgeTrashed(ctx context.Context, before time.Time) (int64, error) {
+	r.before = before
+	return r.purged, r.err
+}
+
+func TestTrashPurgerPurgesOlderThanMaxAge(t *testing.T) {
+	repo := &trashRepo{purged: 2}
+	p := NewTrashPurger(repo, TrashConfig{MaxAge: 30 * 24 * time.Hour, Interval: time.Hour})
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	assert.Equal(t, nil, p.Close(ctx))
+
+	now := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	p.now = func() time.Time { return now }
+
+	n, err := p.Purge(context.Background())
+	assert.Equal(t, nil, err)
+	assert.Equal(t, int64(2), n)
+	assert.Equal(t, time.Date(2025, 5, 2, 0, 0, 0, 0, time.UTC), repo.before)
+
+	repo.err = errors.New("boom")
+	_, err = p.Purge(context.Background())
+	assert.Equal(t, repo.err, err)
+
+	stats := p.Stats()
+	assert.Equal(t, true, stats.Enabled)
+	// фоновая очистка при запуске тоже удалила 2 ссылки
+	assert.Equal(t, int64(4), stats.Purged)
+	assert.Equal(t, int64(1), stats.Failed)
+	assert.Equal(t, now, *stats.LastRun)
+}
+
+func TestTrashPurgerDisabled(t *testing.T) {
+	p := NewTrashPurger(&trashRepo{}, TrashConfig{})
+	assert.Equal(t, nil, p.Close(context.Background()))
+	assert.Equal(t, false, p.Stats().Enabled)
+}
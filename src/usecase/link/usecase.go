@@ -2,37 +2,63 @@ package linkusecase
 
 import (
 	"context"
+	"time"
+
 	"link-service/src/domain/link"
 )
 
 /*Интерфейс для работы с ссылками*/
 type UseCase interface {
-	/*Метод получения списка ссылок*/
-	List(ctx context.Context) ([]LinkDTO, error)
-	/*Список ссылок с range*/
-	ListWithRange(ctx context.Context, rng *link.Range) ([]LinkDTO, error)
-	/*Общее количество ссылок*/
-	Count(ctx context.Context) (int64, error)
+	/*Метод получения списка ссылок с фильтром и сортировкой*/
+	List(ctx context.Context, q link.Query) ([]LinkDTO, error)
+	/*Список ссылок с range, фильтром и сортировкой*/
+	ListWithRange(ctx context.Context, rng *link.Range, q link.Query) ([]LinkDTO, error)
+	/*Страница ссылок после курсора и курсор следующей страницы (nil — страница последняя)*/
+	ListAfter(ctx context.Context, cur *link.Cursor) ([]LinkDTO, *link.Cursor, error)
+	/*Обход ссылок с фильтром и сортировкой без загрузки всего списка в память*/
+	Each(ctx context.Context, q link.Query, fn func(LinkDTO) error) error
+	/*Количество ссылок, подходящих под фильтр*/
+	Count(ctx context.Context, f link.Filter) (int64, error)
 	/*Метод получения ссылки по идентификатору*/
 	Get(ctx context.Context, id int64) (LinkDTO, error)
 	/*Метод получения ссылки по short_name*/
 	GetByShortName(ctx context.Context, shortName string) (LinkDTO, error)
+	/*Метод проверки, что лимит посещений ссылки исчерпан, по счётчику из хранилища (минуя кеш short_name)*/
+	LimitReached(ctx context.Context, id int64) (bool, error)
+	/*Метод получения защищённой паролем ссылки по short_name с проверкой пароля*/
+	Unlock(ctx context.Context, shortName, password string) (LinkDTO, error)
 	/*Метод создания новой ссылки*/
 	Create(ctx context.Context, in CreateInput) (LinkDTO, error)
+	/*Метод импорта ссылок с отчётом по строкам (allOrNothing — при любой ошибке ничего не создаётся)*/
+	Import(ctx context.Context, rows []ImportRow, allOrNothing bool) (ImportResult, error)
 	/*Метод обновления ссылки*/
 	Update(ctx context.Context, id int64, in UpdateInput) (LinkDTO, error)
-	/*Метод удаления ссылки*/
+	/*Метод перемещения ссылки в корзину*/
 	Delete(ctx context.Context, id int64) error
+	/*Метод восстановления ссылки из корзины*/
+	Restore(ctx context.Context, id int64) (LinkDTO, error)
+	/*Метод получения истории изменений ссылки (включая ссылку в корзине)*/
+	History(ctx context.Context, id int64) ([]LinkEventDTO, error)
 }
 
 /*DTO для создания ссылки*/
 type CreateInput struct {
-	OriginalURL string /*Исходная ссылка*/
-	ShortName   string /*Короткая ссылка*/
+	OriginalURL  string     /*Исходная ссылка*/
+	ShortName    string     /*Короткая ссылка*/
+	ExpiresAt    *time.Time /*Дата истечения срока действия*/
+	MaxVisits    *int       /*Лимит посещений*/
+	Password     string     /*Пароль в открытом виде (пустая строка — без пароля)*/
+	RedirectType int        /*HTTP статус редиректа (0 — 302)*/
+	OwnerID      *int64     /*Владелец (nil — пользователь из контекста; задать другого может только администратор)*/
 }
 
-/*DTO для обновления ссылки*/
+/*DTO для обновления ссылки: не переданное поле сохраняет текущее значение*/
 type UpdateInput struct {
-	OriginalURL string /*Исходная ссылка*/
-	ShortName   string /*Короткая ссылка*/
+	OriginalURL  string              /*Исходная ссылка (пусто — не менять)*/
+	ShortName    string              /*Короткая ссылка (пусто — не менять)*/
+	ExpiresAt    Optional[time.Time] /*Дата истечения срока действия (не передана — не менять, null — бессрочная)*/
+	MaxVisits    Optional[int]       /*Лимит посещений (не передан — не менять, null — без лимита)*/
+	Password     *string             /*Пароль в открытом виде (nil — не менять, пустая строка — снять пароль)*/
+	RedirectType int                 /*HTTP статус редиректа (0 — не менять)*/
+	OwnerID      *int64              /*Новый владелец (nil — не менять; только для администратора)*/
 }
@@ -4,12 +4,57 @@ import "time"
 
 /*DTO для посещения ссылки*/
 type LinkVisitDTO struct {
-	ID        int64     `json:"id"`
-	LinkID    int64     `json:"link_id"`
-	CreatedAt time.Time `json:"created_at"`
-	IP        string    `json:"ip"`
-	UserAgent string    `json:"user_agent"`
-	Referer   string    `json:"referer"`
-	Status    int       `json:"status"`
+	ID             int64     `json:"id"`
+	LinkID         int64     `json:"link_id"`
+	CreatedAt      time.Time `json:"created_at"`
+	IP             string    `json:"ip"`
+	UserAgent      string    `json:"user_agent"`
+	Referer        string    `json:"referer"`
+	Status         int       `json:"status"`
+	Browser        string    `json:"browser"`
+	BrowserVersion string    `json:"browser_version"`
+	OS             string    `json:"os"`
+	Device         string    `json:"device"`
+	IsBot          bool      `json:"is_bot"`
+	Country        string    `json:"country"`
+	Region         string    `json:"region"`
+	City           string    `json:"city"`
 }
 
+/*DTO для статистики переходов по ссылке*/
+type StatsDTO struct {
+	LinkID           int64            `json:"link_id"`
+	From             time.Time        `json:"from"`
+	To               time.Time        `json:"to"`
+	Interval         string           `json:"interval"`
+	Total            int64            `json:"total"`
+	Bots             int64            `json:"bots"`
+	Series           []BucketDTO      `json:"series"`
+	TopReferers      []ValueCountDTO  `json:"top_referers"`
+	TopUserAgents    []ValueCountDTO  `json:"top_user_agents"`
+	Statuses         []StatusCountDTO `json:"statuses"`
+	Browsers         []ValueCountDTO  `json:"browsers"`
+	OperatingSystems []ValueCountDTO  `json:"operating_systems"`
+	Devices          []ValueCountDTO  `json:"devices"`
+	Countries        []ValueCountDTO  `json:"countries"`
+	RefererHosts     []ValueCountDTO  `json:"referer_hosts"`
+	RawFrom          time.Time        `json:"raw_from"` /*Начало периода по сырым посещениям; раньше него — дневная агрегация без топов, кроме referer_hosts*/
+}
+
+/*DTO для количества переходов за интервал*/
+type BucketDTO struct {
+	Start  time.Time `json:"start"`
+	Clicks int64     `json:"clicks"`
+}
+
+/*DTO для количества посещений с одинаковым значением*/
+type ValueCountDTO struct {
+	Value string `json:"value"`
+	Count int64  `json:"count"`
+}
+
+/*DTO для количества посещений с одинаковым статусом*/
+type StatusCountDTO struct {
+	Status int   `json:"status"`
+	Count  int64 `json:"count"`
+}
@@ -0,0 +1,20 @@
+package linkvisitusecase
+
+import "errors"
+
+var (
+	/*Ссылка не найдена или принадлежит другому пользователю*/
+	ErrLinkNotFound = errors.New("link not found")
+	/*Лимит посещений ссылки исчерпан*/
+	ErrLimitReached = errors.New("link visit limit reached")
+	/*Очередь записи посещений переполнена*/
+	ErrQueueFull = errors.New("link visit queue is full")
+	/*Запись посещений остановлена*/
+	ErrRecorderClosed = errors.New("link visit recorder is closed")
+	/*Невалидные параметры статистики*/
+	ErrInvalidStatsQuery = errors.New("invalid stats query")
+	/*Срок хранения посещений не задан*/
+	ErrRetentionDisabled = errors.New("link visit retention is disabled")
+	/*Очистка посещений уже выполняется*/
+	ErrPurgeRunning = errors.New("link visit purge is already running")
+)
@@ -0,0 +1,71 @@
+package linkvisitusecase
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"net"
+	"time"
+)
+
+/*Режим хранения IP посещений*/
+type IPMode string
+
+const (
+	IPModeFull     IPMode = "full"     /*IP хранится полностью*/
+	IPModeTruncate IPMode = "truncate" /*IPv4 обрезается до /24, IPv6 — до /48*/
+	IPModeHash     IPMode = "hash"     /*Хранится HMAC от IP с периодически меняющейся солью*/
+)
+
+/*Параметры приватности записи посещений*/
+type Privacy struct {
+	IPMode       IPMode        /*Режим хранения IP (пусто — full)*/
+	HashKey      []byte        /*Ключ HMAC (пусто — случайный ключ на время работы процесса)*/
+	SaltRotation time.Duration /*Период смены соли (по умолчанию сутки)*/
+}
+
+/*Метод подготовки параметров: недостающие значения заполняются по умолчанию*/
+func (p Privacy) withDefaults() Privacy {
+	if p.IPMode == "" {
+		p.IPMode = IPModeFull
+	}
+	if p.SaltRotation <= 0 {
+		p.SaltRotation = 24 * time.Hour
+	}
+	if p.IPMode == IPModeHash && len(p.HashKey) == 0 {
+		p.HashKey = make([]byte, 32)
+		_, _ = rand.Read(p.HashKey)
+	}
+	return p
+}
+
+/*IP в виде, допустимом для хранения в текущем режиме*/
+func (p Privacy) anonymizeIP(ip string, now time.Time) string {
+	switch p.IPMode {
+	case IPModeTruncate:
+		addr := net.ParseIP(ip)
+		if addr == nil {
+			return ""
+		}
+		if v4 := addr.To4(); v4 != nil {
+			return v4.Mask(net.CIDRMask(24, 32)).String()
+		}
+		return addr.Mask(net.CIDRMask(48, 128)).String()
+	case IPModeHash:
+		if ip == "" {
+			return ""
+		}
+		// соль выводится из ключа и номера периода: хэши одного IP совпадают только в пределах периода
+		var period [8]byte
+		binary.BigEndian.PutUint64(period[:], uint64(now.UnixNano()/int64(p.SaltRotation)))
+		salt := hmac.New(sha256.New, p.HashKey)
+		salt.Write(period[:])
+
+		mac := hmac.New(sha256.New, salt.Sum(nil))
+		mac.Write([]byte(ip))
+		return hex.EncodeToString(mac.Sum(nil)[:16])
+	}
+	return ip
+}
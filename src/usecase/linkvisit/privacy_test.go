@@ -0,0 +1,78 @@
+package linkvisitusecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"link-service/src/domain/entity"
+	domain "link-service/src/domain/linkvisit"
+
+	"github.com/go-playground/assert/v2"
+)
+
+type createRepo struct {
+	domain.Repository
+
+	in domain.CreateInput
+}
+
+func (r *createRepo) Create(ctx context.Context, in domain.CreateInput) (entity.LinkVisit, error) {
+	r.in = in
+	return entity.LinkVisit{LinkID: in.LinkID, IP: in.IP}, nil
+}
+
+type staticGeo domain.Location
+
+func (g staticGeo) Locate(ip string) domain.Location {
+	if ip != "203.0.113.7" {
+		return domain.Location{}
+	}
+	return domain.Location(g)
+}
+
+func TestAnonymizeIP(t *testing.T) {
+	now := time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC)
+	truncate := Privacy{IPMode: IPModeTruncate}.withDefaults()
+
+	assert.Equal(t, "203.0.113.0", truncate.anonymizeIP("203.0.113.7", now))
+	assert.Equal(t, "2001:db8:abcd::", truncate.anonymizeIP("2001:db8:abcd:12::1", now))
+	assert.Equal(t, "", truncate.anonymizeIP("garbage", now))
+	assert.Equal(t, "203.0.113.7", Privacy{}.withDefaults().anonymizeIP("203.0.113.7", now))
+
+	hash := Privacy{IPMode: IPModeHash, HashKey: []byte("secret")}.withDefaults()
+	h := hash.anonymizeIP("203.0.113.7", now)
+	assert.Equal(t, 32, len(h))
+	assert.Equal(t, h, hash.anonymizeIP("203.0.113.7", now.Add(time.Hour)))
+	assert.NotEqual(t, h, hash.anonymizeIP("203.0.113.8", now))
+	// соль меняется со сменой периода
+	assert.NotEqual(t, h, hash.anonymizeIP("203.0.113.7", now.Add(24*time.Hour)))
+	// другой ключ даёт другой хэш
+	other := Privacy{IPMode: IPModeHash, HashKey: []byte("other")}.withDefaults()
+	assert.NotEqual(t, h, other.anonymizeIP("203.0.113.7", now))
+}
+
+func TestCreateAppliesPrivacy(t *testing.T) {
+	repo := &createRepo{}
+	geo := staticGeo{Country: "GB", City: "London"}
+	s := NewService(repo, nil, nil, geo, Privacy{IPMode: IPModeTruncate})
+
+	const ua = "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36"
+
+	_, err := s.Create(context.Background(), CreateInput{LinkID: 1, IP: "203.0.113.7", UserAgent: ua})
+	assert.Equal(t, nil, err)
+	// местоположение определяется по полному IP, хранится обрезанный
+	assert.Equal(t, "203.0.113.0", repo.in.IP)
+	assert.Equal(t, domain.Location{Country: "GB", City: "London"}, repo.in.Geo)
+	assert.Equal(t, "Chrome", repo.in.Agent.Browser)
+
+	_, err = s.Create(context.Background(), CreateInput{LinkID: 1, IP: "203.0.113.7", UserAgent: ua, DoNotTrack: true})
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "203.0.113.0", repo.in.IP)
+	assert.Equal(t, domain.Location{}, repo.in.Geo)
+	assert.Equal(t, domain.UserAgent{}, repo.in.Agent)
+
+	_, err = s.Create(context.Background(), CreateInput{LinkID: 1, IP: "203.0.113.7", IsBot: true, DoNotTrack: true})
+	assert.Equal(t, nil, err)
+	assert.Equal(t, true, repo.in.Agent.IsBot)
+}
@@ -0,0 +1,186 @@
+package linkvisitusecase
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	domain "link-service/src/domain/linkvisit"
+)
+
+/*Параметры асинхронной записи посещений*/
+type RecorderConfig struct {
+	QueueSize     int           /*Размер очереди в памяти*/
+	BatchSize     int           /*Размер пачки для записи*/
+	FlushInterval time.Duration /*Максимальное время ожидания пачки*/
+}
+
+/*Счётчики асинхронной записи посещений*/
+type RecorderStats struct {
+	Queued  int64 `json:"queued"`  /*Ожидают записи*/
+	Written int64 `json:"written"` /*Записано*/
+	Dropped int64 `json:"dropped"` /*Отброшено из-за переполнения очереди*/
+	Failed  int64 `json:"failed"`  /*Потеряно из-за ошибок записи*/
+}
+
+/*Асинхронная запись посещений пачками по размеру или по таймеру*/
+type Recorder struct {
+	repo          domain.Repository
+	queue         chan domain.CreateInput
+	batchSize     int
+	flushInterval time.Duration
+
+	mu     sync.RWMutex
+	closed bool
+	stop   chan struct{}
+	done   chan struct{}
+
+	written atomic.Int64
+	dropped atomic.Int64
+	failed  atomic.Int64
+}
+
+/*Метод создания и запуска асинхронной записи посещений*/
+func NewRecorder(repo domain.Repository, cfg RecorderConfig) *Recorder {
+	if cfg.QueueSize <= 0 {
+		cfg.QueueSize = 10000
+	}
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = 500
+	}
+	if cfg.FlushInterval <= 0 {
+		cfg.FlushInterval = time.Second
+	}
+
+	r := &Recorder{
+		repo:          repo,
+		queue:         make(chan domain.CreateInput, cfg.QueueSize),
+		batchSize:     cfg.BatchSize,
+		flushInterval: cfg.FlushInterval,
+		stop:          make(chan struct{}),
+		done:          make(chan struct{}),
+	}
+
+	go r.run()
+
+	return r
+}
+
+/*Постановка посещения в очередь без блокировки*/
+func (r *Recorder) Enqueue(in domain.CreateInput) error {
+	// при переполнении очереди посещение отбрасывается, чтобы не задерживать редирект
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if r.closed {
+		return ErrRecorderClosed
+	}
+
+	select {
+	case r.queue <- in:
+		return nil
+	default:
+		r.dropped.Add(1)
+		return ErrQueueFull
+	}
+}
+
+/*Остановка записи с дозаписью очереди*/
+func (r *Recorder) Close(ctx context.Context) error {
+	r.mu.Lock()
+	if !r.closed {
+		r.closed = true
+		close(r.stop)
+	}
+	r.mu.Unlock()
+
+	select {
+	case <-r.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+/*Текущие счётчики*/
+func (r *Recorder) Stats() RecorderStats {
+	return RecorderStats{
+		Queued:  int64(len(r.queue)),
+		Written: r.written.Load(),
+		Dropped: r.dropped.Load(),
+		Failed:  r.failed.Load(),
+	}
+}
+
+/*Основной цикл: накопление пачки и запись по размеру или таймеру*/
+func (r *Recorder) run() {
+	defer close(r.done)
+
+	ticker := time.NewTicker(r.flushInterval)
+	defer ticker.Stop()
+
+	batch := make([]domain.CreateInput, 0, r.batchSize)
+
+	for {
+		select {
+		case in := <-r.queue:
+			batch = append(batch, in)
+			if len(batch) >= r.batchSize {
+				batch = r.flush(batch)
+			}
+		case <-ticker.C:
+			batch = r.flush(batch)
+		case <-r.stop:
+			// после закрытия новые посещения не поступают — дочитываем очередь
+			for {
+				select {
+				case in := <-r.queue:
+					batch = append(batch, in)
+					if len(batch) >= r.batchSize {
+						batch = r.flush(batch)
+					}
+				default:
+					r.flush(batch)
+					return
+				}
+			}
+		}
+	}
+}
+
+/*Запись пачки в репозиторий*/
+func (r *Recorder) flush(batch []domain.CreateInput) []domain.CreateInput {
+	if len(batch) == 0 {
+		return batch
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	r.write(ctx, batch)
+
+	return batch[:0]
+}
+
+/*Запись пачки: если виноваты данные строк, пачка делится пополам, пока не останутся отдельные строки, чтобы потерять только те посещения, которые не записываются (например, ссылка уже удалена)*/
+func (r *Recorder) write(ctx context.Context, batch []domain.CreateInput) {
+	err := r.repo.CreateBatch(ctx, batch)
+	if err == nil {
+		r.written.Add(int64(len(batch)))
+		return
+	}
+
+	// хранилище недоступно или время вышло — повторять по частям бессмысленно
+	if len(batch) == 1 || !errors.Is(err, domain.ErrInvalidRow) || ctx.Err() != nil {
+		r.failed.Add(int64(len(batch)))
+		log.Printf("link visits: dropped batch of %d: %v", len(batch), err)
+		return
+	}
+
+	mid := len(batch) / 2
+	r.write(ctx, batch[:mid])
+	r.write(ctx, batch[mid:])
+}
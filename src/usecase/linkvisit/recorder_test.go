@@ -0,0 +1,155 @@
+package linkvisitusecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	domain "link-service/src/domain/linkvisit"
+
+	"github.com/go-playground/assert/v2"
+)
+
+type batchRepo struct {
+	domain.Repository
+
+	mu      sync.Mutex
+	batches [][]domain.CreateInput
+}
+
+func (r *batchRepo) CreateBatch(ctx context.Context, in []domain.CreateInput) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.batches = append(r.batches, append([]domain.CreateInput(nil), in...))
+	return nil
+}
+
+func (r *batchRepo) sizes() []int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	res := make([]int, 0, len(r.batches))
+	for _, b := range r.batches {
+		res = append(res, len(b))
+	}
+	return res
+}
+
+func TestRecorderFlushesBySizeAndDrainsOnClose(t *testing.T) {
+	repo := &batchRepo{}
+	rec := NewRecorder(repo, RecorderConfig{QueueSize: 100, BatchSize: 3, FlushInterval: time.Hour})
+
+	for i := 0; i < 7; i++ {
+		assert.Equal(t, nil, rec.Enqueue(domain.CreateInput{LinkID: int64(i), Status: 302}))
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	assert.Equal(t, nil, rec.Close(ctx))
+
+	assert.Equal(t, []int{3, 3, 1}, repo.sizes())
+	assert.Equal(t, int64(7), rec.Stats().Written)
+	assert.Equal(t, ErrRecorderClosed, rec.Enqueue(domain.CreateInput{LinkID: 1}))
+}
+
+func TestRecorderFlushesByInterval(t *testing.T) {
+	repo := &batchRepo{}
+	rec := NewRecorder(repo, RecorderConfig{QueueSize: 100, BatchSize: 100, FlushInterval: 10 * time.Millisecond})
+	defer func() { _ = rec.Close(context.Background()) }()
+
+	assert.Equal(t, nil, rec.Enqueue(domain.CreateInput{LinkID: 1, Status: 302}))
+
+	deadline := time.Now().Add(time.Second)
+	for rec.Stats().Written == 0 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	assert.Equal(t, []int{1}, repo.sizes())
+}
+
+func TestRecorderDropsWhenQueueIsFull(t *testing.T) {
+	repo := &batchRepo{}
+	rec := &Recorder{
+		repo:      repo,
+		queue:     make(chan domain.CreateInput, 1),
+		batchSize: 10,
+		stop:      make(chan struct{}),
+		done:      make(chan struct{}),
+	}
+
+	assert.Equal(t, nil, rec.Enqueue(domain.CreateInput{LinkID: 1}))
+	assert.Equal(t, ErrQueueFull, rec.Enqueue(domain.CreateInput{LinkID: 2}))
+	assert.Equal(t, int64(1), rec.Stats().Dropped)
+}
+
+type failingBatchRepo struct {
+	batchRepo
+
+	bad map[int64]bool /*Ссылки, посещения которых не записываются (ссылка удалена)*/
+}
+
+func (r *failingBatchRepo) CreateBatch(ctx context.Context, in []domain.CreateInput) error {
+	for _, v := range in {
+		if r.bad[v.LinkID] {
+			return fmt.Errorf("%w: foreign key violation", domain.ErrInvalidRow)
+		}
+	}
+	return r.batchRepo.CreateBatch(ctx, in)
+}
+
+func TestRecorderDropsOnlyFailingRows(t *testing.T) {
+	repo := &failingBatchRepo{bad: map[int64]bool{3: true, 6: true}}
+	rec := NewRecorder(repo, RecorderConfig{QueueSize: 100, BatchSize: 8, FlushInterval: time.Hour})
+
+	for i := 1; i <= 8; i++ {
+		assert.Equal(t, nil, rec.Enqueue(domain.CreateInput{LinkID: int64(i), Status: 302}))
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	assert.Equal(t, nil, rec.Close(ctx))
+
+	stats := rec.Stats()
+	assert.Equal(t, int64(6), stats.Written)
+	assert.Equal(t, int64(2), stats.Failed)
+
+	var written []int64
+	for _, b := range repo.batches {
+		for _, v := range b {
+			written = append(written, v.LinkID)
+		}
+	}
+	assert.Equal(t, []int64{1, 2, 4, 5, 7, 8}, written)
+}
+
+type downBatchRepo struct {
+	batchRepo
+
+	calls int
+}
+
+func (r *downBatchRepo) CreateBatch(ctx context.Context, in []domain.CreateInput) error {
+	r.calls++
+	return errors.New("connection refused")
+}
+
+func TestRecorderDoesNotSplitWhenStorageIsDown(t *testing.T) {
+	repo := &downBatchRepo{}
+	rec := NewRecorder(repo, RecorderConfig{QueueSize: 100, BatchSize: 8, FlushInterval: time.Hour})
+
+	for i := 1; i <= 8; i++ {
+		assert.Equal(t, nil, rec.Enqueue(domain.CreateInput{LinkID: int64(i), Status: 302}))
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	assert.Equal(t, nil, rec.Close(ctx))
+
+	assert.Equal(t, 1, repo.calls)
+	assert.Equal(t, int64(0), rec.Stats().Written)
+	assert.Equal(t, int64(8), rec.Stats().Failed)
+}
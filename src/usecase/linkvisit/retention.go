@@ -0,0 +1,205 @@
+package linkvisitusecase
+
+import (
+	"context"
+	"log"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	domain "link-service/src/domain/linkvisit"
+)
+
+/*Очистка устаревших посещений*/
+type Retention interface {
+	/*Удаление посещений старше срока хранения (dryRun — только подсчёт)*/
+	Purge(ctx context.Context, dryRun bool) (PurgeResult, error)
+}
+
+/*Параметры хранения посещений*/
+type RetentionConfig struct {
+	MaxAge     time.Duration /*Срок хранения посещений (0 — хранятся бессрочно)*/
+	Interval   time.Duration /*Период фоновой очистки*/
+	BatchSize  int           /*Максимум строк в одном DELETE*/
+	BatchPause time.Duration /*Пауза между пачками, чтобы не держать таблицу под нагрузкой*/
+	DryRun     bool          /*Фоновая очистка только считает устаревшие посещения*/
+}
+
+/*Результат очистки*/
+type PurgeResult struct {
+	Before  time.Time `json:"before"`  /*Удаляются посещения, созданные раньше этого момента (нулевое — агрегации ещё не было)*/
+	DryRun  bool      `json:"dry_run"` /*Ничего не удалялось*/
+	Deleted int64     `json:"deleted"` /*Удалено посещений (при dry_run — будет удалено)*/
+	Batches int       `json:"batches"` /*Выполнено DELETE*/
+}
+
+/*Счётчики фоновой очистки*/
+type PurgerStats struct {
+	Enabled bool         `json:"enabled"` /*Срок хранения задан*/
+	Deleted int64        `json:"deleted"` /*Удалено с момента запуска*/
+	Failed  int64        `json:"failed"`  /*Очисток, завершившихся ошибкой*/
+	Last    *PurgeResult `json:"last"`    /*Результат последней очистки*/
+}
+
+/*Удаление посещений старше срока хранения небольшими пачками по таймеру и по запросу*/
+type Purger struct {
+	repo domain.Repository
+	cfg  RetentionConfig
+	now  func() time.Time
+
+	// одновременно выполняется только одна очистка
+	running sync.Mutex
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	done   chan struct{}
+
+	deleted atomic.Int64
+	failed  atomic.Int64
+	last    atomic.Pointer[PurgeResult]
+}
+
+/*Метод создания очистки; фоновая очистка запускается, только если задан срок хранения*/
+func NewPurger(repo domain.Repository, cfg RetentionConfig) *Purger {
+	if cfg.Interval <= 0 {
+		cfg.Interval = time.Hour
+	}
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = 1000
+	}
+	if cfg.BatchPause < 0 {
+		cfg.BatchPause = 0
+	}
+
+	p := &Purger{
+		repo: repo,
+		cfg:  cfg,
+		now:  time.Now,
+		done: make(chan struct{}),
+	}
+	p.ctx, p.cancel = context.WithCancel(context.Background())
+
+	if cfg.MaxAge <= 0 {
+		close(p.done)
+		return p
+	}
+
+	go p.run()
+
+	return p
+}
+
+/*Удаление посещений старше срока хранения (dryRun — только подсчёт)*/
+func (p *Purger) Purge(ctx context.Context, dryRun bool) (PurgeResult, error) {
+	if p.cfg.MaxAge <= 0 {
+		return PurgeResult{}, ErrRetentionDisabled
+	}
+	if !p.running.TryLock() {
+		return PurgeResult{}, ErrPurgeRunning
+	}
+	defer p.running.Unlock()
+
+	// удаляются только целые сутки, уже попавшие в дневную агрегацию: история переходов сохраняется
+	res := PurgeResult{Before: domain.IntervalDay.Truncate(p.now().Add(-p.cfg.MaxAge)), DryRun: dryRun}
+
+	state, err := p.repo.RollupState(ctx)
+	if err != nil {
+		return PurgeResult{}, err
+	}
+	// последний агрегированный день Aggregator пересчитывает заново и перезаписывает, поэтому его посещения не трогаем
+	if state.RolledUntil.IsZero() {
+		res.Before = time.Time{}
+	} else if last := state.RolledUntil.AddDate(0, 0, -1); last.Before(res.Before) {
+		res.Before = last
+	}
+	if res.Before.IsZero() {
+		p.last.Store(&res)
+		return res, nil
+	}
+
+	if dryRun {
+		count, err := p.repo.CountBefore(ctx, res.Before)
+		if err != nil {
+			return PurgeResult{}, err
+		}
+		res.Deleted = count
+		p.last.Store(&res)
+		return res, nil
+	}
+
+	for {
+		n, err := p.repo.DeleteBefore(ctx, res.Before, p.cfg.BatchSize)
+		res.Deleted += n
+		p.deleted.Add(n)
+		if err != nil {
+			p.failed.Add(1)
+			return res, err
+		}
+		res.Batches++
+
+		if n < int64(p.cfg.BatchSize) {
+			break
+		}
+
+		select {
+		case <-time.After(p.cfg.BatchPause):
+		case <-ctx.Done():
+			p.failed.Add(1)
+			return res, ctx.Err()
+		}
+	}
+
+	p.last.Store(&res)
+
+	return res, nil
+}
+
+/*Остановка фоновой очистки (прерывает текущую очистку)*/
+func (p *Purger) Close(ctx context.Context) error {
+	p.cancel()
+
+	select {
+	case <-p.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+/*Текущие счётчики*/
+func (p *Purger) Stats() PurgerStats {
+	return PurgerStats{
+		Enabled: p.cfg.MaxAge > 0,
+		Deleted: p.deleted.Load(),
+		Failed:  p.failed.Load(),
+		Last:    p.last.Load(),
+	}
+}
+
+/*Основной цикл: очистка при запуске и далее по таймеру*/
+func (p *Purger) run() {
+	defer close(p.done)
+
+	ticker := time.NewTicker(p.cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		res, err := p.Purge(p.ctx, p.cfg.DryRun)
+		switch {
+		case err == nil && res.DryRun:
+			log.Printf("link visits: retention dry run, %d visits before %s would be deleted", res.Deleted, res.Before.Format(time.RFC3339))
+		case err == nil && res.Deleted > 0:
+			log.Printf("link visits: retention deleted %d visits before %s", res.Deleted, res.Before.Format(time.RFC3339))
+		case err != nil && p.ctx.Err() == nil:
+			log.Printf("link visits: retention purge failed after %d deleted: %v", res.Deleted, err)
+		}
+
+		select {
+		case <-p.ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+var _ Retention = (*Purger)(nil)
@@ -0,0 +1,174 @@
+package linkvisitusecase
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	domain "link-service/src/domain/linkvisit"
+
+	"github.com/go-playground/assert/v2"
+)
+
+type purgeRepo struct {
+	domain.Repository
+
+	mu      sync.Mutex
+	expired int64
+	limits  []int
+	before  time.Time
+
+	rolledUntil time.Time
+}
+
+func (r *purgeRepo) RollupState(ctx context.Context) (domain.RollupState, error) {
+	return domain.RollupState{RolledUntil: r.rolledUntil}, nil
+}
+
+func (r *purgeRepo) CountBefore(ctx context.Context, before time.Time) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.before = before
+	return r.expired, nil
+}
+
+func (r *purgeRepo) DeleteBefore(ctx context.Context, before time.Time, limit int) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.before = before
+	r.limits = append(r.limits, limit)
+	n := min(r.expired, int64(limit))
+	r.expired -= n
+	return n, nil
+}
+
+func TestPurgerDeletesInBatches(t *testing.T) {
+	repo := &purgeRepo{expired: 5, rolledUntil: time.Date(2025, 5, 31, 0, 0, 0, 0, time.UTC)}
+	// Interval большой: фоновая очистка выполняется один раз при запуске
+	p := NewPurger(repo, RetentionConfig{MaxAge: 90 * 24 * time.Hour, Interval: time.Hour, BatchSize: 2, DryRun: true})
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	assert.Equal(t, nil, p.Close(ctx))
+
+	// ручной запуск работает и после остановки фоновой очистки
+	now := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	p.now = func() time.Time { return now }
+
+	res, err := p.Purge(context.Background(), true)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, PurgeResult{Before: now.Add(-90 * 24 * time.Hour), DryRun: true, Deleted: 5}, res)
+	assert.Equal(t, 0, len(repo.limits))
+
+	res, err = p.Purge(context.Background(), false)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, int64(5), res.Deleted)
+	assert.Equal(t, 3, res.Batches)
+	assert.Equal(t, []int{2, 2, 2}, repo.limits)
+	assert.Equal(t, time.Date(2025, 3, 3, 0, 0, 0, 0, time.UTC), repo.before)
+
+	stats := p.Stats()
+	assert.Equal(t, true, stats.Enabled)
+	assert.Equal(t, int64(5), stats.Deleted)
+	assert.Equal(t, &res, stats.Last)
+
+	// граница выравнивается на сутки и не заходит за дневную агрегацию
+	p.now = func() time.Time { return now.Add(15 * time.Hour) }
+	res, err = p.Purge(context.Background(), true)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, time.Date(2025, 3, 3, 0, 0, 0, 0, time.UTC), res.Before)
+
+	// последний агрегированный день ещё будет пересчитан, поэтому граница на сутки раньше
+	repo.rolledUntil = time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)
+	res, err = p.Purge(context.Background(), true)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, time.Date(2025, 1, 31, 0, 0, 0, 0, time.UTC), res.Before)
+
+	repo.rolledUntil = time.Time{}
+	res, err = p.Purge(context.Background(), false)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, PurgeResult{}, res)
+	assert.Equal(t, 3, len(repo.limits))
+}
+
+func TestPurgerDisabledAndBusy(t *testing.T) {
+	p := NewPurger(&purgeRepo{}, RetentionConfig{})
+	_, err := p.Purge(context.Background(), false)
+	assert.Equal(t, ErrRetentionDisabled, err)
+	assert.Equal(t, false, p.Stats().Enabled)
+	assert.Equal(t, nil, p.Close(context.Background()))
+
+	p = NewPurger(&purgeRepo{}, RetentionConfig{MaxAge: time.Hour, Interval: time.Hour})
+	defer func() { _ = p.Close(context.Background()) }()
+
+	p.running.Lock()
+	_, err = p.Purge(context.Background(), false)
+	p.running.Unlock()
+	assert.Equal(t, ErrPurgeRunning, err)
+}
+
+/*Хранилище с сырыми посещениями по дням: агрегация считает их, очистка удаляет*/
+type historyRepo struct {
+	rollupRepo
+
+	visits map[int64]int64 /*Посещения по началу суток*/
+}
+
+func (r *historyRepo) CountGroups(ctx context.Context, q domain.GroupQuery) ([]domain.VisitGroup, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if n := r.visits[q.From.Unix()]; n > 0 {
+		return []domain.VisitGroup{{LinkID: 1, Status: 302, Count: n}}, nil
+	}
+	return nil, nil
+}
+
+func (r *historyRepo) DeleteBefore(ctx context.Context, before time.Time, limit int) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var n int64
+	for day, count := range r.visits {
+		if time.Unix(day, 0).Before(before) {
+			n += count
+			delete(r.visits, day)
+		}
+	}
+	return n, nil
+}
+
+func TestPurgerKeepsDayAggregatorRecomputes(t *testing.T) {
+	day1 := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	day2 := day1.AddDate(0, 0, 1)
+	repo := &historyRepo{rollupRepo: rollupRepo{daily: map[int64][]domain.DailyCount{}}}
+
+	a := NewAggregator(repo, RollupConfig{Interval: time.Hour})
+	p := NewPurger(repo, RetentionConfig{MaxAge: time.Hour, Interval: time.Hour})
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	assert.Equal(t, nil, a.Close(ctx))
+	assert.Equal(t, nil, p.Close(ctx))
+
+	now := day2.AddDate(0, 0, 1).Add(time.Hour)
+	a.now = func() time.Time { return now }
+	p.now = func() time.Time { return now }
+	// фоновые запуски при создании уже завершены, начинаем с чистого состояния
+	repo.state = domain.RollupState{FirstVisitAt: day1}
+	repo.daily = map[int64][]domain.DailyCount{}
+	repo.visits = map[int64]int64{day1.Unix(): 4, day2.Unix(): 3}
+
+	assert.Equal(t, nil, a.Run(context.Background()))
+
+	// срок хранения истёк для обоих дней, но второй день агрегация ещё пересчитает
+	res, err := p.Purge(context.Background(), false)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, day2, res.Before)
+	assert.Equal(t, int64(4), res.Deleted)
+
+	assert.Equal(t, nil, a.Run(context.Background()))
+	assert.Equal(t, int64(4), repo.daily[day1.Unix()][0].Visits)
+	assert.Equal(t, int64(3), repo.daily[day2.Unix()][0].Visits)
+}
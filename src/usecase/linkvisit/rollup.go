@@ -0,0 +1,185 @@
+package linkvisitusecase
+
+import (
+	"context"
+	"log"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	domain "link-service/src/domain/linkvisit"
+)
+
+/*Параметры дневной агрегации посещений*/
+type RollupConfig struct {
+	Interval time.Duration /*Период запуска агрегации*/
+}
+
+/*Счётчики дневной агрегации*/
+type RollupStats struct {
+	RolledUntil *time.Time `json:"rolled_until"` /*Граница после последнего агрегированного дня*/
+	Days        int64      `json:"days"`         /*Агрегировано дней с момента запуска*/
+	Failed      int64      `json:"failed"`       /*Запусков, завершившихся ошибкой*/
+}
+
+/*Инкрементальная агрегация посещений в дневные счётчики по таймеру*/
+type Aggregator struct {
+	repo     domain.Repository
+	interval time.Duration
+	now      func() time.Time
+
+	// одновременно агрегирует только один запуск
+	running sync.Mutex
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	done   chan struct{}
+
+	days        atomic.Int64
+	failed      atomic.Int64
+	rolledUntil atomic.Pointer[time.Time]
+}
+
+/*Метод создания и запуска агрегации*/
+func NewAggregator(repo domain.Repository, cfg RollupConfig) *Aggregator {
+	if cfg.Interval <= 0 {
+		cfg.Interval = time.Hour
+	}
+
+	a := &Aggregator{
+		repo:     repo,
+		interval: cfg.Interval,
+		now:      time.Now,
+		done:     make(chan struct{}),
+	}
+	a.ctx, a.cancel = context.WithCancel(context.Background())
+
+	go a.run()
+
+	return a
+}
+
+// Run агрегирует завершившиеся дни (до начала текущих суток UTC), начиная с последнего агрегированного.
+//
+// Последний агрегированный день пересчитывается повторно: пересчёт идемпотентен и подбирает
+// посещения, записанные после прошлого запуска.
+func (a *Aggregator) Run(ctx context.Context) error {
+	a.running.Lock()
+	defer a.running.Unlock()
+
+	state, err := a.repo.RollupState(ctx)
+	if err != nil {
+		return err
+	}
+
+	var start time.Time
+	switch {
+	case !state.RolledUntil.IsZero():
+		start = state.RolledUntil.AddDate(0, 0, -1)
+	case !state.FirstVisitAt.IsZero():
+		start = domain.IntervalDay.Truncate(state.FirstVisitAt)
+	default:
+		return nil
+	}
+
+	today := domain.IntervalDay.Truncate(a.now())
+	for day := start; day.Before(today); day = day.AddDate(0, 0, 1) {
+		if err := a.rollupDay(ctx, day); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+/*Остановка агрегации (прерывает текущий запуск)*/
+func (a *Aggregator) Close(ctx context.Context) error {
+	a.cancel()
+
+	select {
+	case <-a.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+/*Текущие счётчики*/
+func (a *Aggregator) Stats() RollupStats {
+	return RollupStats{
+		RolledUntil: a.rolledUntil.Load(),
+		Days:        a.days.Load(),
+		Failed:      a.failed.Load(),
+	}
+}
+
+/*Основной цикл: агрегация при запуске и далее по таймеру*/
+func (a *Aggregator) run() {
+	defer close(a.done)
+
+	ticker := time.NewTicker(a.interval)
+	defer ticker.Stop()
+
+	for {
+		if err := a.Run(a.ctx); err != nil && a.ctx.Err() == nil {
+			a.failed.Add(1)
+			log.Printf("link visits: daily rollup failed: %v", err)
+		}
+
+		select {
+		case <-a.ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+/*Агрегация посещений за сутки, начинающиеся в day (вызывается под running)*/
+func (a *Aggregator) rollupDay(ctx context.Context, day time.Time) error {
+	groups, err := a.repo.CountGroups(ctx, domain.GroupQuery{From: day, To: day.AddDate(0, 0, 1)})
+	if err != nil {
+		return err
+	}
+
+	if err := a.repo.SaveDaily(ctx, day, dailyCounts(day, groups)); err != nil {
+		return err
+	}
+
+	a.days.Add(1)
+	next := day.AddDate(0, 0, 1)
+	a.rolledUntil.Store(&next)
+
+	return nil
+}
+
+/*Свёртка групп посещений по хосту реферера*/
+func dailyCounts(day time.Time, groups []domain.VisitGroup) []domain.DailyCount {
+	type key struct {
+		linkID int64
+		status int
+		isBot  bool
+		host   string
+	}
+
+	index := map[key]int{}
+	res := make([]domain.DailyCount, 0, len(groups))
+	for _, g := range groups {
+		k := key{g.LinkID, g.Status, g.IsBot, domain.RefererHost(g.Referer)}
+		if i, ok := index[k]; ok {
+			res[i].Visits += g.Count
+			continue
+		}
+
+		index[k] = len(res)
+		res = append(res, domain.DailyCount{
+			LinkID:      k.linkID,
+			Day:         day,
+			Status:      k.status,
+			IsBot:       k.isBot,
+			RefererHost: k.host,
+			Visits:      g.Count,
+		})
+	}
+
+	return res
+}
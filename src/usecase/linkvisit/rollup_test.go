@@ -0,0 +1,194 @@
+package linkvisitusecase
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	domain "link-service/src/domain/linkvisit"
+
+	"github.com/go-playground/assert/v2"
+)
+
+type rollupRepo struct {
+	domain.Repository
+
+	mu          sync.Mutex
+	groups      map[int64][]domain.VisitGroup /*Группы посещений по началу суток*/
+	daily       map[int64][]domain.DailyCount
+	saved       []time.Time
+	state       domain.RollupState
+	raw         domain.Stats
+	rawQuery    *domain.StatsQuery
+	dailyFromTo [2]time.Time
+}
+
+func (r *rollupRepo) RollupState(ctx context.Context) (domain.RollupState, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.state, nil
+}
+
+func (r *rollupRepo) CountGroups(ctx context.Context, q domain.GroupQuery) ([]domain.VisitGroup, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.groups[q.From.Unix()], nil
+}
+
+func (r *rollupRepo) SaveDaily(ctx context.Context, day time.Time, counts []domain.DailyCount) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.saved = append(r.saved, day)
+	r.daily[day.Unix()] = counts
+	if next := day.AddDate(0, 0, 1); next.After(r.state.RolledUntil) {
+		r.state.RolledUntil = next
+	}
+	return nil
+}
+
+func (r *rollupRepo) ListDaily(ctx context.Context, linkID int64, from, to time.Time) ([]domain.DailyCount, error) {
+	r.dailyFromTo = [2]time.Time{from, to}
+
+	var res []domain.DailyCount
+	for day := from; day.Before(to); day = day.AddDate(0, 0, 1) {
+		res = append(res, r.daily[day.Unix()]...)
+	}
+	return res, nil
+}
+
+func (r *rollupRepo) Stats(ctx context.Context, q domain.StatsQuery) (domain.Stats, error) {
+	r.rawQuery = &q
+	return r.raw, nil
+}
+
+func TestAggregatorRollsUpCompletedDays(t *testing.T) {
+	day1 := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	day2 := day1.AddDate(0, 0, 1)
+	repo := &rollupRepo{
+		groups: map[int64][]domain.VisitGroup{
+			day1.Unix(): {
+				{LinkID: 1, Status: 302, Referer: "https://www.google.com/a", Count: 2},
+				{LinkID: 1, Status: 302, Referer: "https://google.com/b", Count: 3},
+				{LinkID: 1, Status: 302, IsBot: true, Count: 1},
+			},
+		},
+		daily: map[int64][]domain.DailyCount{},
+		state: domain.RollupState{FirstVisitAt: day1.Add(15 * time.Hour)},
+	}
+
+	a := NewAggregator(repo, RollupConfig{Interval: time.Hour})
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	assert.Equal(t, nil, a.Close(ctx))
+
+	a.now = func() time.Time { return day2.Add(10 * time.Hour) }
+	repo.saved = nil
+	repo.state = domain.RollupState{FirstVisitAt: day1.Add(15 * time.Hour)}
+
+	// текущие сутки не агрегируются
+	assert.Equal(t, nil, a.Run(context.Background()))
+	assert.Equal(t, []time.Time{day1}, repo.saved)
+	assert.Equal(t, []domain.DailyCount{
+		{LinkID: 1, Day: day1, Status: 302, RefererHost: "google.com", Visits: 5},
+		{LinkID: 1, Day: day1, Status: 302, IsBot: true, Visits: 1},
+	}, repo.daily[day1.Unix()])
+	assert.Equal(t, &day2, a.Stats().RolledUntil)
+
+	// последний агрегированный день пересчитывается, результат тот же
+	a.now = func() time.Time { return day2.AddDate(0, 0, 1) }
+	assert.Equal(t, nil, a.Run(context.Background()))
+	assert.Equal(t, []time.Time{day1, day1, day2}, repo.saved)
+	assert.Equal(t, 5, int(repo.daily[day1.Unix()][0].Visits))
+}
+
+func TestStatsMergesRollupWithRawVisits(t *testing.T) {
+	day1 := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	today := day1.AddDate(0, 0, 2)
+	repo := &rollupRepo{
+		daily: map[int64][]domain.DailyCount{
+			day1.Unix(): {
+				{LinkID: 1, Day: day1, Status: 302, RefererHost: "google.com", Visits: 5},
+				{LinkID: 1, Day: day1, Status: 302, IsBot: true, Visits: 1},
+			},
+			day1.AddDate(0, 0, 1).Unix(): {
+				{LinkID: 1, Day: day1.AddDate(0, 0, 1), Status: 410, Visits: 2},
+			},
+		},
+		state: domain.RollupState{RolledUntil: today, FirstVisitAt: today.Add(time.Hour)},
+		raw: domain.Stats{
+			Total:        3,
+			Series:       []domain.Bucket{{Start: today, Clicks: 3}},
+			Statuses:     []domain.StatusCount{{Status: 302, Count: 3}},
+			RefererHosts: []domain.ValueCount{{Value: "", Count: 2}, {Value: "google.com", Count: 1}},
+			Browsers:     []domain.ValueCount{{Value: "Chrome", Count: 3}},
+		},
+	}
+
+	res, err := NewService(repo, nil, nil, nil, Privacy{}).Stats(systemCtx, StatsInput{
+		LinkID: 1,
+		From:   day1.Add(15 * time.Hour),
+		To:     today.Add(12 * time.Hour),
+	})
+	assert.Equal(t, nil, err)
+
+	// агрегация читается с начала суток, в ответе остаётся запрошенный from; сырые посещения — только за сегодня
+	assert.Equal(t, day1.Add(15*time.Hour), res.From)
+	assert.Equal(t, today, res.RawFrom)
+	assert.Equal(t, today, repo.rawQuery.From)
+	assert.Equal(t, [2]time.Time{day1, today}, repo.dailyFromTo)
+
+	assert.Equal(t, int64(10), res.Total)
+	assert.Equal(t, int64(1), res.Bots)
+	assert.Equal(t, []BucketDTO{
+		{Start: day1, Clicks: 5},
+		{Start: day1.AddDate(0, 0, 1), Clicks: 2},
+		{Start: today, Clicks: 3},
+	}, res.Series)
+	assert.Equal(t, []StatusCountDTO{{Status: 302, Count: 8}, {Status: 410, Count: 2}}, res.Statuses)
+	assert.Equal(t, []ValueCountDTO{{Value: "google.com", Count: 6}, {Value: "", Count: 4}}, res.RefererHosts)
+	// разбивок нет в агрегации, а сырые посещения первых дней уже удалены
+	assert.Equal(t, []ValueCountDTO(nil), res.Browsers)
+	assert.Equal(t, []ValueCountDTO(nil), res.Countries)
+
+	// агрегация читается и тогда, когда сырые посещения за период ещё не удалены
+	repo.state = domain.RollupState{RolledUntil: today, FirstVisitAt: day1.Add(9 * time.Hour)}
+	repo.rawQuery = nil
+	res, err = NewService(repo, nil, nil, nil, Privacy{}).Stats(systemCtx, StatsInput{
+		LinkID: 1,
+		From:   day1.Add(15 * time.Hour),
+		To:     today.Add(12 * time.Hour),
+	})
+	assert.Equal(t, nil, err)
+	assert.Equal(t, today, res.RawFrom)
+	assert.Equal(t, today, repo.rawQuery.From)
+	assert.Equal(t, int64(10), res.Total)
+
+	// почасовая статистика строится только по сырым посещениям
+	res, err = NewService(repo, nil, nil, nil, Privacy{}).Stats(systemCtx, StatsInput{
+		LinkID:   1,
+		From:     day1.Add(15 * time.Hour),
+		To:       today.Add(12 * time.Hour),
+		Interval: "hour",
+	})
+	assert.Equal(t, nil, err)
+	assert.Equal(t, res.From, res.RawFrom)
+	assert.Equal(t, res.From, repo.rawQuery.From)
+	assert.Equal(t, int64(3), res.Total)
+	assert.Equal(t, []ValueCountDTO{{Value: "Chrome", Count: 3}}, res.Browsers)
+
+	// без агрегации весь период читается из сырых посещений
+	repo.state = domain.RollupState{}
+	res, err = NewService(repo, nil, nil, nil, Privacy{}).Stats(systemCtx, StatsInput{
+		LinkID: 1,
+		From:   day1.Add(15 * time.Hour),
+		To:     today.Add(12 * time.Hour),
+	})
+	assert.Equal(t, nil, err)
+	assert.Equal(t, day1.Add(15*time.Hour), res.From)
+	assert.Equal(t, res.From, repo.rawQuery.From)
+	assert.Equal(t, int64(3), res.Total)
+}
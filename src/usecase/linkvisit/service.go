@@ -2,40 +2,64 @@ package linkvisitusecase
 
 import (
 	"context"
+	"errors"
+	"time"
 
 	"link-service/src/domain/entity"
-	domain "link-service/src/domain/linkvisit"
 	"link-service/src/domain/link"
+	domain "link-service/src/domain/linkvisit"
 )
 
 /*Сервис для работы с посещениями ссылок*/
 type Service struct {
-	repo domain.Repository
+	repo     domain.Repository
+	links    link.Repository
+	recorder *Recorder
+	geo      domain.GeoLocator
+	privacy  Privacy
+	now      func() time.Time
 }
 
-/*Метод создания нового сервиса*/
-func NewService(repo domain.Repository) *Service {
-	return &Service{repo: repo}
+/*Метод создания нового сервиса (links — проверка владельца ссылки, recorder == nil — посещения пишутся синхронно, geo == nil — без местоположения)*/
+func NewService(repo domain.Repository, links link.Repository, recorder *Recorder, geo domain.GeoLocator, privacy Privacy) *Service {
+	return &Service{repo: repo, links: links, recorder: recorder, geo: geo, privacy: privacy.withDefaults(), now: time.Now}
 }
 
 /*Создание посещения*/
 func (s *Service) Create(ctx context.Context, in CreateInput) (LinkVisitDTO, error) {
-	v, err := s.repo.Create(ctx, domain.CreateInput{
-		LinkID:    in.LinkID,
-		IP:        in.IP,
-		UserAgent: in.UserAgent,
-		Referer:   in.Referer,
-		Status:    in.Status,
-	})
+	v, err := s.repo.Create(ctx, s.toDomainInput(in))
+	if err != nil {
+		return LinkVisitDTO{}, err
+	}
+	return toDTO(v), nil
+}
+
+/*Запись посещения через очередь асинхронной записи*/
+func (s *Service) Record(ctx context.Context, in CreateInput) error {
+	if s.recorder == nil {
+		_, err := s.Create(ctx, in)
+		return err
+	}
+
+	return s.recorder.Enqueue(s.toDomainInput(in))
+}
+
+/*Создание посещения с учётом лимита посещений ссылки*/
+func (s *Service) CreateWithinLimit(ctx context.Context, in CreateInput) (LinkVisitDTO, error) {
+	v, err := s.repo.CreateWithinLimit(ctx, s.toDomainInput(in))
 	if err != nil {
+		if errors.Is(err, domain.ErrLimitReached) {
+			return LinkVisitDTO{}, ErrLimitReached
+		}
 		return LinkVisitDTO{}, err
 	}
 	return toDTO(v), nil
 }
 
 /*Список посещений с range*/
-func (s *Service) ListWithRange(ctx context.Context, rng *link.Range) ([]LinkVisitDTO, error) {
-	visits, err := s.repo.ListWithRange(ctx, rng)
+func (s *Service) ListWithRange(ctx context.Context, rng *link.Range, f domain.Filter) ([]LinkVisitDTO, error) {
+	f.OwnerID = link.OwnerScope(ctx)
+	visits, err := s.repo.ListWithRange(ctx, rng, f)
 	if err != nil {
 		return nil, err
 	}
@@ -47,22 +71,108 @@ func (s *Service) ListWithRange(ctx context.Context, rng *link.Range) ([]LinkVis
 	return res, nil
 }
 
-/*Общее количество посещений*/
-func (s *Service) Count(ctx context.Context) (int64, error) {
-	return s.repo.Count(ctx)
+/*Страница посещений после курсора*/
+func (s *Service) ListAfter(ctx context.Context, cur *link.Cursor, f domain.Filter) ([]LinkVisitDTO, *link.Cursor, error) {
+	f.OwnerID = link.OwnerScope(ctx)
+	// запрашиваем на одну запись больше, чтобы узнать, есть ли следующая страница
+	visits, err := s.repo.ListAfter(ctx, &link.Cursor{AfterID: cur.AfterID, Limit: cur.Limit + 1}, f)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var next *link.Cursor
+	if len(visits) > cur.Limit {
+		visits = visits[:cur.Limit]
+		next = &link.Cursor{AfterID: visits[len(visits)-1].ID, Limit: cur.Limit}
+	}
+
+	res := make([]LinkVisitDTO, 0, len(visits))
+	for _, v := range visits {
+		res = append(res, toDTO(v))
+	}
+	return res, next, nil
+}
+
+/*Обход посещений по фильтру в порядке id без загрузки всего списка в память*/
+func (s *Service) Each(ctx context.Context, f domain.Filter, fn func(LinkVisitDTO) error) error {
+	f.OwnerID = link.OwnerScope(ctx)
+	return s.repo.Each(ctx, f, func(v entity.LinkVisit) error {
+		return fn(toDTO(v))
+	})
+}
+
+/*Количество посещений, подходящих под фильтр*/
+func (s *Service) Count(ctx context.Context, f domain.Filter) (int64, error) {
+	f.OwnerID = link.OwnerScope(ctx)
+	return s.repo.Count(ctx, f)
+}
+
+/*Метод проверки, что ссылка доступна пользователю из контекста (чужая или несуществующая — ErrLinkNotFound)*/
+func (s *Service) authorizeLink(ctx context.Context, id int64) error {
+	owner := link.OwnerScope(ctx)
+	if owner == 0 {
+		return nil
+	}
+	if s.links == nil {
+		return ErrLinkNotFound
+	}
+
+	n, err := s.links.Count(ctx, link.Filter{IDs: []int64{id}, OwnerID: owner})
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrLinkNotFound
+	}
+
+	return nil
 }
 
 func toDTO(v entity.LinkVisit) LinkVisitDTO {
 	return LinkVisitDTO{
-		ID:        v.ID,
-		LinkID:    v.LinkID,
-		CreatedAt: v.CreatedAt,
-		IP:        v.IP,
-		UserAgent: v.UserAgent,
-		Referer:   v.Referer,
-		Status:    v.Status,
+		ID:             v.ID,
+		LinkID:         v.LinkID,
+		CreatedAt:      v.CreatedAt,
+		IP:             v.IP,
+		UserAgent:      v.UserAgent,
+		Referer:        v.Referer,
+		Status:         v.Status,
+		Browser:        v.Browser,
+		BrowserVersion: v.BrowserVersion,
+		OS:             v.OS,
+		Device:         v.Device,
+		IsBot:          v.IsBot,
+		Country:        v.Country,
+		Region:         v.Region,
+		City:           v.City,
 	}
 }
 
-var _ UseCase = (*Service)(nil)
+/*Входные параметры репозитория: обогащение выполняется до обезличивания IP и пропускается при DNT/Sec-GPC*/
+func (s *Service) toDomainInput(in CreateInput) domain.CreateInput {
+	var agent domain.UserAgent
+	var geo domain.Location
+	if !in.DoNotTrack {
+		agent = domain.ParseUserAgent(in.UserAgent)
+		if s.geo != nil {
+			geo = s.geo.Locate(in.IP)
+		}
+	}
+	// решение о боте принимает обработчик запроса по списку из конфигурации, а не встроенные признаки разбора
+	agent.IsBot = in.IsBot
+	if in.IsBot {
+		agent.Device = ""
+	}
+
+	return domain.CreateInput{
+		LinkID:    in.LinkID,
+		IP:        s.privacy.anonymizeIP(in.IP, s.now()),
+		UserAgent: in.UserAgent,
+		Referer:   in.Referer,
+		Status:    in.Status,
+		Agent:     agent,
+		Geo:       geo,
+	}
+}
 
+var _ UseCase = (*Service)(nil)
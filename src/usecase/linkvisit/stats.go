@@ -0,0 +1,224 @@
+package linkvisitusecase
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"time"
+
+	domain "link-service/src/domain/linkvisit"
+)
+
+const (
+	/*Период статистики по умолчанию*/
+	defaultStatsPeriod = 30 * 24 * time.Hour
+	/*Количество записей в топах*/
+	statsTopLimit = 10
+	/*Максимум интервалов в ряду (защита от hour за несколько лет)*/
+	maxStatsBuckets = 5000
+)
+
+/*Статистика переходов по ссылке за период*/
+func (s *Service) Stats(ctx context.Context, in StatsInput) (StatsDTO, error) {
+	q, err := statsQuery(in, time.Now())
+	if err != nil {
+		return StatsDTO{}, err
+	}
+
+	// запросы статистики и дневной агрегации не знают о владельцах, поэтому доступ к ссылке проверяется здесь
+	if err := s.authorizeLink(ctx, q.LinkID); err != nil {
+		return StatsDTO{}, err
+	}
+
+	var state domain.RollupState
+	if q.Interval != domain.IntervalHour {
+		if state, err = s.repo.RollupState(ctx); err != nil {
+			return StatsDTO{}, err
+		}
+	}
+
+	// завершённые сутки до границы агрегации читаются из дневных счётчиков, остальное — из сырых посещений
+	rollupFrom, rollupTo := rollupRange(q, state)
+
+	var daily []domain.DailyCount
+	if rollupFrom.Before(rollupTo) {
+		if daily, err = s.repo.ListDaily(ctx, q.LinkID, rollupFrom, rollupTo); err != nil {
+			return StatsDTO{}, err
+		}
+	}
+
+	// сырые посещения читаются только после границы агрегации (обычно — за текущие сутки)
+	var stats domain.Stats
+	if raw := q; rollupTo.Before(q.To) {
+		raw.From = rollupTo
+		if stats, err = s.repo.Stats(ctx, raw); err != nil {
+			return StatsDTO{}, err
+		}
+	}
+
+	if len(daily) > 0 {
+		addDaily(&stats, q, daily)
+	}
+
+	hosts := stats.RefererHosts
+	if len(hosts) > q.Top {
+		hosts = hosts[:q.Top]
+	}
+
+	res := StatsDTO{
+		LinkID:       q.LinkID,
+		From:         q.From,
+		To:           q.To,
+		RawFrom:      rollupTo,
+		Interval:     string(q.Interval),
+		Total:        stats.Total,
+		Bots:         stats.Bots,
+		Series:       fillSeries(q, stats.Series),
+		Statuses:     make([]StatusCountDTO, 0, len(stats.Statuses)),
+		RefererHosts: toValueCountDTOs(hosts),
+	}
+
+	// часть периода взята из агрегации, где этих разбивок нет: отдаём null, а не неполные данные
+	if len(daily) == 0 {
+		res.TopReferers = toValueCountDTOs(stats.TopReferers)
+		res.TopUserAgents = toValueCountDTOs(stats.TopUserAgents)
+		res.Browsers = toValueCountDTOs(stats.Browsers)
+		res.OperatingSystems = toValueCountDTOs(stats.OperatingSystems)
+		res.Devices = toValueCountDTOs(stats.Devices)
+		res.Countries = toValueCountDTOs(stats.Countries)
+	}
+
+	for _, st := range stats.Statuses {
+		res.Statuses = append(res.Statuses, StatusCountDTO{Status: st.Status, Count: st.Count})
+	}
+
+	return res, nil
+}
+
+// rollupRange возвращает часть периода, которая берётся из дневной агрегации: [from, to).
+//
+// Агрегация хранит целые сутки, поэтому для day и week начало этой части выравнивается на начало суток.
+// Почасовая статистика всегда строится по сырым посещениям, тогда from == to == q.From.
+func rollupRange(q domain.StatsQuery, state domain.RollupState) (time.Time, time.Time) {
+	if q.Interval == domain.IntervalHour {
+		return q.From, q.From
+	}
+
+	from := domain.IntervalDay.Truncate(q.From)
+	to := domain.IntervalDay.Truncate(q.To)
+	if state.RolledUntil.Before(to) {
+		to = state.RolledUntil
+	}
+	if !from.Before(to) {
+		return q.From, q.From
+	}
+
+	return from, to
+}
+
+/*Добавление дневных счётчиков к статистике сырых посещений*/
+func addDaily(stats *domain.Stats, q domain.StatsQuery, daily []domain.DailyCount) {
+	buckets := map[int64]int64{}
+	for _, b := range stats.Series {
+		buckets[b.Start.Unix()] += b.Clicks
+	}
+	statuses := map[int]int64{}
+	for _, st := range stats.Statuses {
+		statuses[st.Status] += st.Count
+	}
+	hosts := map[string]int64{}
+	for _, h := range stats.RefererHosts {
+		hosts[h.Value] += h.Count
+	}
+
+	for _, d := range daily {
+		if d.IsBot {
+			stats.Bots += d.Visits
+			if !q.IncludeBots {
+				continue
+			}
+		}
+
+		stats.Total += d.Visits
+		buckets[q.Interval.Truncate(d.Day).Unix()] += d.Visits
+		statuses[d.Status] += d.Visits
+		hosts[d.RefererHost] += d.Visits
+	}
+
+	stats.Series = stats.Series[:0]
+	for start, clicks := range buckets {
+		stats.Series = append(stats.Series, domain.Bucket{Start: time.Unix(start, 0).UTC(), Clicks: clicks})
+	}
+	slices.SortFunc(stats.Series, func(a, b domain.Bucket) int { return a.Start.Compare(b.Start) })
+
+	stats.Statuses = stats.Statuses[:0]
+	for status, count := range statuses {
+		stats.Statuses = append(stats.Statuses, domain.StatusCount{Status: status, Count: count})
+	}
+	slices.SortFunc(stats.Statuses, func(a, b domain.StatusCount) int {
+		if a.Count != b.Count {
+			return cmp.Compare(b.Count, a.Count)
+		}
+		return cmp.Compare(a.Status, b.Status)
+	})
+
+	stats.RefererHosts = domain.SortValueCounts(hosts)
+}
+
+/*Подстановка значений по умолчанию и проверка параметров статистики*/
+func statsQuery(in StatsInput, now time.Time) (domain.StatsQuery, error) {
+	q := domain.StatsQuery{
+		LinkID:      in.LinkID,
+		From:        in.From.UTC(),
+		To:          in.To.UTC(),
+		Interval:    domain.Interval(in.Interval),
+		Top:         statsTopLimit,
+		IncludeBots: in.IncludeBots,
+	}
+
+	if q.Interval == "" {
+		q.Interval = domain.IntervalDay
+	}
+	if in.To.IsZero() {
+		q.To = now.UTC()
+	}
+	if in.From.IsZero() {
+		q.From = q.To.Add(-defaultStatsPeriod)
+	}
+
+	if !q.Interval.IsValid() || !q.From.Before(q.To) {
+		return domain.StatsQuery{}, ErrInvalidStatsQuery
+	}
+
+	buckets := 0
+	for start := q.Interval.Truncate(q.From); start.Before(q.To); start = q.Interval.Next(start) {
+		if buckets++; buckets > maxStatsBuckets {
+			return domain.StatsQuery{}, ErrInvalidStatsQuery
+		}
+	}
+
+	return q, nil
+}
+
+/*Ряд без пропусков: интервалы без переходов заполняются нулями*/
+func fillSeries(q domain.StatsQuery, series []domain.Bucket) []BucketDTO {
+	clicks := make(map[int64]int64, len(series))
+	for _, b := range series {
+		clicks[b.Start.Unix()] = b.Clicks
+	}
+
+	res := []BucketDTO{}
+	for start := q.Interval.Truncate(q.From); start.Before(q.To); start = q.Interval.Next(start) {
+		res = append(res, BucketDTO{Start: start, Clicks: clicks[start.Unix()]})
+	}
+
+	return res
+}
+
+func toValueCountDTOs(in []domain.ValueCount) []ValueCountDTO {
+	res := make([]ValueCountDTO, 0, len(in))
+	for _, v := range in {
+		res = append(res, ValueCountDTO{Value: v.Value, Count: v.Count})
+	}
+	return res
+}
@@ -0,0 +1,121 @@
+package linkvisitusecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"link-service/src/domain/link"
+	domain "link-service/src/domain/linkvisit"
+
+	"github.com/go-playground/assert/v2"
+)
+
+/*Контекст фоновой задачи: доступ ко всем ссылкам*/
+var systemCtx = link.WithAccess(context.Background(), link.SystemAccess)
+
+type statsRepo struct {
+	domain.Repository
+
+	query domain.StatsQuery
+	stats domain.Stats
+}
+
+func (r *statsRepo) Stats(ctx context.Context, q domain.StatsQuery) (domain.Stats, error) {
+	r.query = q
+	return r.stats, nil
+}
+
+func TestStatsFillsEmptyBuckets(t *testing.T) {
+	from := time.Date(2025, 3, 1, 10, 30, 0, 0, time.UTC)
+	repo := &statsRepo{stats: domain.Stats{
+		Total: 4,
+		Series: []domain.Bucket{
+			{Start: time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC), Clicks: 1},
+			{Start: time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC), Clicks: 3},
+		},
+		Statuses: []domain.StatusCount{{Status: 302, Count: 4}},
+	}}
+
+	res, err := NewService(repo, nil, nil, nil, Privacy{}).Stats(systemCtx, StatsInput{
+		LinkID:   7,
+		From:     from,
+		To:       from.Add(3 * time.Hour),
+		Interval: "hour",
+	})
+	assert.Equal(t, nil, err)
+
+	assert.Equal(t, int64(7), repo.query.LinkID)
+	assert.Equal(t, statsTopLimit, repo.query.Top)
+	assert.Equal(t, []BucketDTO{
+		{Start: time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC), Clicks: 1},
+		{Start: time.Date(2025, 3, 1, 11, 0, 0, 0, time.UTC), Clicks: 0},
+		{Start: time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC), Clicks: 3},
+		{Start: time.Date(2025, 3, 1, 13, 0, 0, 0, time.UTC), Clicks: 0},
+	}, res.Series)
+	assert.Equal(t, []StatusCountDTO{{Status: 302, Count: 4}}, res.Statuses)
+	assert.Equal(t, []ValueCountDTO{}, res.TopReferers)
+}
+
+func TestStatsQueryDefaultsAndValidation(t *testing.T) {
+	now := time.Date(2025, 3, 31, 15, 0, 0, 0, time.UTC)
+
+	q, err := statsQuery(StatsInput{LinkID: 1}, now)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, domain.IntervalDay, q.Interval)
+	assert.Equal(t, now, q.To)
+	assert.Equal(t, now.Add(-defaultStatsPeriod), q.From)
+
+	_, err = statsQuery(StatsInput{LinkID: 1, Interval: "month"}, now)
+	assert.Equal(t, ErrInvalidStatsQuery, err)
+
+	_, err = statsQuery(StatsInput{LinkID: 1, From: now, To: now}, now)
+	assert.Equal(t, ErrInvalidStatsQuery, err)
+
+	_, err = statsQuery(StatsInput{LinkID: 1, From: now.AddDate(-2, 0, 0), Interval: "hour"}, now)
+	assert.Equal(t, ErrInvalidStatsQuery, err)
+
+	assert.Equal(t, time.Date(2025, 3, 24, 0, 0, 0, 0, time.UTC), domain.IntervalWeek.Truncate(time.Date(2025, 3, 30, 23, 0, 0, 0, time.UTC)))
+}
+
+/*Ссылки двух пользователей: 1 принадлежит 10, 2 — 20*/
+type ownedLinks struct {
+	link.Repository
+}
+
+func (ownedLinks) Count(ctx context.Context, f link.Filter) (int64, error) {
+	owners := map[int64]int64{1: 10, 2: 20}
+	var n int64
+	for _, id := range f.IDs {
+		if owner, ok := owners[id]; ok && (f.OwnerID == 0 || f.OwnerID == owner) {
+			n++
+		}
+	}
+	return n, nil
+}
+
+func TestStatsChecksLinkOwner(t *testing.T) {
+	repo := &statsRepo{stats: domain.Stats{Total: 5}}
+	s := NewService(repo, ownedLinks{}, nil, nil, Privacy{})
+	member := link.WithAccess(context.Background(), link.Access{UserID: 10})
+	in := StatsInput{LinkID: 2, Interval: "hour", From: time.Now().Add(-time.Hour)}
+
+	// участник не видит статистику чужой ссылки, и до запросов к посещениям дело не доходит
+	_, err := s.Stats(member, in)
+	assert.Equal(t, ErrLinkNotFound, err)
+	assert.Equal(t, int64(0), repo.query.LinkID)
+
+	// без доступа в контексте статистика закрыта
+	_, err = s.Stats(context.Background(), in)
+	assert.Equal(t, ErrLinkNotFound, err)
+
+	in.LinkID = 1
+	res, err := s.Stats(member, in)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, int64(5), res.Total)
+
+	in.LinkID = 2
+	res, err = s.Stats(systemCtx, in)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, int64(5), res.Total)
+}
@@ -2,26 +2,48 @@ package linkvisitusecase
 
 import (
 	"context"
+	"time"
 
 	"link-service/src/domain/link"
+	domain "link-service/src/domain/linkvisit"
 )
 
 /*Интерфейс для работы с посещениями ссылок*/
 type UseCase interface {
 	/*Создание посещения*/
 	Create(ctx context.Context, in CreateInput) (LinkVisitDTO, error)
-	/*Список посещений с range*/
-	ListWithRange(ctx context.Context, rng *link.Range) ([]LinkVisitDTO, error)
-	/*Общее количество посещений*/
-	Count(ctx context.Context) (int64, error)
+	/*Запись посещения без ожидания записи в хранилище*/
+	Record(ctx context.Context, in CreateInput) error
+	/*Создание посещения с учётом лимита посещений ссылки*/
+	CreateWithinLimit(ctx context.Context, in CreateInput) (LinkVisitDTO, error)
+	/*Список посещений с range и фильтром*/
+	ListWithRange(ctx context.Context, rng *link.Range, f domain.Filter) ([]LinkVisitDTO, error)
+	/*Страница посещений после курсора и курсор следующей страницы (nil — страница последняя)*/
+	ListAfter(ctx context.Context, cur *link.Cursor, f domain.Filter) ([]LinkVisitDTO, *link.Cursor, error)
+	/*Обход посещений по фильтру в порядке id без загрузки всего списка в память*/
+	Each(ctx context.Context, f domain.Filter, fn func(LinkVisitDTO) error) error
+	/*Количество посещений, подходящих под фильтр*/
+	Count(ctx context.Context, f domain.Filter) (int64, error)
+	/*Статистика переходов по ссылке за период*/
+	Stats(ctx context.Context, in StatsInput) (StatsDTO, error)
 }
 
 /*Входные параметры для создания посещения*/
 type CreateInput struct {
-	LinkID    int64
-	IP        string
-	UserAgent string
-	Referer   string
-	Status    int
+	LinkID     int64
+	IP         string
+	UserAgent  string
+	Referer    string
+	Status     int
+	IsBot      bool /*Бот по данным запроса (шаблоны User-Agent из конфигурации, заголовки превью)*/
+	DoNotTrack bool /*Клиент передал DNT или Sec-GPC: User-Agent и местоположение не определяются*/
 }
 
+/*Входные параметры статистики ссылки (нулевые значения — по умолчанию)*/
+type StatsInput struct {
+	LinkID      int64
+	From        time.Time /*Начало периода (по умолчанию — To минус 30 дней)*/
+	To          time.Time /*Конец периода, не включая (по умолчанию — сейчас)*/
+	Interval    string    /*hour, day или week (по умолчанию — day)*/
+	IncludeBots bool      /*Учитывать посещения ботов*/
+}
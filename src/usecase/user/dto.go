@@ -0,0 +1,18 @@
+package userusecase
+
+import "time"
+
+/*DTO для работы с пользователями*/
+type UserDTO struct {
+	ID        int64     /*Идентификатор пользователя*/
+	Username  string    /*Имя для входа*/
+	Role      string    /*Роль (admin или member)*/
+	CreatedAt time.Time /*Дата создания*/
+}
+
+/*DTO сессии, созданной при входе*/
+type SessionDTO struct {
+	Token     string    /*Токен сессии (больше нигде не отдаётся)*/
+	ExpiresAt time.Time /*Дата истечения сессии*/
+	User      UserDTO   /*Вошедший пользователь*/
+}
@@ -0,0 +1,14 @@
+package userusecase
+
+import "errors"
+
+var (
+	/*Не найден*/
+	ErrNotFound = errors.New("user not found")
+	/*Неверное имя пользователя или пароль*/
+	ErrInvalidCredentials = errors.New("invalid username or password")
+	/*Конфликт*/
+	ErrUsernameConflict = errors.New("username already exists")
+	/*Невалидный ввод*/
+	ErrInvalidInput = errors.New("invalid input")
+)
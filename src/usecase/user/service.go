@@ -0,0 +1,166 @@
+package userusecase
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+
+	"link-service/src/domain/entity"
+	domain "link-service/src/domain/user"
+)
+
+const (
+	/*Максимальная длина имени пользователя*/
+	maxUsernameLen = 64
+	/*Минимальная длина пароля*/
+	minPasswordLen = 8
+	/*Максимальная длина пароля (bcrypt учитывает только первые 72 байта)*/
+	maxPasswordLen = 72
+)
+
+var (
+	/*Хеш для сравнения, когда пользователя нет: вход занимает столько же времени и не выдаёт существующие имена*/
+	dummyHash     []byte
+	dummyHashOnce sync.Once
+)
+
+/*Сервис для работы с пользователями*/
+type Service struct {
+	repo       domain.Repository
+	sessionTTL time.Duration
+	now        func() time.Time
+}
+
+/*Метод создания нового сервиса (sessionTTL — срок действия сессии после входа)*/
+func NewService(repo domain.Repository, sessionTTL time.Duration) *Service {
+	return &Service{repo: repo, sessionTTL: sessionTTL, now: time.Now}
+}
+
+/*Метод входа по имени и паролю*/
+func (s *Service) Login(ctx context.Context, username, password string) (SessionDTO, error) {
+	u, err := s.repo.GetByUsername(ctx, strings.TrimSpace(username))
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			_ = bcrypt.CompareHashAndPassword(getDummyHash(), []byte(password))
+			return SessionDTO{}, ErrInvalidCredentials
+		}
+		return SessionDTO{}, err
+	}
+
+	if bcrypt.CompareHashAndPassword([]byte(u.PasswordHash), []byte(password)) != nil {
+		return SessionDTO{}, ErrInvalidCredentials
+	}
+
+	token, err := generateToken()
+	if err != nil {
+		return SessionDTO{}, err
+	}
+
+	expiresAt := s.now().Add(s.sessionTTL).UTC()
+	if err := s.repo.CreateSession(ctx, u.ID, domain.HashToken(token), expiresAt); err != nil {
+		return SessionDTO{}, err
+	}
+
+	// истёкшие сессии чистим при входе: отдельная фоновая задача для них не нужна
+	if _, err := s.repo.DeleteExpiredSessions(ctx); err != nil {
+		log.Printf("users: delete expired sessions: %v", err)
+	}
+
+	return SessionDTO{Token: token, ExpiresAt: expiresAt, User: toDTO(u)}, nil
+}
+
+/*Метод выхода*/
+func (s *Service) Logout(ctx context.Context, token string) error {
+	return s.repo.DeleteSession(ctx, domain.HashToken(token))
+}
+
+/*Метод получения списка пользователей*/
+func (s *Service) List(ctx context.Context) ([]UserDTO, error) {
+	users, err := s.repo.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]UserDTO, 0, len(users))
+	for _, u := range users {
+		res = append(res, toDTO(u))
+	}
+
+	return res, nil
+}
+
+/*Метод создания пользователя*/
+func (s *Service) Create(ctx context.Context, in CreateInput) (UserDTO, error) {
+	username := strings.TrimSpace(in.Username)
+	if username == "" || len(username) > maxUsernameLen {
+		return UserDTO{}, ErrInvalidInput
+	}
+
+	if len(in.Password) < minPasswordLen || len(in.Password) > maxPasswordLen {
+		return UserDTO{}, ErrInvalidInput
+	}
+
+	role := in.Role
+	if role == "" {
+		role = domain.RoleMember
+	}
+	if !domain.IsValidRole(role) {
+		return UserDTO{}, ErrInvalidInput
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(in.Password), bcrypt.DefaultCost)
+	if err != nil {
+		return UserDTO{}, err
+	}
+
+	u, err := s.repo.Create(ctx, domain.CreateInput{
+		Username:     username,
+		PasswordHash: string(hash),
+		Role:         role,
+	})
+	if err != nil {
+		if errors.Is(err, domain.ErrUsernameConflict) {
+			return UserDTO{}, ErrUsernameConflict
+		}
+		return UserDTO{}, err
+	}
+
+	return toDTO(u), nil
+}
+
+/*Метод генерации токена сессии: префикс и 32 случайных байта*/
+func generateToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return domain.SessionTokenPrefix + base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+/*Метод получения хеша для сравнения при неизвестном пользователе (считается один раз)*/
+func getDummyHash() []byte {
+	dummyHashOnce.Do(func() {
+		dummyHash, _ = bcrypt.GenerateFromPassword([]byte("dummy-password"), bcrypt.DefaultCost)
+	})
+	return dummyHash
+}
+
+/*Метод преобразования из entity.User в UserDTO*/
+func toDTO(u entity.User) UserDTO {
+	return UserDTO{
+		ID:        u.ID,
+		Username:  u.Username,
+		Role:      u.Role,
+		CreatedAt: u.CreatedAt,
+	}
+}
+
+var _ UseCase = (*Service)(nil)
@@ -0,0 +1,22 @@
+package userusecase
+
+import "context"
+
+/*Интерфейс для работы с пользователями и их сессиями*/
+type UseCase interface {
+	/*Метод входа по имени и паролю: создаёт сессию (ErrInvalidCredentials — неверное имя или пароль)*/
+	Login(ctx context.Context, username, password string) (SessionDTO, error)
+	/*Метод выхода: удаляет сессию с токеном token*/
+	Logout(ctx context.Context, token string) error
+	/*Метод получения списка пользователей*/
+	List(ctx context.Context) ([]UserDTO, error)
+	/*Метод создания пользователя*/
+	Create(ctx context.Context, in CreateInput) (UserDTO, error)
+}
+
+/*DTO для создания пользователя*/
+type CreateInput struct {
+	Username string /*Имя для входа*/
+	Password string /*Пароль в открытом виде*/
+	Role     string /*Роль (пусто — member)*/
+}